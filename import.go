@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+
+	"github.com/rs/zerolog/log"
+
+	"internal-transfers/internal/config"
+	"internal-transfers/internal/domain"
+	"internal-transfers/internal/importer"
+	"internal-transfers/internal/repository"
+	"internal-transfers/internal/service"
+)
+
+// cliCaller is the caller of commands run from the command line, for MINT_ALLOWED_CALLERS
+const cliCaller = "cli"
+
+const importUsage = "usage: import [-dry-run] [-format csv|jsonl] <file|->"
+
+// runImport creates accounts from a CSV or JSONL file, printing a report of the rows that were not created
+func runImport(cfg config.Config, args []string) {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "validate the file and report the outcome without creating accounts")
+	formatName := flags.String("format", "", "csv or jsonl; inferred from the file extension when omitted")
+	_ = flags.Parse(args)
+	if flags.NArg() != 1 {
+		log.Fatal().Msg(importUsage)
+	}
+	path := flags.Arg(0)
+	// in-memory storage lives in the server process, so there is nothing for the command to import into
+	if cfg.Storage != config.StoragePostgres {
+		log.Fatal().Str("storage", cfg.Storage).Msg("import requires postgres storage")
+	}
+
+	if *formatName == "" {
+		*formatName = strings.TrimPrefix(filepath.Ext(path), ".")
+	}
+	format, err := importer.ParseFormat(*formatName)
+	if err != nil {
+		log.Fatal().Err(err).Msg(importUsage)
+	}
+
+	var in io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to open import file")
+		}
+		defer f.Close()
+		in = f
+	}
+	records, err := importer.Parse(in, format)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to read import file")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	db, err := repository.InitDB(ctx, cfg.DB)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to initialize db")
+	}
+	defer db.Close()
+
+	accounts := repository.NewAccountRepository(db)
+	if err := ensureSystemAccounts(ctx, accounts, cfg); err != nil {
+		log.Fatal().Err(err).Msg("failed to initialize db")
+	}
+
+	// the command is run by an operator, who is named as the cli caller when minting
+	ctx = domain.WithCaller(ctx, cliCaller)
+	svc := service.NewAccountService(accounts, repository.NewUnitOfWork(db, txOptions(cfg.DB)), mintPolicy(cfg.Mint), accountIDPolicy(cfg.Accounts))
+	report, err := svc.ImportAccounts(ctx, records, *dryRun)
+	if err != nil {
+		log.Fatal().Err(err).Msg("import failed")
+	}
+	if err := printImportReport(report); err != nil {
+		log.Fatal().Err(err).Msg("failed to print import report")
+	}
+}
+
+func printImportReport(report *service.ImportReport) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "LINE\tACCOUNT ID\tSTATUS\tERROR")
+	for _, row := range report.Rows {
+		if row.Status != service.ImportCreated {
+			fmt.Fprintf(w, "%d\t%d\t%s\t%s\n", row.Line, row.AccountID, row.Status, row.Error)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	verb := "created"
+	if report.DryRun {
+		verb = "would create"
+	}
+	_, err := fmt.Fprintf(os.Stdout, "%s %d accounts, %d duplicate, %d invalid\n", verb, report.Created, report.Duplicate, report.Invalid)
+	return err
+}
@@ -1,14 +1,23 @@
 package handler
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/rs/zerolog/log"
+	"math"
 	"net/http"
+	"net/url"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"internal-transfers/internal/api/types"
 	"internal-transfers/internal/domain"
+	"internal-transfers/internal/model"
+	"internal-transfers/internal/repository"
 	"internal-transfers/internal/service"
 )
 
@@ -20,58 +29,332 @@ func NewAccountHandler(svc service.AccountService) *AccountHandler {
 	return &AccountHandler{accountService: svc}
 }
 
+// CreateAccount opens an account, with an id allocated by the server unless account_id is given, and answers
+// with the account and its location
 func (h *AccountHandler) CreateAccount(w http.ResponseWriter, r *http.Request) {
 	var req types.CreateAccountRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		log.Error().Err(err).Msg("error decoding body")
+		log.Ctx(r.Context()).Error().Err(err).Msg("error decoding body")
 		types.WriteResponseError(w, http.StatusBadRequest, "invalid request body")
 		return
 	}
 	if req.InitialBalance < 0 {
-		log.Warn().Msg("attempt to create account with negative balance")
+		log.Ctx(r.Context()).Warn().Msg("attempt to create account with negative balance")
 		types.WriteResponseError(w, http.StatusBadRequest, "initial balance cannot be negative")
 		return
 	}
 
-	err := h.accountService.CreateAccount(r.Context(), req.AccountID, float64(req.InitialBalance))
+	details := service.AccountDetails{Name: req.Name, Owner: req.Owner, Labels: req.Labels}
+	acc, err := h.accountService.CreateAccount(r.Context(), req.AccountID, float64(req.InitialBalance), model.AccountType(req.Type), details)
 	if err != nil {
+		if errors.Is(err, domain.ErrInvalidAccountType) || errors.Is(err, domain.ErrInvalidAccount) {
+			types.WriteResponseError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		if errors.Is(err, domain.ErrAccountDuplicate) {
-			log.Warn().Err(err).Msg("attempt to create account that already exists")
+			log.Ctx(r.Context()).Warn().Err(err).Msg("attempt to create account that already exists")
 			types.WriteResponseError(w, http.StatusConflict, "account has already been created")
 			return
 		}
-		log.Error().Err(err).Msg("error creating account")
+		if errors.Is(err, domain.ErrMintNotAllowed) {
+			log.Ctx(r.Context()).Warn().Err(err).Msg("caller not allowed to mint")
+			types.WriteResponseError(w, http.StatusForbidden, "caller is not allowed to fund new accounts")
+			return
+		}
+		log.Ctx(r.Context()).Error().Err(err).Msg("error creating account")
 		types.WriteResponseError(w, http.StatusInternalServerError, "failed to create account")
 		return
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	w.Header().Set("Location", "/accounts/"+strconv.FormatInt(acc.AccountID, 10))
+	w.Header().Set("ETag", accountETag(acc))
+	types.WriteResponse(w, http.StatusCreated, "created", accountResponse(acc))
 }
 
 func (h *AccountHandler) GetAccount(w http.ResponseWriter, r *http.Request) {
-	accountIDStr := r.URL.Path[len("/accounts/"):]
-	accountID, err := strconv.ParseInt(accountIDStr, 10, 64)
+	accountID, err := parseAccountID(r.PathValue("id"))
 	if err != nil {
-		log.Error().Err(err).Msg("failed to parse account id")
-		types.WriteResponseError(w, http.StatusBadRequest, "invalid account id")
+		log.Ctx(r.Context()).Error().Err(err).Msg("failed to parse account id")
+		types.WriteResponseError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 	acc, err := h.accountService.GetAccount(r.Context(), accountID)
 	if err != nil {
 		if errors.Is(err, domain.ErrAccountNotFound) {
-			log.Warn().Msg("account not found")
+			log.Ctx(r.Context()).Warn().Msg("account not found")
 			types.WriteResponseError(w, http.StatusNotFound, "account not found")
 			return
 		}
-		log.Error().Err(err).Msg("failed to get account")
+		log.Ctx(r.Context()).Error().Err(err).Msg("failed to get account")
 		types.WriteResponseError(w, http.StatusInternalServerError, "failed to get account")
 		return
 	}
 
-	resp := types.AccountResponse{
-		AccountID: acc.AccountID,
-		Balance:   acc.Balance,
+	resp := accountResponse(acc)
+
+	w.Header().Set("ETag", accountETag(acc))
+	types.WriteResponseSuccess(w, resp)
+}
+
+// UpdateAccount changes the name, owner, labels and status of an account. With If-Match set to the ETag of an
+// earlier read the update only applies if the account has not changed since.
+func (h *AccountHandler) UpdateAccount(w http.ResponseWriter, r *http.Request) {
+	accountID, err := parseAccountID(r.PathValue("id"))
+	if err != nil {
+		types.WriteResponseError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	version, err := parseIfMatch(r.Header.Get("If-Match"))
+	if err != nil {
+		types.WriteResponseError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var req types.UpdateAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		types.WriteResponseError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	update := service.AccountUpdate{Name: req.Name, Owner: req.Owner, Labels: req.Labels}
+	if req.Status != nil {
+		status := model.AccountStatus(*req.Status)
+		update.Status = &status
+	}
+	acc, err := h.accountService.UpdateAccount(r.Context(), accountID, update, version)
+	switch {
+	case err == nil:
+	case errors.Is(err, domain.ErrAccountNotFound):
+		types.WriteResponseError(w, http.StatusNotFound, "account not found")
+		return
+	case errors.Is(err, domain.ErrInvalidAccount):
+		types.WriteResponseError(w, http.StatusBadRequest, err.Error())
+		return
+	case errors.Is(err, domain.ErrAccountVersionMismatch):
+		types.WriteResponseError(w, http.StatusPreconditionFailed, "account has changed since the version in If-Match")
+		return
+	case errors.Is(err, domain.ErrConcurrentUpdate):
+		log.Ctx(r.Context()).Warn().Err(err).Msg("account update aborted by concurrent updates")
+		w.Header().Set("Retry-After", retryAfterSeconds)
+		types.WriteResponseError(w, http.StatusServiceUnavailable, "too many concurrent updates to the account, retry later")
+		return
+	default:
+		log.Ctx(r.Context()).Error().Err(err).Msg("failed to update account")
+		types.WriteResponseError(w, http.StatusInternalServerError, "failed to update account")
+		return
+	}
+
+	w.Header().Set("ETag", accountETag(acc))
+	types.WriteResponseSuccess(w, accountResponse(acc))
+}
+
+// errInvalidAccountID is returned for account ids that are not numbers or that no account can have
+var errInvalidAccountID = errors.New("invalid account id")
+
+// parseAccountID parses an account id, rejecting allocated ids whose check digit is wrong with the other
+// malformed ids rather than looking them up
+func parseAccountID(v string) (int64, error) {
+	id, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return 0, errInvalidAccountID
+	}
+	if err := checkAccountID(id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// checkAccountID returns an error for an id above model.MaxCallerAccountID whose check digit is wrong
+func checkAccountID(id int64) error {
+	if !model.WellFormedAccountID(id) {
+		return fmt.Errorf("%w: %d has a wrong check digit", errInvalidAccountID, id)
 	}
+	return nil
+}
 
+// accountETag identifies the version of an account's type and details. It is weak because balance changes keep
+// the same ETag while the balance in the body differs.
+func accountETag(acc *model.Account) string {
+	return `W/"` + strconv.FormatInt(acc.Version, 10) + `"`
+}
+
+// parseIfMatch returns the version an If-Match header requires, 0 when any version will do. The ETags are weak, so
+// the comparison is too: W/"3" and "3" both require version 3.
+func parseIfMatch(v string) (int64, error) {
+	if v == "" || v == "*" {
+		return 0, nil
+	}
+	quoted, ok := strings.CutPrefix(strings.TrimPrefix(v, "W/"), `"`)
+	if ok {
+		quoted, ok = strings.CutSuffix(quoted, `"`)
+	}
+	version, err := strconv.ParseInt(quoted, 10, 64)
+	if !ok || err != nil || version <= 0 {
+		return 0, errors.New("invalid If-Match, expected the ETag of the account")
+	}
+	return version, nil
+}
+
+// Limits on the page size of ListAccounts
+const (
+	defaultAccountListLimit = 100
+	maxAccountListLimit     = 1000
+)
+
+// ListAccounts returns a page of accounts, by default in ascending id order, optionally filtered by type,
+// status, owner, balance and creation time
+func (h *AccountHandler) ListAccounts(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseAccountFilter(r.URL.Query())
+	if err != nil {
+		types.WriteResponseError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	accounts, err := h.accountService.ListAccounts(r.Context(), filter)
+	if err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("failed to list accounts")
+		types.WriteResponseError(w, http.StatusInternalServerError, "failed to list accounts")
+		return
+	}
+	resp := types.ListAccountsResponse{Accounts: make([]types.AccountResponse, 0, len(accounts))}
+	for _, acc := range accounts {
+		resp.Accounts = append(resp.Accounts, accountResponse(acc))
+	}
+	if len(accounts) == filter.Limit {
+		resp.NextCursor = encodeAccountCursor(filter, accounts[len(accounts)-1])
+	}
 	types.WriteResponseSuccess(w, resp)
 }
+
+// accountSorts are the values of the sort parameter, each optionally prefixed with - for descending order
+var accountSorts = []repository.AccountSort{
+	repository.AccountSortID,
+	repository.AccountSortBalance,
+	repository.AccountSortCreatedAt,
+}
+
+func parseAccountFilter(q url.Values) (repository.AccountFilter, error) {
+	filter := repository.AccountFilter{Limit: defaultAccountListLimit, Owner: q.Get("owner")}
+	if v := q.Get("type"); v != "" {
+		for _, t := range strings.Split(v, ",") {
+			accountType := model.AccountType(t)
+			if _, ok := accountType.Rule(); !ok {
+				return filter, fmt.Errorf("unknown account type %q", t)
+			}
+			filter.Types = append(filter.Types, accountType)
+		}
+	}
+	if v := q.Get("status"); v != "" {
+		for _, s := range strings.Split(v, ",") {
+			status := model.AccountStatus(s)
+			if !slices.Contains(model.AccountStatuses, status) {
+				return filter, fmt.Errorf("unknown account status %q", s)
+			}
+			filter.Statuses = append(filter.Statuses, status)
+		}
+	}
+	for _, p := range []struct {
+		name string
+		dst  **float64
+	}{{"min_balance", &filter.MinBalance}, {"max_balance", &filter.MaxBalance}} {
+		if v := q.Get(p.name); v != "" {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+				return filter, fmt.Errorf("invalid %s", p.name)
+			}
+			*p.dst = &f
+		}
+	}
+	for _, p := range []struct {
+		name string
+		dst  *time.Time
+	}{{"created_from", &filter.CreatedFrom}, {"created_to", &filter.CreatedTo}} {
+		if v := q.Get(p.name); v != "" {
+			t, err := parseQueryTime(v)
+			if err != nil {
+				return filter, fmt.Errorf("invalid %s, expected a date or RFC 3339 timestamp", p.name)
+			}
+			*p.dst = t
+		}
+	}
+	if v := q.Get("sort"); v != "" {
+		name, desc := strings.CutPrefix(v, "-")
+		sort := repository.AccountSort(name)
+		if !slices.Contains(accountSorts, sort) {
+			return filter, fmt.Errorf("invalid sort, expected one of id, balance or created_at, optionally prefixed with -")
+		}
+		filter.Sort, filter.Desc = sort, desc
+	}
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > maxAccountListLimit {
+			return filter, fmt.Errorf("invalid limit, expected 1 to %d", maxAccountListLimit)
+		}
+		filter.Limit = n
+	}
+	if v := q.Get("cursor"); v != "" {
+		after, err := decodeAccountCursor(filter, v)
+		if err != nil {
+			return filter, err
+		}
+		filter.After = after
+	}
+	return filter, nil
+}
+
+// accountCursor is the content of the opaque cursor of an account listing. It records the order of the
+// listing so that a cursor cannot be resumed in another order, where it would skip or repeat accounts.
+type accountCursor struct {
+	Sort      repository.AccountSort `json:"s,omitempty"`
+	Desc      bool                   `json:"d,omitempty"`
+	AccountID int64                  `json:"id"`
+	Balance   float64                `json:"b,omitempty"`
+	CreatedAt *time.Time             `json:"c,omitempty"`
+}
+
+func encodeAccountCursor(filter repository.AccountFilter, last *model.Account) string {
+	c := accountCursor{Sort: filter.Sort, Desc: filter.Desc, AccountID: last.AccountID}
+	switch filter.Sort {
+	case repository.AccountSortBalance:
+		c.Balance = last.Balance
+	case repository.AccountSortCreatedAt:
+		c.CreatedAt = &last.CreatedAt
+	}
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeAccountCursor(filter repository.AccountFilter, v string) (*repository.AccountCursor, error) {
+	var c accountCursor
+	b, err := base64.RawURLEncoding.DecodeString(v)
+	if err == nil {
+		err = json.Unmarshal(b, &c)
+	}
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	if c.Sort != filter.Sort || c.Desc != filter.Desc {
+		return nil, errors.New("cursor belongs to a listing in another order")
+	}
+	after := &repository.AccountCursor{AccountID: c.AccountID, Balance: c.Balance}
+	if c.CreatedAt != nil {
+		after.CreatedAt = *c.CreatedAt
+	}
+	return after, nil
+}
+
+func accountResponse(acc *model.Account) types.AccountResponse {
+	rule, _ := acc.Type.Rule()
+	return types.AccountResponse{
+		AccountID:     acc.AccountID,
+		Balance:       acc.Balance,
+		Type:          string(acc.Type),
+		NormalBalance: string(rule.NormalBalance),
+		Status:        string(acc.Status),
+		Name:          acc.Name,
+		Owner:         acc.Owner,
+		Labels:        acc.Labels,
+		Version:       acc.Version,
+		CreatedAt:     acc.CreatedAt.UTC(),
+		UpdatedAt:     acc.UpdatedAt.UTC(),
+	}
+}
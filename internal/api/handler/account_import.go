@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/rs/zerolog/log"
+
+	"internal-transfers/internal/api/types"
+	"internal-transfers/internal/domain"
+	"internal-transfers/internal/importer"
+)
+
+// maxImportBytes bounds the size of an import file accepted over HTTP
+const maxImportBytes = 32 << 20
+
+// importContentTypes maps the media types accepted for import bodies to their format
+var importContentTypes = map[string]importer.Format{
+	"text/csv":             importer.FormatCSV,
+	"application/jsonl":    importer.FormatJSONL,
+	"application/x-ndjson": importer.FormatJSONL,
+}
+
+// ImportAccounts creates accounts from a CSV or JSONL body, chosen by the format query parameter or the
+// Content-Type header, and responds with a per-row report. dry_run=true validates without creating anything.
+func (h *AccountHandler) ImportAccounts(w http.ResponseWriter, r *http.Request) {
+	format, err := importFormat(r)
+	if err != nil {
+		types.WriteResponseError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	dryRun := false
+	if v := r.URL.Query().Get("dry_run"); v != "" {
+		if dryRun, err = strconv.ParseBool(v); err != nil {
+			types.WriteResponseError(w, http.StatusBadRequest, "invalid dry_run")
+			return
+		}
+	}
+
+	records, err := importer.Parse(http.MaxBytesReader(w, r.Body, maxImportBytes), format)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			types.WriteResponseError(w, http.StatusRequestEntityTooLarge, "import file too large")
+			return
+		}
+		log.Ctx(r.Context()).Warn().Err(err).Msg("failed to read import file")
+		types.WriteResponseError(w, http.StatusBadRequest, "failed to read import file")
+		return
+	}
+	if len(records) == 0 {
+		types.WriteResponseError(w, http.StatusBadRequest, "import file has no rows")
+		return
+	}
+
+	report, err := h.accountService.ImportAccounts(r.Context(), records, dryRun)
+	if err != nil {
+		if errors.Is(err, domain.ErrMintNotAllowed) {
+			log.Ctx(r.Context()).Warn().Err(err).Msg("caller not allowed to mint")
+			types.WriteResponseError(w, http.StatusForbidden, "caller is not allowed to fund new accounts")
+			return
+		}
+		log.Ctx(r.Context()).Error().Err(err).Msg("failed to import accounts")
+		types.WriteResponseError(w, http.StatusInternalServerError, "failed to import accounts")
+		return
+	}
+	log.Ctx(r.Context()).Info().
+		Bool("dry_run", dryRun).
+		Int("created", report.Created).
+		Int("duplicate", report.Duplicate).
+		Int("invalid", report.Invalid).
+		Msg("accounts imported")
+
+	resp := types.ImportAccountsResponse{
+		DryRun:    report.DryRun,
+		Created:   report.Created,
+		Duplicate: report.Duplicate,
+		Invalid:   report.Invalid,
+		Rows:      make([]types.ImportRowResponse, len(report.Rows)),
+	}
+	for i, row := range report.Rows {
+		resp.Rows[i] = types.ImportRowResponse{
+			Line:      row.Line,
+			AccountID: row.AccountID,
+			Status:    string(row.Status),
+			Error:     row.Error,
+		}
+	}
+	types.WriteResponseSuccess(w, resp)
+}
+
+func importFormat(r *http.Request) (importer.Format, error) {
+	if f := r.URL.Query().Get("format"); f != "" {
+		return importer.ParseFormat(f)
+	}
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if format, ok := importContentTypes[mediaType]; err == nil && ok {
+		return format, nil
+	}
+	return "", errors.New("unsupported import format, use Content-Type text/csv or application/jsonl, or ?format=csv|jsonl")
+}
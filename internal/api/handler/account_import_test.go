@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"internal-transfers/internal/api/types"
+	"internal-transfers/internal/importer"
+	"internal-transfers/internal/service"
+	"internal-transfers/internal/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountHandler_ImportAccounts(t *testing.T) {
+	t.Run("csv", func(t *testing.T) {
+		// given
+		mockSvc := mocks.NewAccountService(t)
+		h := NewAccountHandler(mockSvc)
+		req := httptest.NewRequest(http.MethodPost, "/accounts/import", strings.NewReader("account_id,initial_balance\n1,100\n"))
+		req.Header.Set("Content-Type", "text/csv; charset=utf-8")
+		w := httptest.NewRecorder()
+
+		mockSvc.EXPECT().
+			ImportAccounts(mock.Anything, []importer.Record{{Line: 2, AccountID: 1, InitialBalance: 100}}, false).
+			Return(&service.ImportReport{
+				Created: 1,
+				Rows:    []service.ImportRow{{Line: 2, AccountID: 1, Status: service.ImportCreated}},
+			}, nil)
+
+		// when
+		h.ImportAccounts(w, req)
+
+		// then
+		resp := w.Result()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		var body struct {
+			Data types.ImportAccountsResponse `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, 1, body.Data.Created)
+		assert.Equal(t, []types.ImportRowResponse{{Line: 2, AccountID: 1, Status: "created"}}, body.Data.Rows)
+	})
+
+	t.Run("jsonl dry run", func(t *testing.T) {
+		// given
+		mockSvc := mocks.NewAccountService(t)
+		h := NewAccountHandler(mockSvc)
+		req := httptest.NewRequest(http.MethodPost, "/accounts/import?format=jsonl&dry_run=true",
+			strings.NewReader(`{"account_id": 1, "initial_balance": "100"}`))
+		w := httptest.NewRecorder()
+
+		mockSvc.EXPECT().
+			ImportAccounts(mock.Anything, mock.Anything, true).
+			Return(&service.ImportReport{DryRun: true}, nil)
+
+		// when
+		h.ImportAccounts(w, req)
+
+		// then
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		// given
+		h := NewAccountHandler(mocks.NewAccountService(t))
+		req := httptest.NewRequest(http.MethodPost, "/accounts/import", strings.NewReader("1,100\n"))
+		req.Header.Set("Content-Type", "application/xml")
+		w := httptest.NewRecorder()
+
+		// when
+		h.ImportAccounts(w, req)
+
+		// then
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	})
+
+	t.Run("empty file", func(t *testing.T) {
+		// given
+		h := NewAccountHandler(mocks.NewAccountService(t))
+		req := httptest.NewRequest(http.MethodPost, "/accounts/import?format=csv", strings.NewReader("account_id,initial_balance\n"))
+		w := httptest.NewRecorder()
+
+		// when
+		h.ImportAccounts(w, req)
+
+		// then
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		// given
+		mockSvc := mocks.NewAccountService(t)
+		h := NewAccountHandler(mockSvc)
+		req := httptest.NewRequest(http.MethodPost, "/accounts/import?format=csv", strings.NewReader("1,100\n"))
+		w := httptest.NewRecorder()
+
+		mockSvc.EXPECT().
+			ImportAccounts(mock.Anything, mock.Anything, false).
+			Return(nil, errors.New("db error"))
+
+		// when
+		h.ImportAccounts(w, req)
+
+		// then
+		assert.Equal(t, http.StatusInternalServerError, w.Result().StatusCode)
+	})
+}
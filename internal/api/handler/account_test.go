@@ -9,14 +9,19 @@ import (
 	"internal-transfers/internal/model"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"internal-transfers/internal/domain"
+	"internal-transfers/internal/repository"
+	"internal-transfers/internal/service"
 	"internal-transfers/internal/service/mocks" // import path to your generated mocks
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAccountHandler_CreateAccount(t *testing.T) {
@@ -30,8 +35,8 @@ func TestAccountHandler_CreateAccount(t *testing.T) {
 		w := httptest.NewRecorder()
 
 		mockSvc.EXPECT().
-			CreateAccount(mock.Anything, int64(1), 100.0).
-			Return(nil).
+			CreateAccount(mock.Anything, int64(1), 100.0, model.AccountType(""), service.AccountDetails{}).
+			Return(&model.Account{AccountID: 1, Balance: 100, Type: model.AccountCustomer, Version: 1}, nil).
 			Once()
 
 		// when
@@ -39,7 +44,59 @@ func TestAccountHandler_CreateAccount(t *testing.T) {
 
 		// then
 		resp := w.Result()
-		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.Equal(t, "/accounts/1", resp.Header.Get("Location"))
+		assert.Equal(t, `W/"1"`, resp.Header.Get("ETag"))
+		var body struct {
+			Data types.AccountResponse `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, int64(1), body.Data.AccountID)
+		assert.Equal(t, 100.0, body.Data.Balance)
+	})
+
+	t.Run("allocates the id when it is left out", func(t *testing.T) {
+		// given
+		mockSvc := mocks.NewAccountService(t)
+		h := NewAccountHandler(mockSvc)
+
+		reqBody := `{"initial_balance": 100}`
+		req := httptest.NewRequest(http.MethodPost, "/accounts", strings.NewReader(reqBody))
+		w := httptest.NewRecorder()
+
+		mockSvc.EXPECT().
+			CreateAccount(mock.Anything, int64(0), 100.0, model.AccountType(""), service.AccountDetails{}).
+			Return(&model.Account{AccountID: 100000000008, Balance: 100, Type: model.AccountCustomer, Version: 1}, nil).
+			Once()
+
+		// when
+		h.CreateAccount(w, req)
+
+		// then
+		resp := w.Result()
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.Equal(t, "/accounts/100000000008", resp.Header.Get("Location"))
+	})
+
+	t.Run("account id outside the range callers may choose", func(t *testing.T) {
+		// given
+		mockSvc := mocks.NewAccountService(t)
+		h := NewAccountHandler(mockSvc)
+
+		reqBody := `{"account_id": 100000000008, "initial_balance": 100}`
+		req := httptest.NewRequest(http.MethodPost, "/accounts", strings.NewReader(reqBody))
+		w := httptest.NewRecorder()
+
+		mockSvc.EXPECT().
+			CreateAccount(mock.Anything, int64(100000000008), 100.0, model.AccountType(""), service.AccountDetails{}).
+			Return(nil, fmt.Errorf("%w: account_id must be between 1 and 99999999999", domain.ErrInvalidAccount)).
+			Once()
+
+		// when
+		h.CreateAccount(w, req)
+
+		// then
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
 	})
 
 	t.Run("invalid body", func(t *testing.T) {
@@ -85,8 +142,8 @@ func TestAccountHandler_CreateAccount(t *testing.T) {
 		w := httptest.NewRecorder()
 
 		mockSvc.EXPECT().
-			CreateAccount(mock.Anything, int64(123), 10.0).
-			Return(domain.ErrAccountDuplicate).
+			CreateAccount(mock.Anything, int64(123), 10.0, model.AccountType(""), service.AccountDetails{}).
+			Return(nil, domain.ErrAccountDuplicate).
 			Once()
 
 		// when
@@ -98,6 +155,28 @@ func TestAccountHandler_CreateAccount(t *testing.T) {
 		mockSvc.AssertExpectations(t)
 	})
 
+	t.Run("caller not allowed to mint", func(t *testing.T) {
+		// given
+		mockSvc := mocks.NewAccountService(t)
+		h := NewAccountHandler(mockSvc)
+
+		reqBody := `{"account_id": 123, "initial_balance": 10}`
+		req := httptest.NewRequest(http.MethodPost, "/accounts", strings.NewReader(reqBody))
+		w := httptest.NewRecorder()
+
+		mockSvc.EXPECT().
+			CreateAccount(mock.Anything, int64(123), 10.0, model.AccountType(""), service.AccountDetails{}).
+			Return(nil, domain.ErrMintNotAllowed).
+			Once()
+
+		// when
+		h.CreateAccount(w, req)
+
+		// then
+		resp := w.Result()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
 	t.Run("service error", func(t *testing.T) {
 		// given
 		mockSvc := mocks.NewAccountService(t)
@@ -108,8 +187,8 @@ func TestAccountHandler_CreateAccount(t *testing.T) {
 		w := httptest.NewRecorder()
 
 		mockSvc.EXPECT().
-			CreateAccount(mock.Anything, int64(1), 100.0).
-			Return(errors.New("some db error")).
+			CreateAccount(mock.Anything, int64(1), 100.0, model.AccountType(""), service.AccountDetails{}).
+			Return(nil, errors.New("some db error")).
 			Once()
 
 		// when
@@ -119,9 +198,155 @@ func TestAccountHandler_CreateAccount(t *testing.T) {
 		resp := w.Result()
 		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
 	})
+
+	t.Run("invalid type", func(t *testing.T) {
+		// given
+		mockSvc := mocks.NewAccountService(t)
+		h := NewAccountHandler(mockSvc)
+
+		reqBody := `{"account_id": 1, "initial_balance": 100, "type": "system"}`
+		req := httptest.NewRequest(http.MethodPost, "/accounts", strings.NewReader(reqBody))
+		w := httptest.NewRecorder()
+
+		mockSvc.EXPECT().
+			CreateAccount(mock.Anything, int64(1), 100.0, model.AccountSystem, service.AccountDetails{}).
+			Return(nil, fmt.Errorf("%w: %q", domain.ErrInvalidAccountType, "system"))
+
+		// when
+		h.CreateAccount(w, req)
+
+		// then
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	})
+
+	t.Run("invalid details", func(t *testing.T) {
+		// given
+		mockSvc := mocks.NewAccountService(t)
+		h := NewAccountHandler(mockSvc)
+
+		reqBody := `{"account_id": 1, "initial_balance": 100, "name": "Savings", "owner": "cust-1", "labels": {"": "x"}}`
+		req := httptest.NewRequest(http.MethodPost, "/accounts", strings.NewReader(reqBody))
+		w := httptest.NewRecorder()
+
+		mockSvc.EXPECT().
+			CreateAccount(mock.Anything, int64(1), 100.0, model.AccountType(""), service.AccountDetails{
+				Name:   "Savings",
+				Owner:  "cust-1",
+				Labels: map[string]string{"": "x"},
+			}).
+			Return(nil, fmt.Errorf("%w: label keys must not be empty", domain.ErrInvalidAccount))
+
+		// when
+		h.CreateAccount(w, req)
+
+		// then
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	})
+}
+
+func TestAccountHandler_ListAccounts(t *testing.T) {
+	t.Run("filtered by type", func(t *testing.T) {
+		// given
+		mockSvc := mocks.NewAccountService(t)
+		h := NewAccountHandler(mockSvc)
+		req := httptest.NewRequest(http.MethodGet, "/accounts?type=revenue,expense&status=open&limit=5", nil)
+		w := httptest.NewRecorder()
+
+		mockSvc.EXPECT().
+			ListAccounts(mock.Anything, repository.AccountFilter{
+				Types:    []model.AccountType{model.AccountRevenue, model.AccountExpense},
+				Statuses: []model.AccountStatus{model.AccountOpen},
+				Limit:    5,
+			}).
+			Return([]*model.Account{
+				{AccountID: 7, Balance: 12.5, Type: model.AccountRevenue, Status: model.AccountOpen},
+				{AccountID: 8, Balance: -3, Type: model.AccountExpense, Status: model.AccountOpen},
+			}, nil)
+
+		// when
+		h.ListAccounts(w, req)
+
+		// then
+		resp := w.Result()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		var body struct {
+			Data types.ListAccountsResponse `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, []types.AccountResponse{
+			{AccountID: 7, Balance: 12.5, Type: "revenue", NormalBalance: "credit", Status: "open"},
+			{AccountID: 8, Balance: -3, Type: "expense", NormalBalance: "debit", Status: "open"},
+		}, body.Data.Accounts)
+	})
+
+	t.Run("filtered, sorted and paged", func(t *testing.T) {
+		// given
+		mockSvc := mocks.NewAccountService(t)
+		h := NewAccountHandler(mockSvc)
+		createdAt := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+		minBalance := 10.0
+		filter := repository.AccountFilter{
+			MinBalance:  &minBalance,
+			CreatedFrom: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			CreatedTo:   time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+			Sort:        repository.AccountSortBalance,
+			Desc:        true,
+			Limit:       1,
+		}
+		mockSvc.EXPECT().ListAccounts(mock.Anything, filter).
+			Return([]*model.Account{{AccountID: 7, Balance: 12.5, Type: model.AccountCustomer, CreatedAt: createdAt}}, nil)
+		query := "/accounts?min_balance=10&created_from=2025-01-01&created_to=2025-02-01&sort=-balance&limit=1"
+
+		// when
+		w := httptest.NewRecorder()
+		h.ListAccounts(w, httptest.NewRequest(http.MethodGet, query, nil))
+
+		// then
+		var body struct {
+			Data types.ListAccountsResponse `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(w.Result().Body).Decode(&body))
+		assert.Equal(t, createdAt, body.Data.Accounts[0].CreatedAt)
+		require.NotEmpty(t, body.Data.NextCursor)
+
+		// when the next page is requested with the cursor
+		filter.After = &repository.AccountCursor{AccountID: 7, Balance: 12.5}
+		mockSvc.EXPECT().ListAccounts(mock.Anything, filter).Return(nil, nil)
+		w = httptest.NewRecorder()
+		h.ListAccounts(w, httptest.NewRequest(http.MethodGet, query+"&cursor="+body.Data.NextCursor, nil))
+
+		// then
+		body.Data = types.ListAccountsResponse{}
+		require.NoError(t, json.NewDecoder(w.Result().Body).Decode(&body))
+		assert.Empty(t, body.Data.Accounts)
+		assert.Empty(t, body.Data.NextCursor)
+
+		// when the cursor is resumed in another order
+		w = httptest.NewRecorder()
+		h.ListAccounts(w, httptest.NewRequest(http.MethodGet, "/accounts?sort=balance&cursor="+encodeAccountCursor(filter, &model.Account{AccountID: 7}), nil))
+
+		// then
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		h := NewAccountHandler(mocks.NewAccountService(t))
+		for _, query := range []string{"type=asset", "status=frozen", "limit=0", "limit=x", "min_balance=x", "max_balance=NaN",
+			"created_from=yesterday", "sort=owner", "sort=+balance", "cursor=!!", "cursor=bm90IGpzb24"} {
+			w := httptest.NewRecorder()
+			h.ListAccounts(w, httptest.NewRequest(http.MethodGet, "/accounts?"+query, nil))
+			assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode, query)
+		}
+	})
 }
 
 func TestAccountHandler_GetAccount(t *testing.T) {
+	newRequest := func(id string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/accounts/"+id, nil)
+		req.SetPathValue("id", id)
+		return req
+	}
+
 	t.Run("success", func(t *testing.T) {
 		// given
 		mockSvc := mocks.NewAccountService(t)
@@ -131,6 +356,7 @@ func TestAccountHandler_GetAccount(t *testing.T) {
 		account := &model.Account{
 			AccountID: accountID,
 			Balance:   100.23344,
+			Version:   4,
 		}
 
 		mockSvc.EXPECT().
@@ -138,7 +364,7 @@ func TestAccountHandler_GetAccount(t *testing.T) {
 			Return(account, nil).
 			Once()
 
-		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/accounts/%d", accountID), nil)
+		req := newRequest(strconv.FormatInt(accountID, 10))
 		w := httptest.NewRecorder()
 
 		// when
@@ -149,6 +375,7 @@ func TestAccountHandler_GetAccount(t *testing.T) {
 		fmt.Printf("RESPONSE BODY IS: %v\n", resp)
 		defer resp.Body.Close()
 		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, `W/"4"`, resp.Header.Get("ETag"))
 
 		var gotResp struct {
 			Code    int                   `json:"code"`
@@ -169,7 +396,7 @@ func TestAccountHandler_GetAccount(t *testing.T) {
 		mockSvc := mocks.NewAccountService(t)
 		h := NewAccountHandler(mockSvc)
 
-		req := httptest.NewRequest(http.MethodGet, "/accounts/abc", nil)
+		req := newRequest("abc")
 		w := httptest.NewRecorder()
 
 		// when
@@ -183,6 +410,35 @@ func TestAccountHandler_GetAccount(t *testing.T) {
 		mockSvc.AssertNotCalled(t, "GetAccount", mock.Anything, mock.Anything)
 	})
 
+	t.Run("allocated id with a wrong check digit", func(t *testing.T) {
+		// given
+		h := NewAccountHandler(mocks.NewAccountService(t))
+		w := httptest.NewRecorder()
+
+		// when
+		h.GetAccount(w, newRequest("100000000009"))
+
+		// then
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+		assert.Contains(t, w.Body.String(), "100000000009 has a wrong check digit")
+	})
+
+	t.Run("allocated and seeded ids are looked up", func(t *testing.T) {
+		for _, id := range []int64{100000000008, model.FeeRevenueAccountID} {
+			// given
+			mockSvc := mocks.NewAccountService(t)
+			h := NewAccountHandler(mockSvc)
+			w := httptest.NewRecorder()
+			mockSvc.EXPECT().GetAccount(mock.Anything, id).Return(&model.Account{AccountID: id}, nil)
+
+			// when
+			h.GetAccount(w, newRequest(strconv.FormatInt(id, 10)))
+
+			// then
+			assert.Equal(t, http.StatusOK, w.Result().StatusCode, id)
+		}
+	})
+
 	t.Run("account not found", func(t *testing.T) {
 		// given
 		mockSvc := mocks.NewAccountService(t)
@@ -194,7 +450,7 @@ func TestAccountHandler_GetAccount(t *testing.T) {
 			Return(nil, domain.ErrAccountNotFound).
 			Once()
 
-		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/accounts/%d", accountID), nil)
+		req := newRequest(strconv.FormatInt(accountID, 10))
 		w := httptest.NewRecorder()
 
 		// when
@@ -219,7 +475,7 @@ func TestAccountHandler_GetAccount(t *testing.T) {
 			Return(nil, errors.New("service failure")).
 			Once()
 
-		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/accounts/%d", accountID), nil)
+		req := newRequest(strconv.FormatInt(accountID, 10))
 		w := httptest.NewRecorder()
 
 		// when
@@ -233,3 +489,140 @@ func TestAccountHandler_GetAccount(t *testing.T) {
 		mockSvc.AssertExpectations(t)
 	})
 }
+
+func TestAccountHandler_UpdateAccount(t *testing.T) {
+	name, tier := "Savings", "silver"
+	update := service.AccountUpdate{Name: &name, Labels: map[string]*string{"tier": &tier, "region": nil}}
+	reqBody := `{"name": "Savings", "labels": {"tier": "silver", "region": null}}`
+	newRequest := func(id, ifMatch string) *http.Request {
+		req := httptest.NewRequest(http.MethodPatch, "/accounts/"+id, strings.NewReader(reqBody))
+		req.SetPathValue("id", id)
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		return req
+	}
+
+	t.Run("success", func(t *testing.T) {
+		// given
+		mockSvc := mocks.NewAccountService(t)
+		h := NewAccountHandler(mockSvc)
+		w := httptest.NewRecorder()
+		mockSvc.EXPECT().UpdateAccount(mock.Anything, int64(7), update, int64(3)).Return(&model.Account{
+			AccountID: 7,
+			Balance:   50,
+			Type:      model.AccountCustomer,
+			Name:      "Savings",
+			Owner:     "cust-1",
+			Labels:    map[string]string{"tier": "silver"},
+			Version:   4,
+		}, nil)
+
+		// when
+		h.UpdateAccount(w, newRequest("7", `W/"3"`))
+
+		// then
+		resp := w.Result()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, `W/"4"`, resp.Header.Get("ETag"))
+		var body struct {
+			Data types.AccountResponse `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, "Savings", body.Data.Name)
+		assert.Equal(t, "cust-1", body.Data.Owner)
+		assert.Equal(t, map[string]string{"tier": "silver"}, body.Data.Labels)
+		assert.Equal(t, int64(4), body.Data.Version)
+	})
+
+	t.Run("without a precondition", func(t *testing.T) {
+		for _, ifMatch := range []string{"", "*"} {
+			// given
+			mockSvc := mocks.NewAccountService(t)
+			h := NewAccountHandler(mockSvc)
+			w := httptest.NewRecorder()
+			mockSvc.EXPECT().UpdateAccount(mock.Anything, int64(7), update, int64(0)).Return(&model.Account{AccountID: 7, Version: 2}, nil)
+
+			// when
+			h.UpdateAccount(w, newRequest("7", ifMatch))
+
+			// then
+			assert.Equal(t, http.StatusOK, w.Result().StatusCode, ifMatch)
+		}
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		h := NewAccountHandler(mocks.NewAccountService(t))
+		for _, req := range []*http.Request{newRequest("x", ""), newRequest("7", "3"), newRequest("7", `"0"`), newRequest("7", `"a"`), newRequest("7", `W/3`)} {
+			w := httptest.NewRecorder()
+			h.UpdateAccount(w, req)
+			assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode, req.Header.Get("If-Match"))
+		}
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPatch, "/accounts/7", strings.NewReader(`{"name": 1}`))
+		req.SetPathValue("id", "7")
+		h.UpdateAccount(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	})
+
+	t.Run("closes the account", func(t *testing.T) {
+		// given
+		mockSvc := mocks.NewAccountService(t)
+		h := NewAccountHandler(mockSvc)
+		w := httptest.NewRecorder()
+		closed := model.AccountClosed
+		mockSvc.EXPECT().UpdateAccount(mock.Anything, int64(7), service.AccountUpdate{Status: &closed}, int64(0)).
+			Return(&model.Account{AccountID: 7, Status: model.AccountClosed, Version: 2}, nil)
+		req := httptest.NewRequest(http.MethodPatch, "/accounts/7", strings.NewReader(`{"status": "closed"}`))
+		req.SetPathValue("id", "7")
+
+		// when
+		h.UpdateAccount(w, req)
+
+		// then
+		resp := w.Result()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		var body struct {
+			Data types.AccountResponse `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, "closed", body.Data.Status)
+	})
+
+	t.Run("strong form of the ETag", func(t *testing.T) {
+		// given
+		mockSvc := mocks.NewAccountService(t)
+		h := NewAccountHandler(mockSvc)
+		w := httptest.NewRecorder()
+		mockSvc.EXPECT().UpdateAccount(mock.Anything, int64(7), update, int64(3)).Return(&model.Account{AccountID: 7, Version: 4}, nil)
+
+		// when
+		h.UpdateAccount(w, newRequest("7", `"3"`))
+
+		// then
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+	})
+
+	t.Run("service errors", func(t *testing.T) {
+		for err, status := range map[error]int{
+			domain.ErrAccountNotFound: http.StatusNotFound,
+			fmt.Errorf("%w: name is longer than 200 characters", domain.ErrInvalidAccount): http.StatusBadRequest,
+			fmt.Errorf("%w: account is at version 4", domain.ErrAccountVersionMismatch):    http.StatusPreconditionFailed,
+			domain.ErrConcurrentUpdate: http.StatusServiceUnavailable,
+			errors.New("db down"):      http.StatusInternalServerError,
+		} {
+			// given
+			mockSvc := mocks.NewAccountService(t)
+			h := NewAccountHandler(mockSvc)
+			w := httptest.NewRecorder()
+			mockSvc.EXPECT().UpdateAccount(mock.Anything, int64(7), update, int64(3)).Return(nil, err)
+
+			// when
+			h.UpdateAccount(w, newRequest("7", `"3"`))
+
+			// then
+			assert.Equal(t, status, w.Result().StatusCode, err.Error())
+		}
+	})
+}
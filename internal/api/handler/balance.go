@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"internal-transfers/internal/api/types"
+	"internal-transfers/internal/domain"
+	"internal-transfers/internal/service"
+)
+
+type BalanceHandler struct {
+	balanceService service.BalanceService
+}
+
+func NewBalanceHandler(svc service.BalanceService) *BalanceHandler {
+	return &BalanceHandler{balanceService: svc}
+}
+
+// GetBalance returns the balance of an account as of the RFC 3339 timestamp in as_of, defaulting to now
+func (h *BalanceHandler) GetBalance(w http.ResponseWriter, r *http.Request) {
+	accountID, err := parseAccountID(r.PathValue("id"))
+	if err != nil {
+		types.WriteResponseError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	asOf := time.Now().UTC()
+	if v := r.URL.Query().Get("as_of"); v != "" {
+		if asOf, err = time.Parse(time.RFC3339, v); err != nil {
+			types.WriteResponseError(w, http.StatusBadRequest, "invalid as_of, expected RFC 3339")
+			return
+		}
+	}
+
+	balance, err := h.balanceService.BalanceAt(r.Context(), accountID, asOf)
+	if err != nil {
+		if errors.Is(err, domain.ErrAccountNotFound) {
+			types.WriteResponseError(w, http.StatusNotFound, "account not found")
+			return
+		}
+		log.Ctx(r.Context()).Error().Err(err).Msg("failed to get balance")
+		types.WriteResponseError(w, http.StatusInternalServerError, "failed to get balance")
+		return
+	}
+
+	types.WriteResponseSuccess(w, types.BalanceResponse{
+		AccountID: accountID,
+		Balance:   balance,
+		AsOf:      asOf,
+	})
+}
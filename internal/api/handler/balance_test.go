@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"internal-transfers/internal/api/types"
+	"internal-transfers/internal/domain"
+	"internal-transfers/internal/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBalanceHandler_GetBalance(t *testing.T) {
+	asOf := time.Date(2025, 1, 31, 23, 59, 59, 0, time.UTC)
+
+	newRequest := func(id, target string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.SetPathValue("id", id)
+		return req
+	}
+
+	t.Run("success", func(t *testing.T) {
+		// given
+		mockSvc := mocks.NewBalanceService(t)
+		h := NewBalanceHandler(mockSvc)
+		w := httptest.NewRecorder()
+		mockSvc.EXPECT().BalanceAt(mock.Anything, int64(1), asOf).Return(42.5, nil)
+
+		// when
+		h.GetBalance(w, newRequest("1", "/accounts/1/balance?as_of=2025-01-31T23:59:59Z"))
+
+		// then
+		resp := w.Result()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		var body struct {
+			Data types.BalanceResponse `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, types.BalanceResponse{AccountID: 1, Balance: 42.5, AsOf: asOf}, body.Data)
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		h := NewBalanceHandler(mocks.NewBalanceService(t))
+		for id, target := range map[string]string{
+			"abc":          "/accounts/abc/balance",
+			"100000000009": "/accounts/100000000009/balance",
+			"1":            "/accounts/1/balance?as_of=2025-01-31",
+		} {
+			w := httptest.NewRecorder()
+			h.GetBalance(w, newRequest(id, target))
+			assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode, target)
+		}
+	})
+
+	t.Run("account not found", func(t *testing.T) {
+		// given
+		mockSvc := mocks.NewBalanceService(t)
+		h := NewBalanceHandler(mockSvc)
+		w := httptest.NewRecorder()
+		mockSvc.EXPECT().BalanceAt(mock.Anything, int64(1), mock.Anything).Return(0, domain.ErrAccountNotFound)
+
+		// when
+		h.GetBalance(w, newRequest("1", "/accounts/1/balance"))
+
+		// then
+		assert.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		// given
+		mockSvc := mocks.NewBalanceService(t)
+		h := NewBalanceHandler(mockSvc)
+		w := httptest.NewRecorder()
+		mockSvc.EXPECT().BalanceAt(mock.Anything, int64(1), mock.Anything).Return(0, errors.New("db error"))
+
+		// when
+		h.GetBalance(w, newRequest("1", "/accounts/1/balance"))
+
+		// then
+		assert.Equal(t, http.StatusInternalServerError, w.Result().StatusCode)
+	})
+}
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+
+	"internal-transfers/internal/api/types"
+	"internal-transfers/internal/health"
+)
+
+type HealthHandler struct {
+	checker *health.Checker
+}
+
+func NewHealthHandler(checker *health.Checker) *HealthHandler {
+	return &HealthHandler{checker: checker}
+}
+
+// Liveness reports that the process is up and serving requests; it never touches dependencies
+func (h *HealthHandler) Liveness(w http.ResponseWriter, r *http.Request) {
+	types.WriteResponseSuccess(w, health.Report{Status: health.StatusUp, Checks: []health.CheckResult{}})
+}
+
+// Readiness reports whether the service can take traffic, responding 503 if any check fails
+func (h *HealthHandler) Readiness(w http.ResponseWriter, r *http.Request) {
+	report := h.checker.Check(r.Context())
+	if report.Status != health.StatusUp {
+		log.Ctx(r.Context()).Warn().Interface("checks", report.Checks).Msg("readiness check failed")
+		types.WriteResponse(w, http.StatusServiceUnavailable, "not ready", report)
+		return
+	}
+	types.WriteResponseSuccess(w, report)
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"internal-transfers/internal/health"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthHandler_Liveness(t *testing.T) {
+	// given
+	h := NewHealthHandler(health.NewChecker(time.Second))
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	w := httptest.NewRecorder()
+
+	// when
+	h.Liveness(w, req)
+
+	// then
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+}
+
+func TestHealthHandler_Readiness(t *testing.T) {
+	t.Run("ready", func(t *testing.T) {
+		// given
+		checker := health.NewChecker(time.Second)
+		checker.Register("database", func(ctx context.Context) error { return nil })
+		h := NewHealthHandler(checker)
+		req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+		w := httptest.NewRecorder()
+
+		// when
+		h.Readiness(w, req)
+
+		// then
+		resp := w.Result()
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var gotResp struct {
+			Data health.Report `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&gotResp))
+		assert.Equal(t, health.StatusUp, gotResp.Data.Status)
+		assert.Len(t, gotResp.Data.Checks, 2)
+	})
+
+	t.Run("dependency down", func(t *testing.T) {
+		// given
+		checker := health.NewChecker(time.Second)
+		checker.Register("database", func(ctx context.Context) error { return errors.New("connection refused") })
+		h := NewHealthHandler(checker)
+		req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+		w := httptest.NewRecorder()
+
+		// when
+		h.Readiness(w, req)
+
+		// then
+		assert.Equal(t, http.StatusServiceUnavailable, w.Result().StatusCode)
+	})
+}
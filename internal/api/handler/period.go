@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"internal-transfers/internal/api/types"
+	"internal-transfers/internal/domain"
+	"internal-transfers/internal/model"
+	"internal-transfers/internal/service"
+)
+
+const (
+	defaultPeriodsListed = 30
+	// maxPeriodsListed bounds how many days one listing spans
+	maxPeriodsListed = 366
+)
+
+type PeriodHandler struct {
+	periodService service.PeriodService
+}
+
+func NewPeriodHandler(svc service.PeriodService) *PeriodHandler {
+	return &PeriodHandler{periodService: svc}
+}
+
+// ListPeriods lists the daily periods from the date in from to the one in to, by default the 30 days up to
+// yesterday
+func (h *PeriodHandler) ListPeriods(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	to := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
+	if v := q.Get("to"); v != "" {
+		var err error
+		if to, err = time.Parse(time.DateOnly, v); err != nil {
+			types.WriteResponseError(w, http.StatusBadRequest, "invalid to, expected YYYY-MM-DD")
+			return
+		}
+	}
+	from := to.AddDate(0, 0, 1-defaultPeriodsListed)
+	if v := q.Get("from"); v != "" {
+		var err error
+		if from, err = time.Parse(time.DateOnly, v); err != nil {
+			types.WriteResponseError(w, http.StatusBadRequest, "invalid from, expected YYYY-MM-DD")
+			return
+		}
+	}
+	if from.After(to) || from.AddDate(0, 0, maxPeriodsListed).Before(to) {
+		types.WriteResponseError(w, http.StatusBadRequest, "from must be on or before to and at most 366 days earlier")
+		return
+	}
+
+	periods, err := h.periodService.ListPeriods(r.Context(), from, to)
+	if err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("failed to list periods")
+		types.WriteResponseError(w, http.StatusInternalServerError, "failed to list periods")
+		return
+	}
+	resp := types.ListPeriodsResponse{Periods: make([]types.PeriodResponse, 0, len(periods))}
+	for _, p := range periods {
+		resp.Periods = append(resp.Periods, periodResponse(p))
+	}
+	types.WriteResponseSuccess(w, resp)
+}
+
+// ClosePeriod closes the daily period whose date is the id, snapshotting balances and recording its trial balance
+func (h *PeriodHandler) ClosePeriod(w http.ResponseWriter, r *http.Request) {
+	day, err := time.Parse(time.DateOnly, r.PathValue("id"))
+	if err != nil {
+		types.WriteResponseError(w, http.StatusBadRequest, "invalid period id, expected YYYY-MM-DD")
+		return
+	}
+
+	period, err := h.periodService.ClosePeriod(r.Context(), day)
+	switch {
+	case err == nil:
+	case errors.Is(err, domain.ErrPeriodClosed), errors.Is(err, domain.ErrPeriodNotClosable):
+		types.WriteResponseError(w, http.StatusConflict, err.Error())
+		return
+	default:
+		log.Ctx(r.Context()).Error().Err(err).Msg("failed to close period")
+		types.WriteResponseError(w, http.StatusInternalServerError, "failed to close period")
+		return
+	}
+	types.WriteResponseSuccess(w, periodResponse(period))
+}
+
+func periodResponse(p *model.Period) types.PeriodResponse {
+	resp := types.PeriodResponse{
+		ID:     p.Date.Format(time.DateOnly),
+		Start:  p.Date,
+		End:    p.End(),
+		Status: "open",
+	}
+	if p.Closed {
+		resp.Status = "closed"
+		resp.ClosedBy = p.ClosedBy
+	}
+	if !p.ClosedAt.IsZero() {
+		closedAt := p.ClosedAt.UTC()
+		resp.ClosedAt = &closedAt
+	}
+	if tb := p.TrialBalance; tb != nil {
+		resp.TrialBalance = &types.TrialBalanceResponse{
+			Lines: make([]types.TrialBalanceLineResponse, 0, len(tb.Lines)),
+			OpeningBalances: types.OpeningBalancesResponse{
+				Accounts: tb.OpeningBalances.Accounts,
+				Debit:    tb.OpeningBalances.Debit,
+				Credit:   tb.OpeningBalances.Credit,
+			},
+			Debits:   tb.Debits,
+			Credits:  tb.Credits,
+			Balanced: math.Round(tb.Debits*100) == math.Round(tb.Credits*100),
+		}
+		for _, line := range tb.Lines {
+			resp.TrialBalance.Lines = append(resp.TrialBalance.Lines, types.TrialBalanceLineResponse{
+				AccountType: string(line.AccountType),
+				Accounts:    line.Accounts,
+				Debit:       line.Debit,
+				Credit:      line.Credit,
+			})
+		}
+	}
+	return resp
+}
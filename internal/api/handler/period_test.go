@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"internal-transfers/internal/api/types"
+	"internal-transfers/internal/domain"
+	"internal-transfers/internal/model"
+	"internal-transfers/internal/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeriodHandler_ListPeriods(t *testing.T) {
+	day := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		// given
+		mockSvc := mocks.NewPeriodService(t)
+		h := NewPeriodHandler(mockSvc)
+		w := httptest.NewRecorder()
+		mockSvc.EXPECT().ListPeriods(mock.Anything, day, day.AddDate(0, 0, 1)).Return([]*model.Period{
+			{Date: day, Closed: true, ClosedAt: day.Add(25 * time.Hour), ClosedBy: "eod-close", TrialBalance: &model.TrialBalance{
+				Lines:           []model.TrialBalanceLine{{AccountType: model.AccountCustomer, Accounts: 2, Credit: 150}},
+				OpeningBalances: model.TrialBalanceLine{Accounts: 2, Debit: 150},
+				Debits:          150,
+				Credits:         150,
+			}},
+			{Date: day.AddDate(0, 0, 1)},
+		}, nil)
+
+		// when
+		h.ListPeriods(w, httptest.NewRequest(http.MethodGet, "/admin/periods?from=2025-01-15&to=2025-01-16", nil))
+
+		// then
+		resp := w.Result()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		var body struct {
+			Data types.ListPeriodsResponse `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		require.Len(t, body.Data.Periods, 2)
+		closed := body.Data.Periods[0]
+		assert.Equal(t, "2025-01-15", closed.ID)
+		assert.Equal(t, "closed", closed.Status)
+		assert.Equal(t, day.AddDate(0, 0, 1), closed.End)
+		require.NotNil(t, closed.TrialBalance)
+		assert.True(t, closed.TrialBalance.Balanced)
+		assert.Equal(t, []types.TrialBalanceLineResponse{{AccountType: "customer", Accounts: 2, Credit: 150}}, closed.TrialBalance.Lines)
+		assert.Equal(t, types.OpeningBalancesResponse{Accounts: 2, Debit: 150}, closed.TrialBalance.OpeningBalances)
+		assert.Equal(t, types.PeriodResponse{
+			ID:     "2025-01-16",
+			Start:  day.AddDate(0, 0, 1),
+			End:    day.AddDate(0, 0, 2),
+			Status: "open",
+		}, body.Data.Periods[1])
+	})
+
+	t.Run("invalid range", func(t *testing.T) {
+		for _, query := range []string{"from=15-01-2025", "to=yesterday", "from=2025-01-16&to=2025-01-15", "from=2023-01-01&to=2025-01-15"} {
+			// given
+			h := NewPeriodHandler(mocks.NewPeriodService(t))
+			w := httptest.NewRecorder()
+
+			// when
+			h.ListPeriods(w, httptest.NewRequest(http.MethodGet, "/admin/periods?"+query, nil))
+
+			// then
+			assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode, query)
+		}
+	})
+}
+
+func TestPeriodHandler_ClosePeriod(t *testing.T) {
+	day := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	newRequest := func(id string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/admin/periods/"+id+"/close", nil)
+		req.SetPathValue("id", id)
+		return req
+	}
+
+	t.Run("success", func(t *testing.T) {
+		// given
+		mockSvc := mocks.NewPeriodService(t)
+		h := NewPeriodHandler(mockSvc)
+		w := httptest.NewRecorder()
+		mockSvc.EXPECT().ClosePeriod(mock.Anything, day).Return(&model.Period{
+			Date: day, Closed: true, ClosedAt: day.Add(26 * time.Hour), ClosedBy: "finance",
+			TrialBalance: &model.TrialBalance{Debits: 40, Credits: 40},
+		}, nil)
+
+		// when
+		h.ClosePeriod(w, newRequest("2025-01-15"))
+
+		// then
+		resp := w.Result()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		var body struct {
+			Data types.PeriodResponse `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, "closed", body.Data.Status)
+		assert.Equal(t, "finance", body.Data.ClosedBy)
+		require.NotNil(t, body.Data.ClosedAt)
+		assert.Equal(t, day.Add(26*time.Hour), *body.Data.ClosedAt)
+		assert.True(t, body.Data.TrialBalance.Balanced)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		h := NewPeriodHandler(mocks.NewPeriodService(t))
+		w := httptest.NewRecorder()
+		h.ClosePeriod(w, newRequest("today"))
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	})
+
+	t.Run("service errors", func(t *testing.T) {
+		for err, status := range map[error]int{
+			fmt.Errorf("%w: periods through 2025-01-15 are closed", domain.ErrPeriodClosed): http.StatusConflict,
+			fmt.Errorf("%w: 2025-01-14 must be closed first", domain.ErrPeriodNotClosable):  http.StatusConflict,
+			errors.New("db down"): http.StatusInternalServerError,
+		} {
+			// given
+			mockSvc := mocks.NewPeriodService(t)
+			h := NewPeriodHandler(mockSvc)
+			w := httptest.NewRecorder()
+			mockSvc.EXPECT().ClosePeriod(mock.Anything, day).Return(nil, err)
+
+			// when
+			h.ClosePeriod(w, newRequest("2025-01-15"))
+
+			// then
+			assert.Equal(t, status, w.Result().StatusCode, err.Error())
+		}
+	})
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+
+	"internal-transfers/internal/api/types"
+	"internal-transfers/internal/service"
+)
+
+type ReconciliationHandler struct {
+	reconciliationService service.ReconciliationService
+}
+
+func NewReconciliationHandler(svc service.ReconciliationService) *ReconciliationHandler {
+	return &ReconciliationHandler{reconciliationService: svc}
+}
+
+// GetLastReconciliation returns the report of the latest reconciliation run by this instance
+func (h *ReconciliationHandler) GetLastReconciliation(w http.ResponseWriter, r *http.Request) {
+	report := h.reconciliationService.Last()
+	if report == nil {
+		types.WriteResponseError(w, http.StatusNotFound, "no reconciliation has run yet")
+		return
+	}
+	types.WriteResponseSuccess(w, NewReconciliationResponse(report))
+}
+
+// GetSupply reports the total money held outside the mint account and how much of it was minted
+func (h *ReconciliationHandler) GetSupply(w http.ResponseWriter, r *http.Request) {
+	supply, err := h.reconciliationService.Supply(r.Context())
+	if err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("failed to get supply")
+		types.WriteResponseError(w, http.StatusInternalServerError, "failed to get supply")
+		return
+	}
+	types.WriteResponseSuccess(w, types.SupplyResponse{
+		AsOf:        supply.AsOf,
+		Accounts:    supply.Accounts,
+		Circulating: supply.Circulating,
+		Minted:      supply.Minted,
+		Settled:     supply.Settled,
+		Unfunded:    supply.Unfunded,
+	})
+}
+
+// NewReconciliationResponse converts a reconciliation report to its JSON representation
+func NewReconciliationResponse(report *service.ReconciliationReport) types.ReconciliationResponse {
+	resp := types.ReconciliationResponse{
+		StartedAt:            report.StartedAt,
+		FinishedAt:           report.FinishedAt,
+		AccountsChecked:      report.AccountsChecked,
+		AccountsUnverifiable: report.AccountsUnverifiable,
+		Discrepancies:        make([]types.DiscrepancyResponse, 0, len(report.Discrepancies)),
+	}
+	for _, d := range report.Discrepancies {
+		resp.Discrepancies = append(resp.Discrepancies, types.DiscrepancyResponse{
+			AccountID:       d.AccountID,
+			Balance:         d.Balance,
+			ExpectedBalance: d.ExpectedBalance,
+			Difference:      d.Difference,
+		})
+	}
+	return resp
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"internal-transfers/internal/api/types"
+	"internal-transfers/internal/service"
+	"internal-transfers/internal/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconciliationHandler_GetLastReconciliation(t *testing.T) {
+	at := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		// given
+		mockSvc := mocks.NewReconciliationService(t)
+		h := NewReconciliationHandler(mockSvc)
+		w := httptest.NewRecorder()
+		mockSvc.EXPECT().Last().Return(&service.ReconciliationReport{
+			StartedAt:       at,
+			FinishedAt:      at.Add(time.Second),
+			AccountsChecked: 3,
+			Discrepancies:   []service.Discrepancy{{AccountID: 2, Balance: 110, ExpectedBalance: 100, Difference: 10}},
+		})
+
+		// when
+		h.GetLastReconciliation(w, httptest.NewRequest(http.MethodGet, "/admin/reconciliation", nil))
+
+		// then
+		resp := w.Result()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		var body struct {
+			Data types.ReconciliationResponse `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, types.ReconciliationResponse{
+			StartedAt:       at,
+			FinishedAt:      at.Add(time.Second),
+			AccountsChecked: 3,
+			Discrepancies:   []types.DiscrepancyResponse{{AccountID: 2, Balance: 110, ExpectedBalance: 100, Difference: 10}},
+		}, body.Data)
+	})
+
+	t.Run("not run yet", func(t *testing.T) {
+		// given
+		mockSvc := mocks.NewReconciliationService(t)
+		h := NewReconciliationHandler(mockSvc)
+		w := httptest.NewRecorder()
+		mockSvc.EXPECT().Last().Return(nil)
+
+		// when
+		h.GetLastReconciliation(w, httptest.NewRequest(http.MethodGet, "/admin/reconciliation", nil))
+
+		// then
+		assert.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+	})
+}
+
+func TestReconciliationHandler_GetSupply(t *testing.T) {
+	at := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		// given
+		mockSvc := mocks.NewReconciliationService(t)
+		h := NewReconciliationHandler(mockSvc)
+		w := httptest.NewRecorder()
+		mockSvc.EXPECT().Supply(mock.Anything).Return(&service.SupplyReport{
+			AsOf: at, Accounts: 3, Circulating: 250, Minted: 200, Settled: 30, Unfunded: 20,
+		}, nil)
+
+		// when
+		h.GetSupply(w, httptest.NewRequest(http.MethodGet, "/admin/supply", nil))
+
+		// then
+		resp := w.Result()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		var body struct {
+			Data types.SupplyResponse `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, types.SupplyResponse{AsOf: at, Accounts: 3, Circulating: 250, Minted: 200, Settled: 30, Unfunded: 20}, body.Data)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		// given
+		mockSvc := mocks.NewReconciliationService(t)
+		h := NewReconciliationHandler(mockSvc)
+		w := httptest.NewRecorder()
+		mockSvc.EXPECT().Supply(mock.Anything).Return(nil, errors.New("db down"))
+
+		// when
+		h.GetSupply(w, httptest.NewRequest(http.MethodGet, "/admin/supply", nil))
+
+		// then
+		assert.Equal(t, http.StatusInternalServerError, w.Result().StatusCode)
+	})
+}
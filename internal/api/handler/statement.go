@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"internal-transfers/internal/api/types"
+	"internal-transfers/internal/domain"
+	"internal-transfers/internal/service"
+)
+
+// statementWriteTimeout bounds the time to write each part of a statement. The write deadline moves forward
+// with every part, so the server's write timeout does not cut off a long statement.
+const statementWriteTimeout = 30 * time.Second
+
+type StatementHandler struct {
+	statementService service.StatementService
+	writeTimeout     time.Duration
+}
+
+func NewStatementHandler(svc service.StatementService) *StatementHandler {
+	return &StatementHandler{statementService: svc, writeTimeout: statementWriteTimeout}
+}
+
+// GetStatement streams the statement of an account for [from, to) as CSV or JSON. from and to accept a date
+// (midnight UTC) or an RFC 3339 timestamp; to defaults to now.
+func (h *StatementHandler) GetStatement(w http.ResponseWriter, r *http.Request) {
+	accountID, err := parseAccountID(r.PathValue("id"))
+	if err != nil {
+		types.WriteResponseError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	query := r.URL.Query()
+	if query.Get("from") == "" {
+		types.WriteResponseError(w, http.StatusBadRequest, "from is required")
+		return
+	}
+	from, err := parseQueryTime(query.Get("from"))
+	if err != nil {
+		types.WriteResponseError(w, http.StatusBadRequest, "invalid from, expected YYYY-MM-DD or RFC 3339")
+		return
+	}
+	to := time.Now().UTC()
+	if v := query.Get("to"); v != "" {
+		if to, err = parseQueryTime(v); err != nil {
+			types.WriteResponseError(w, http.StatusBadRequest, "invalid to, expected YYYY-MM-DD or RFC 3339")
+			return
+		}
+	}
+
+	var sw statementWriter
+	w = &deadlineWriter{ResponseWriter: w, rc: http.NewResponseController(w), timeout: h.writeTimeout}
+	switch query.Get("format") {
+	case "", "json":
+		sw = &jsonStatementWriter{w: w}
+	case "csv":
+		sw = &csvStatementWriter{w: w}
+	default:
+		types.WriteResponseError(w, http.StatusBadRequest, "format must be csv or json")
+		return
+	}
+
+	err = h.statementService.WriteStatement(r.Context(), accountID, from, to, sw)
+	switch {
+	case err == nil:
+	case sw.started():
+		// the status line has gone out, so the only way left to signal failure is to cut the response short
+		log.Ctx(r.Context()).Error().Err(err).Msg("statement aborted mid-stream")
+		panic(http.ErrAbortHandler)
+	case errors.Is(err, domain.ErrInvalidPeriod):
+		types.WriteResponseError(w, http.StatusBadRequest, "from must be before to")
+	case errors.Is(err, domain.ErrAccountNotFound):
+		types.WriteResponseError(w, http.StatusNotFound, "account not found")
+	default:
+		log.Ctx(r.Context()).Error().Err(err).Msg("failed to get statement")
+		types.WriteResponseError(w, http.StatusInternalServerError, "failed to get statement")
+	}
+}
+
+// parseQueryTime reads a time query parameter given as a date (midnight UTC) or an RFC 3339 timestamp
+func parseQueryTime(v string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, v); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, v)
+}
+
+// deadlineWriter extends the write deadline of the response before every write
+type deadlineWriter struct {
+	http.ResponseWriter
+	rc      *http.ResponseController
+	timeout time.Duration
+}
+
+func (d *deadlineWriter) Write(p []byte) (int, error) {
+	// writers without deadlines, such as test recorders, keep the server's
+	_ = d.rc.SetWriteDeadline(time.Now().Add(d.timeout))
+	return d.ResponseWriter.Write(p)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (d *deadlineWriter) Unwrap() http.ResponseWriter {
+	return d.ResponseWriter
+}
+
+type statementWriter interface {
+	service.StatementWriter
+	// started reports whether any of the response has been written
+	started() bool
+}
+
+// jsonStatementWriter writes the statement in the standard response envelope, one entry at a time
+type jsonStatementWriter struct {
+	w       http.ResponseWriter
+	entries int
+	begun   bool
+}
+
+type statementEntryJSON struct {
+	TransactionID         int64     `json:"transaction_id"`
+	CreatedAt             time.Time `json:"created_at"`
+	CounterpartyAccountID int64     `json:"counterparty_account_id"`
+	Amount                float64   `json:"amount"`
+	Balance               float64   `json:"balance"`
+}
+
+func (j *jsonStatementWriter) Opening(header service.StatementHeader) error {
+	j.w.Header().Set("Content-Type", "application/json")
+	j.w.WriteHeader(http.StatusOK)
+	j.begun = true
+	_, err := fmt.Fprintf(j.w, `{"code":200,"message":"success","data":{"account_id":%d,"from":%q,"to":%q,"opening_balance":%s,"entries":[`,
+		header.AccountID, header.From.Format(time.RFC3339), header.To.Format(time.RFC3339), formatAmount(header.OpeningBalance))
+	return err
+}
+
+func (j *jsonStatementWriter) Entry(entry service.StatementEntry) error {
+	b, err := json.Marshal(statementEntryJSON(entry))
+	if err != nil {
+		return err
+	}
+	if j.entries > 0 {
+		if _, err := j.w.Write([]byte(",")); err != nil {
+			return err
+		}
+	}
+	j.entries++
+	_, err = j.w.Write(b)
+	return err
+}
+
+func (j *jsonStatementWriter) Closing(balance float64) error {
+	_, err := fmt.Fprintf(j.w, `],"closing_balance":%s}}`+"\n", formatAmount(balance))
+	return err
+}
+
+func (j *jsonStatementWriter) started() bool { return j.begun }
+
+// csvStatementWriter writes one row per entry, bracketed by opening and closing balance rows
+type csvStatementWriter struct {
+	w     http.ResponseWriter
+	csv   *csv.Writer
+	to    time.Time
+	begun bool
+}
+
+func (c *csvStatementWriter) Opening(header service.StatementHeader) error {
+	c.w.Header().Set("Content-Type", "text/csv")
+	c.w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="statement-%d-%s-%s.csv"`,
+		header.AccountID, header.From.Format(time.DateOnly), header.To.Format(time.DateOnly)))
+	c.w.WriteHeader(http.StatusOK)
+	c.begun = true
+	c.to = header.To
+
+	c.csv = csv.NewWriter(c.w)
+	if err := c.csv.Write([]string{"type", "created_at", "transaction_id", "counterparty_account_id", "amount", "balance"}); err != nil {
+		return err
+	}
+	return c.csv.Write([]string{"opening", header.From.Format(time.RFC3339), "", "", "", formatAmount(header.OpeningBalance)})
+}
+
+func (c *csvStatementWriter) Entry(entry service.StatementEntry) error {
+	return c.csv.Write([]string{
+		"transaction",
+		entry.CreatedAt.Format(time.RFC3339Nano),
+		strconv.FormatInt(entry.TransactionID, 10),
+		strconv.FormatInt(entry.CounterpartyAccountID, 10),
+		formatAmount(entry.Amount),
+		formatAmount(entry.Balance),
+	})
+}
+
+func (c *csvStatementWriter) Closing(balance float64) error {
+	if err := c.csv.Write([]string{"closing", c.to.Format(time.RFC3339), "", "", "", formatAmount(balance)}); err != nil {
+		return err
+	}
+	c.csv.Flush()
+	return c.csv.Error()
+}
+
+func (c *csvStatementWriter) started() bool { return c.begun }
+
+func formatAmount(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
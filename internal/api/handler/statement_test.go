@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"internal-transfers/internal/domain"
+	"internal-transfers/internal/service"
+	"internal-transfers/internal/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatementHandler_GetStatement(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	newRequest := func(target string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.SetPathValue("id", "1")
+		return req
+	}
+	writeStatement := func(ctx context.Context, accountID int64, from, to time.Time, w service.StatementWriter) error {
+		if err := w.Opening(service.StatementHeader{AccountID: accountID, From: from, To: to, OpeningBalance: 100}); err != nil {
+			return err
+		}
+		entries := []service.StatementEntry{
+			{TransactionID: 7, CreatedAt: from.Add(time.Hour), CounterpartyAccountID: 2, Amount: 50, Balance: 150},
+			{TransactionID: 8, CreatedAt: from.Add(2 * time.Hour), CounterpartyAccountID: 3, Amount: -30.5, Balance: 119.5},
+		}
+		for _, e := range entries {
+			if err := w.Entry(e); err != nil {
+				return err
+			}
+		}
+		return w.Closing(119.5)
+	}
+
+	t.Run("json", func(t *testing.T) {
+		// given
+		mockSvc := mocks.NewStatementService(t)
+		h := NewStatementHandler(mockSvc)
+		w := httptest.NewRecorder()
+		mockSvc.EXPECT().WriteStatement(mock.Anything, int64(1), from, to, mock.Anything).RunAndReturn(writeStatement)
+
+		// when
+		h.GetStatement(w, newRequest("/accounts/1/statement?from=2025-01-01&to=2025-02-01"))
+
+		// then
+		resp := w.Result()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		var body struct {
+			Data struct {
+				OpeningBalance float64 `json:"opening_balance"`
+				ClosingBalance float64 `json:"closing_balance"`
+				Entries        []struct {
+					TransactionID int64   `json:"transaction_id"`
+					Balance       float64 `json:"balance"`
+				} `json:"entries"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, 100.0, body.Data.OpeningBalance)
+		assert.Equal(t, 119.5, body.Data.ClosingBalance)
+		require.Len(t, body.Data.Entries, 2)
+		assert.Equal(t, int64(8), body.Data.Entries[1].TransactionID)
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		// given
+		mockSvc := mocks.NewStatementService(t)
+		h := NewStatementHandler(mockSvc)
+		w := httptest.NewRecorder()
+		mockSvc.EXPECT().WriteStatement(mock.Anything, int64(1), from, to, mock.Anything).RunAndReturn(writeStatement)
+
+		// when
+		h.GetStatement(w, newRequest("/accounts/1/statement?from=2025-01-01&to=2025-02-01T00:00:00Z&format=csv"))
+
+		// then
+		resp := w.Result()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/csv", resp.Header.Get("Content-Type"))
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, "type,created_at,transaction_id,counterparty_account_id,amount,balance\n"+
+			"opening,2025-01-01T00:00:00Z,,,,100\n"+
+			"transaction,2025-01-01T01:00:00Z,7,2,50,150\n"+
+			"transaction,2025-01-01T02:00:00Z,8,3,-30.5,119.5\n"+
+			"closing,2025-02-01T00:00:00Z,,,,119.5\n", string(body))
+	})
+
+	t.Run("account not found", func(t *testing.T) {
+		// given
+		mockSvc := mocks.NewStatementService(t)
+		h := NewStatementHandler(mockSvc)
+		w := httptest.NewRecorder()
+		mockSvc.EXPECT().WriteStatement(mock.Anything, int64(1), from, to, mock.Anything).Return(domain.ErrAccountNotFound)
+
+		// when
+		h.GetStatement(w, newRequest("/accounts/1/statement?from=2025-01-01&to=2025-02-01"))
+
+		// then
+		assert.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		h := NewStatementHandler(mocks.NewStatementService(t))
+		for _, target := range []string{
+			"/accounts/1/statement",
+			"/accounts/1/statement?from=yesterday",
+			"/accounts/1/statement?from=2025-01-01&to=soon",
+			"/accounts/1/statement?from=2025-01-01&format=xml",
+		} {
+			w := httptest.NewRecorder()
+			h.GetStatement(w, newRequest(target))
+			assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode, target)
+		}
+	})
+
+	t.Run("streams past the server's write timeout", func(t *testing.T) {
+		// given
+		mockSvc := mocks.NewStatementService(t)
+		h := NewStatementHandler(mockSvc)
+		h.writeTimeout = 200 * time.Millisecond
+		mockSvc.EXPECT().WriteStatement(mock.Anything, int64(1), from, to, mock.Anything).
+			RunAndReturn(func(ctx context.Context, accountID int64, from, to time.Time, sw service.StatementWriter) error {
+				if err := sw.Opening(service.StatementHeader{AccountID: accountID, From: from, To: to}); err != nil {
+					return err
+				}
+				for i := range 6 {
+					time.Sleep(50 * time.Millisecond)
+					if err := sw.Entry(service.StatementEntry{TransactionID: int64(i + 1)}); err != nil {
+						return err
+					}
+				}
+				return sw.Closing(0)
+			})
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /accounts/{id}/statement", h.GetStatement)
+		srv := httptest.NewUnstartedServer(mux)
+		srv.Config.WriteTimeout = 200 * time.Millisecond
+		srv.Start()
+		defer srv.Close()
+
+		// when
+		resp, err := http.Get(srv.URL + "/accounts/1/statement?from=2025-01-01&to=2025-02-01&format=csv")
+
+		// then
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Contains(t, string(body), "\nclosing,")
+	})
+
+	t.Run("failure after streaming started aborts the response", func(t *testing.T) {
+		// given
+		mockSvc := mocks.NewStatementService(t)
+		h := NewStatementHandler(mockSvc)
+		w := httptest.NewRecorder()
+		mockSvc.EXPECT().WriteStatement(mock.Anything, int64(1), from, to, mock.Anything).
+			RunAndReturn(func(ctx context.Context, accountID int64, from, to time.Time, sw service.StatementWriter) error {
+				_ = sw.Opening(service.StatementHeader{AccountID: accountID, From: from, To: to})
+				return assert.AnError
+			})
+
+		// when / then
+		assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+			h.GetStatement(w, newRequest("/accounts/1/statement?from=2025-01-01&to=2025-02-01"))
+		})
+	})
+}
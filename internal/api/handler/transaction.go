@@ -1,15 +1,29 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"internal-transfers/internal/domain"
+	"internal-transfers/internal/model"
+	"internal-transfers/internal/repository"
 	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
 
 	"internal-transfers/internal/api/types"
 	"internal-transfers/internal/service"
 )
 
+// retryAfterSeconds is sent with 503s caused by contention, which usually clears within a second
+const retryAfterSeconds = "1"
+
 type TransactionHandler struct {
 	transactionService service.TransactionService
 }
@@ -21,22 +35,292 @@ func NewTransactionHandler(svc service.TransactionService) *TransactionHandler {
 func (h *TransactionHandler) SubmitTransaction(w http.ResponseWriter, r *http.Request) {
 	var req types.TransactionRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("error decoding body")
 		types.WriteResponseError(w, http.StatusBadRequest, "invalid request body")
 		return
 	}
 	if req.Amount <= 0 {
+		log.Ctx(r.Context()).Warn().Msg("attempt to submit transaction with non-positive amount")
 		types.WriteResponseError(w, http.StatusBadRequest, "amount must be positive")
 		return
 	}
-	err := h.transactionService.ProcessTransaction(r.Context(), req.SourceAccountID, req.DestinationAccountID, float64(req.Amount))
+	for _, id := range []int64{req.SourceAccountID, req.DestinationAccountID} {
+		if err := checkAccountID(id); err != nil {
+			types.WriteResponseError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	details := service.TransferDetails{
+		Type:              model.TransactionType(req.Type),
+		Description:       req.Description,
+		Metadata:          req.Metadata,
+		ExternalReference: req.ExternalReference,
+		EffectiveAt:       req.EffectiveAt,
+	}
+	err := h.transactionService.ProcessTransaction(r.Context(), req.SourceAccountID, req.DestinationAccountID, float64(req.Amount), details)
+	if errors.Is(err, domain.ErrSameAccount) || errors.Is(err, domain.ErrInvalidTransaction) {
+		types.WriteResponseError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	if errors.Is(err, domain.ErrInsufficientFunds) {
+		log.Ctx(r.Context()).Warn().Err(err).Int64("source_account_id", req.SourceAccountID).Msg("insufficient funds")
 		types.WriteResponseError(w, http.StatusBadRequest, "insufficient funds from source account")
 		return
 	}
+	if errors.Is(err, domain.ErrPeriodClosed) || errors.Is(err, domain.ErrAccountClosed) {
+		types.WriteResponseError(w, http.StatusConflict, err.Error())
+		return
+	}
+
+	if errors.Is(err, domain.ErrConcurrentUpdate) {
+		log.Ctx(r.Context()).Warn().Err(err).Msg("transaction aborted by concurrent updates")
+		w.Header().Set("Retry-After", retryAfterSeconds)
+		types.WriteResponseError(w, http.StatusServiceUnavailable, "too many concurrent updates to the accounts, retry later")
+		return
+	}
 
 	if err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("failed to process transaction")
 		types.WriteResponseError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// TransferMulti applies a set of debits and credits netting to zero as one transfer
+func (h *TransactionHandler) TransferMulti(w http.ResponseWriter, r *http.Request) {
+	var req types.MultiTransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("error decoding body")
+		types.WriteResponseError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	legs := make([]model.TransferLeg, len(req.Legs))
+	for i, leg := range req.Legs {
+		if err := checkAccountID(leg.AccountID); err != nil {
+			types.WriteResponseError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		legs[i] = model.TransferLeg{AccountID: leg.AccountID, Amount: float64(leg.Amount)}
+	}
+	details := service.TransferDetails{
+		Type:              model.TransactionType(req.Type),
+		Description:       req.Description,
+		Metadata:          req.Metadata,
+		ExternalReference: req.ExternalReference,
+		EffectiveAt:       req.EffectiveAt,
+	}
+
+	group, err := h.transactionService.TransferMulti(r.Context(), legs, details)
+	switch {
+	case err == nil:
+	case errors.Is(err, domain.ErrInvalidTransaction):
+		types.WriteResponseError(w, http.StatusBadRequest, err.Error())
+		return
+	case errors.Is(err, domain.ErrAccountNotFound):
+		types.WriteResponseError(w, http.StatusNotFound, "account not found")
+		return
+	case errors.Is(err, domain.ErrInsufficientFunds):
+		log.Ctx(r.Context()).Warn().Err(err).Msg("insufficient funds")
+		types.WriteResponseError(w, http.StatusBadRequest, "insufficient funds in a debited account")
+		return
+	case errors.Is(err, domain.ErrPeriodClosed), errors.Is(err, domain.ErrAccountClosed):
+		types.WriteResponseError(w, http.StatusConflict, err.Error())
+		return
+	case errors.Is(err, domain.ErrConcurrentUpdate):
+		log.Ctx(r.Context()).Warn().Err(err).Msg("transfer aborted by concurrent updates")
+		w.Header().Set("Retry-After", retryAfterSeconds)
+		types.WriteResponseError(w, http.StatusServiceUnavailable, "too many concurrent updates to the accounts, retry later")
+		return
+	default:
+		log.Ctx(r.Context()).Error().Err(err).Msg("failed to process multi-leg transfer")
+		types.WriteResponseError(w, http.StatusInternalServerError, "failed to process transfer")
+		return
+	}
+
+	resp := types.MultiTransferResponse{
+		GroupID:      group.GroupID,
+		Legs:         req.Legs,
+		Transactions: make([]types.TransactionResponse, 0, len(group.Transactions)),
+		Timestamp:    group.CreatedAt.UTC().Format(time.RFC3339Nano),
+	}
+	for _, tx := range group.Transactions {
+		resp.Transactions = append(resp.Transactions, transactionResponse(tx))
+	}
+	types.WriteResponse(w, http.StatusCreated, "created", resp)
+}
+
+// Deposit credits the account in the path with money received by an external source
+func (h *TransactionHandler) Deposit(w http.ResponseWriter, r *http.Request) {
+	h.settle(w, r, h.transactionService.Deposit)
+}
+
+// Withdraw debits the account in the path for money paid out by an external source
+func (h *TransactionHandler) Withdraw(w http.ResponseWriter, r *http.Request) {
+	h.settle(w, r, h.transactionService.Withdraw)
+}
+
+type settleFunc func(ctx context.Context, accountID int64, source, reference string, amount float64) (*model.Transaction, error)
+
+func (h *TransactionHandler) settle(w http.ResponseWriter, r *http.Request, fn settleFunc) {
+	accountID, err := parseAccountID(r.PathValue("id"))
+	if err != nil {
+		types.WriteResponseError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var req types.SettlementRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("error decoding body")
+		types.WriteResponseError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	switch {
+	case req.Amount <= 0:
+		types.WriteResponseError(w, http.StatusBadRequest, "amount must be positive")
+		return
+	case req.Source == "":
+		types.WriteResponseError(w, http.StatusBadRequest, "source is required")
+		return
+	case req.ExternalReference == "":
+		types.WriteResponseError(w, http.StatusBadRequest, "external_reference is required")
+		return
+	}
+
+	transaction, err := fn(r.Context(), accountID, req.Source, req.ExternalReference, float64(req.Amount))
+	switch {
+	case err == nil:
+	case errors.Is(err, domain.ErrUnknownSource), errors.Is(err, domain.ErrSameAccount):
+		types.WriteResponseError(w, http.StatusBadRequest, err.Error())
+		return
+	case errors.Is(err, domain.ErrAccountNotFound):
+		types.WriteResponseError(w, http.StatusNotFound, "account not found")
+		return
+	case errors.Is(err, domain.ErrAccountClosed):
+		types.WriteResponseError(w, http.StatusConflict, err.Error())
+		return
+	case errors.Is(err, domain.ErrInsufficientFunds):
+		log.Ctx(r.Context()).Warn().Err(err).Int64("account_id", accountID).Msg("insufficient funds")
+		types.WriteResponseError(w, http.StatusUnprocessableEntity, "insufficient funds")
+		return
+	case errors.Is(err, domain.ErrDuplicateReference):
+		log.Ctx(r.Context()).Warn().Str("source", req.Source).Str("external_reference", req.ExternalReference).
+			Msg("external reference already posted")
+		types.WriteResponseError(w, http.StatusConflict, "external_reference has already been posted for this source")
+		return
+	case errors.Is(err, domain.ErrConcurrentUpdate):
+		log.Ctx(r.Context()).Warn().Err(err).Msg("settlement aborted by concurrent updates")
+		w.Header().Set("Retry-After", retryAfterSeconds)
+		types.WriteResponseError(w, http.StatusServiceUnavailable, "too many concurrent updates to the accounts, retry later")
+		return
+	default:
+		log.Ctx(r.Context()).Error().Err(err).Msg("failed to process settlement")
+		types.WriteResponseError(w, http.StatusInternalServerError, "failed to process settlement")
+		return
+	}
+
+	types.WriteResponse(w, http.StatusCreated, "created", transactionResponse(transaction))
+}
+
+// Limits on the page size of ListTransactions
+const (
+	defaultListLimit = 100
+	maxListLimit     = 1000
+)
+
+// metadataParamPrefix marks query parameters filtering on a metadata key, e.g. metadata.order_id=42
+const metadataParamPrefix = "metadata."
+
+// ListTransactions returns a page of transactions, oldest first, optionally filtered by account, type,
+// external reference, metadata and creation time
+func (h *TransactionHandler) ListTransactions(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseTransactionFilter(r.URL.Query())
+	if err != nil {
+		types.WriteResponseError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	transactions, err := h.transactionService.ListTransactions(r.Context(), filter)
+	if err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("failed to list transactions")
+		types.WriteResponseError(w, http.StatusInternalServerError, "failed to list transactions")
+		return
+	}
+
+	resp := types.ListTransactionsResponse{Transactions: make([]types.TransactionResponse, 0, len(transactions))}
+	for _, tx := range transactions {
+		resp.Transactions = append(resp.Transactions, transactionResponse(tx))
+	}
+	if len(transactions) == filter.Limit {
+		resp.NextAfter = transactions[len(transactions)-1].TransactionID
+	}
+	types.WriteResponseSuccess(w, resp)
+}
+
+func parseTransactionFilter(q url.Values) (repository.TransactionFilter, error) {
+	filter := repository.TransactionFilter{Limit: defaultListLimit, ExternalReference: q.Get("external_reference")}
+	for _, p := range []struct {
+		name string
+		dst  *int64
+	}{{"account_id", &filter.AccountID}, {"group_id", &filter.GroupID}, {"after", &filter.AfterID}} {
+		if v := q.Get(p.name); v != "" {
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil || n <= 0 {
+				return filter, fmt.Errorf("invalid %s", p.name)
+			}
+			*p.dst = n
+		}
+	}
+	for _, p := range []struct {
+		name string
+		dst  *time.Time
+	}{{"from", &filter.From}, {"to", &filter.To}} {
+		if v := q.Get(p.name); v != "" {
+			t, err := parseQueryTime(v)
+			if err != nil {
+				return filter, fmt.Errorf("invalid %s, expected a date or RFC 3339 timestamp", p.name)
+			}
+			*p.dst = t
+		}
+	}
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > maxListLimit {
+			return filter, fmt.Errorf("invalid limit, expected 1 to %d", maxListLimit)
+		}
+		filter.Limit = n
+	}
+	if v := q.Get("type"); v != "" {
+		for _, t := range strings.Split(v, ",") {
+			transactionType := model.TransactionType(t)
+			if !slices.Contains(model.TransactionTypes, transactionType) {
+				return filter, fmt.Errorf("unknown transaction type %q", t)
+			}
+			filter.Types = append(filter.Types, transactionType)
+		}
+	}
+	for key, values := range q {
+		if name, ok := strings.CutPrefix(key, metadataParamPrefix); ok && name != "" {
+			if filter.Metadata == nil {
+				filter.Metadata = make(map[string]string)
+			}
+			filter.Metadata[name] = values[0]
+		}
+	}
+	return filter, nil
+}
+
+func transactionResponse(tx *model.Transaction) types.TransactionResponse {
+	return types.TransactionResponse{
+		TransactionID:        tx.TransactionID,
+		SourceAccountID:      tx.SourceAccountID,
+		DestinationAccountID: tx.DestinationAccountID,
+		Amount:               types.FlexibleFloat(tx.Amount),
+		Type:                 string(tx.Type),
+		Description:          tx.Description,
+		Metadata:             tx.Metadata,
+		ExternalSource:       tx.ExternalSource,
+		ExternalReference:    tx.ExternalReference,
+		GroupID:              tx.GroupID,
+		Timestamp:            tx.CreatedAt.UTC().Format(time.RFC3339Nano),
+	}
+}
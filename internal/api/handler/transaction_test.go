@@ -2,15 +2,24 @@ package handler
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/stretchr/testify/mock"
+	"internal-transfers/internal/api/types"
 	"internal-transfers/internal/domain"
+	"internal-transfers/internal/model"
+	"internal-transfers/internal/repository"
+	"internal-transfers/internal/service"
 	"internal-transfers/internal/service/mocks"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestTransactionHandler_SubmitTransaction(t *testing.T) {
@@ -24,7 +33,7 @@ func TestTransactionHandler_SubmitTransaction(t *testing.T) {
 
 		// when
 		mockSvc.
-			On("ProcessTransaction", mock.Anything, int64(1), int64(2), 100.0).
+			On("ProcessTransaction", mock.Anything, int64(1), int64(2), 100.0, service.TransferDetails{}).
 			Return(nil)
 
 		// then
@@ -60,6 +69,18 @@ func TestTransactionHandler_SubmitTransaction(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 	})
 
+	t.Run("account id with a wrong check digit", func(t *testing.T) {
+		h := NewTransactionHandler(mocks.NewTransactionService(t))
+		for _, reqBody := range []string{
+			`{"source_account_id": 100000000009, "destination_account_id": 2, "amount": 50}`,
+			`{"source_account_id": 1, "destination_account_id": 100000000018, "amount": 50}`,
+		} {
+			w := httptest.NewRecorder()
+			h.SubmitTransaction(w, httptest.NewRequest(http.MethodPost, "/transactions", strings.NewReader(reqBody)))
+			assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode, reqBody)
+		}
+	})
+
 	t.Run("insufficient funds", func(t *testing.T) {
 		// given
 		mockSvc := &mocks.TransactionService{}
@@ -70,7 +91,7 @@ func TestTransactionHandler_SubmitTransaction(t *testing.T) {
 
 		// when
 		mockSvc.
-			On("ProcessTransaction", mock.Anything, int64(1), int64(2), 100.0).
+			On("ProcessTransaction", mock.Anything, int64(1), int64(2), 100.0, service.TransferDetails{}).
 			Return(domain.ErrInsufficientFunds)
 
 		// then
@@ -79,6 +100,45 @@ func TestTransactionHandler_SubmitTransaction(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 	})
 
+	t.Run("same source and destination", func(t *testing.T) {
+		// given
+		mockSvc := &mocks.TransactionService{}
+		h := NewTransactionHandler(mockSvc)
+		reqBody := `{"source_account_id": 1, "destination_account_id": 1, "amount": 100}`
+		req := httptest.NewRequest(http.MethodPost, "/transactions", bytes.NewReader([]byte(reqBody)))
+		w := httptest.NewRecorder()
+
+		// when
+		mockSvc.
+			On("ProcessTransaction", mock.Anything, int64(1), int64(1), 100.0, service.TransferDetails{}).
+			Return(domain.ErrSameAccount)
+
+		// then
+		h.SubmitTransaction(w, req)
+		resp := w.Result()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("retries exhausted", func(t *testing.T) {
+		// given
+		mockSvc := &mocks.TransactionService{}
+		h := NewTransactionHandler(mockSvc)
+		reqBody := `{"source_account_id": 1, "destination_account_id": 2, "amount": 100}`
+		req := httptest.NewRequest(http.MethodPost, "/transactions", bytes.NewReader([]byte(reqBody)))
+		w := httptest.NewRecorder()
+
+		// when
+		mockSvc.
+			On("ProcessTransaction", mock.Anything, int64(1), int64(2), 100.0, service.TransferDetails{}).
+			Return(fmt.Errorf("%w: gave up after 4 attempts", domain.ErrConcurrentUpdate))
+
+		// then
+		h.SubmitTransaction(w, req)
+		resp := w.Result()
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Equal(t, "1", resp.Header.Get("Retry-After"))
+	})
+
 	t.Run("generic service error", func(t *testing.T) {
 		// given
 		mockSvc := &mocks.TransactionService{}
@@ -89,7 +149,7 @@ func TestTransactionHandler_SubmitTransaction(t *testing.T) {
 
 		// when
 		mockSvc.
-			On("ProcessTransaction", mock.Anything, int64(1), int64(2), 100.0).
+			On("ProcessTransaction", mock.Anything, int64(1), int64(2), 100.0, service.TransferDetails{}).
 			Return(errors.New("some db error"))
 
 		// then
@@ -98,4 +158,312 @@ func TestTransactionHandler_SubmitTransaction(t *testing.T) {
 		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
 	})
 
+	t.Run("details", func(t *testing.T) {
+		// given
+		mockSvc := mocks.NewTransactionService(t)
+		h := NewTransactionHandler(mockSvc)
+		reqBody := `{"source_account_id": 1, "destination_account_id": 2, "amount": 100, "type": "payroll",
+			"description": "January salary", "metadata": {"run": "2025-01"}, "external_reference": "pr-1"}`
+		w := httptest.NewRecorder()
+		mockSvc.EXPECT().ProcessTransaction(mock.Anything, int64(1), int64(2), 100.0, service.TransferDetails{
+			Type:              model.TransactionPayroll,
+			Description:       "January salary",
+			Metadata:          map[string]string{"run": "2025-01"},
+			ExternalReference: "pr-1",
+		}).Return(nil)
+
+		// when
+		h.SubmitTransaction(w, httptest.NewRequest(http.MethodPost, "/transactions", strings.NewReader(reqBody)))
+
+		// then
+		assert.Equal(t, http.StatusNoContent, w.Result().StatusCode)
+	})
+
+	t.Run("invalid details", func(t *testing.T) {
+		// given
+		mockSvc := mocks.NewTransactionService(t)
+		h := NewTransactionHandler(mockSvc)
+		reqBody := `{"source_account_id": 1, "destination_account_id": 2, "amount": 100, "type": "deposit"}`
+		w := httptest.NewRecorder()
+		mockSvc.EXPECT().ProcessTransaction(mock.Anything, int64(1), int64(2), 100.0, mock.Anything).
+			Return(fmt.Errorf("%w: type \"deposit\" cannot be used for a transfer", domain.ErrInvalidTransaction))
+
+		// when
+		h.SubmitTransaction(w, httptest.NewRequest(http.MethodPost, "/transactions", strings.NewReader(reqBody)))
+
+		// then
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	})
+
+	t.Run("backdated into a closed period", func(t *testing.T) {
+		// given
+		mockSvc := mocks.NewTransactionService(t)
+		h := NewTransactionHandler(mockSvc)
+		reqBody := `{"source_account_id": 1, "destination_account_id": 2, "amount": 100, "effective_at": "2025-01-15T10:00:00Z"}`
+		w := httptest.NewRecorder()
+		mockSvc.EXPECT().ProcessTransaction(mock.Anything, int64(1), int64(2), 100.0, service.TransferDetails{
+			EffectiveAt: time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC),
+		}).Return(fmt.Errorf("%w: periods through 2025-01-15 are closed", domain.ErrPeriodClosed))
+
+		// when
+		h.SubmitTransaction(w, httptest.NewRequest(http.MethodPost, "/transactions", strings.NewReader(reqBody)))
+
+		// then
+		assert.Equal(t, http.StatusConflict, w.Result().StatusCode)
+	})
+
+	t.Run("closed account", func(t *testing.T) {
+		// given
+		mockSvc := mocks.NewTransactionService(t)
+		h := NewTransactionHandler(mockSvc)
+		reqBody := `{"source_account_id": 1, "destination_account_id": 2, "amount": 100}`
+		w := httptest.NewRecorder()
+		mockSvc.EXPECT().ProcessTransaction(mock.Anything, int64(1), int64(2), 100.0, service.TransferDetails{}).
+			Return(fmt.Errorf("%w: account 2", domain.ErrAccountClosed))
+
+		// when
+		h.SubmitTransaction(w, httptest.NewRequest(http.MethodPost, "/transactions", strings.NewReader(reqBody)))
+
+		// then
+		assert.Equal(t, http.StatusConflict, w.Result().StatusCode)
+	})
+}
+
+func TestTransactionHandler_ListTransactions(t *testing.T) {
+	at := time.Date(2025, 1, 31, 12, 0, 0, 0, time.UTC)
+
+	t.Run("filters and next page", func(t *testing.T) {
+		// given
+		mockSvc := mocks.NewTransactionService(t)
+		h := NewTransactionHandler(mockSvc)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet,
+			"/transactions?account_id=1&type=payroll,fee&external_reference=pr-1&metadata.run=2025-01&from=2025-01-01&after=5&limit=2", nil)
+		mockSvc.EXPECT().ListTransactions(mock.Anything, repository.TransactionFilter{
+			AccountID:         1,
+			Types:             []model.TransactionType{model.TransactionPayroll, model.TransactionFee},
+			ExternalReference: "pr-1",
+			Metadata:          map[string]string{"run": "2025-01"},
+			From:              time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			AfterID:           5,
+			Limit:             2,
+		}).Return([]*model.Transaction{
+			{TransactionID: 6, SourceAccountID: 1, DestinationAccountID: 2, Amount: 10, Type: model.TransactionPayroll, CreatedAt: at},
+			{TransactionID: 8, SourceAccountID: 1, DestinationAccountID: 3, Amount: 1, Type: model.TransactionFee,
+				Description: "wire fee", Metadata: map[string]string{"run": "2025-01"}, CreatedAt: at},
+		}, nil)
+
+		// when
+		h.ListTransactions(w, req)
+
+		// then
+		resp := w.Result()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		var body struct {
+			Data types.ListTransactionsResponse `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		require.Len(t, body.Data.Transactions, 2)
+		assert.Equal(t, "wire fee", body.Data.Transactions[1].Description)
+		assert.Equal(t, map[string]string{"run": "2025-01"}, body.Data.Transactions[1].Metadata)
+		assert.Equal(t, int64(8), body.Data.NextAfter)
+	})
+
+	t.Run("last page", func(t *testing.T) {
+		// given
+		mockSvc := mocks.NewTransactionService(t)
+		h := NewTransactionHandler(mockSvc)
+		w := httptest.NewRecorder()
+		mockSvc.EXPECT().ListTransactions(mock.Anything, repository.TransactionFilter{Limit: 100}).Return(nil, nil)
+
+		// when
+		h.ListTransactions(w, httptest.NewRequest(http.MethodGet, "/transactions", nil))
+
+		// then
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+		assert.Contains(t, w.Body.String(), `"transactions":[]`)
+		assert.NotContains(t, w.Body.String(), "next_after")
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		h := NewTransactionHandler(mocks.NewTransactionService(t))
+		for _, query := range []string{"account_id=x", "after=-1", "from=yesterday", "to=2025-13-01", "limit=0", "limit=1001",
+			"type=bonus", "type=fee,,refund"} {
+			w := httptest.NewRecorder()
+			h.ListTransactions(w, httptest.NewRequest(http.MethodGet, "/transactions?"+query, nil))
+			assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode, query)
+		}
+	})
+}
+
+func TestTransactionHandler_Deposit(t *testing.T) {
+	at := time.Date(2025, 1, 31, 12, 0, 0, 0, time.UTC)
+
+	newRequest := func(id, body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/accounts/"+id+"/deposits", strings.NewReader(body))
+		req.SetPathValue("id", id)
+		return req
+	}
+
+	t.Run("success", func(t *testing.T) {
+		// given
+		mockSvc := mocks.NewTransactionService(t)
+		h := NewTransactionHandler(mockSvc)
+		w := httptest.NewRecorder()
+		mockSvc.EXPECT().Deposit(mock.Anything, int64(1), "bank", "dep-1", 100.5).Return(&model.Transaction{
+			TransactionID: 7, SourceAccountID: 9001, DestinationAccountID: 1, Amount: 100.5,
+			Type: model.TransactionDeposit, ExternalSource: "bank", ExternalReference: "dep-1", CreatedAt: at,
+		}, nil)
+
+		// when
+		h.Deposit(w, newRequest("1", `{"amount": "100.5", "source": "bank", "external_reference": "dep-1"}`))
+
+		// then
+		resp := w.Result()
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		var body struct {
+			Data types.TransactionResponse `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, types.TransactionResponse{
+			TransactionID: 7, SourceAccountID: 9001, DestinationAccountID: 1, Amount: 100.5,
+			Type: "deposit", ExternalSource: "bank", ExternalReference: "dep-1", Timestamp: "2025-01-31T12:00:00Z",
+		}, body.Data)
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		h := NewTransactionHandler(mocks.NewTransactionService(t))
+		for name, req := range map[string]*http.Request{
+			"account id":         newRequest("x", `{"amount": 1, "source": "bank", "external_reference": "r"}`),
+			"check digit":        newRequest("100000000009", `{"amount": 1, "source": "bank", "external_reference": "r"}`),
+			"body":               newRequest("1", `{`),
+			"amount":             newRequest("1", `{"amount": 0, "source": "bank", "external_reference": "r"}`),
+			"source":             newRequest("1", `{"amount": 1, "external_reference": "r"}`),
+			"external reference": newRequest("1", `{"amount": 1, "source": "bank"}`),
+		} {
+			w := httptest.NewRecorder()
+			h.Deposit(w, req)
+			assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode, name)
+		}
+	})
+
+	t.Run("service errors", func(t *testing.T) {
+		for err, status := range map[error]int{
+			domain.ErrUnknownSource:      http.StatusBadRequest,
+			domain.ErrSameAccount:        http.StatusBadRequest, // the account is the settlement account itself
+			domain.ErrAccountNotFound:    http.StatusNotFound,
+			domain.ErrDuplicateReference: http.StatusConflict,
+			domain.ErrAccountClosed:      http.StatusConflict,
+			domain.ErrConcurrentUpdate:   http.StatusServiceUnavailable,
+			errors.New("db down"):        http.StatusInternalServerError,
+		} {
+			// given
+			mockSvc := mocks.NewTransactionService(t)
+			h := NewTransactionHandler(mockSvc)
+			w := httptest.NewRecorder()
+			mockSvc.EXPECT().Deposit(mock.Anything, int64(1), "bank", "dep-1", 10.0).Return(nil, err)
+
+			// when
+			h.Deposit(w, newRequest("1", `{"amount": 10, "source": "bank", "external_reference": "dep-1"}`))
+
+			// then
+			assert.Equal(t, status, w.Result().StatusCode, err.Error())
+		}
+	})
+}
+
+func TestTransactionHandler_Withdraw(t *testing.T) {
+	// given
+	mockSvc := mocks.NewTransactionService(t)
+	h := NewTransactionHandler(mockSvc)
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/accounts/1/withdrawals", strings.NewReader(`{"amount": 10, "source": "bank", "external_reference": "wd-1"}`))
+	req.SetPathValue("id", "1")
+	mockSvc.EXPECT().Withdraw(mock.Anything, int64(1), "bank", "wd-1", 10.0).Return(nil, domain.ErrInsufficientFunds)
+
+	// when
+	h.Withdraw(w, req)
+
+	// then
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Result().StatusCode)
+}
+
+func TestTransactionHandler_TransferMulti(t *testing.T) {
+	at := time.Date(2025, 1, 31, 12, 0, 0, 0, time.UTC)
+	reqBody := `{"legs": [{"account_id": 1, "amount": "-10"}, {"account_id": 2, "amount": 9}, {"account_id": 3, "amount": 1}],
+		"type": "payment", "description": "order 42"}`
+	legs := []model.TransferLeg{{AccountID: 1, Amount: -10}, {AccountID: 2, Amount: 9}, {AccountID: 3, Amount: 1}}
+	details := service.TransferDetails{Type: model.TransactionPayment, Description: "order 42"}
+
+	t.Run("success", func(t *testing.T) {
+		// given
+		mockSvc := mocks.NewTransactionService(t)
+		h := NewTransactionHandler(mockSvc)
+		w := httptest.NewRecorder()
+		mockSvc.EXPECT().TransferMulti(mock.Anything, legs, details).Return(&model.TransferGroup{
+			GroupID: 4,
+			Legs:    legs,
+			Transactions: []*model.Transaction{
+				{TransactionID: 7, SourceAccountID: 1, DestinationAccountID: 2, Amount: 9, Type: model.TransactionPayment, GroupID: 4, CreatedAt: at},
+				{TransactionID: 8, SourceAccountID: 1, DestinationAccountID: 3, Amount: 1, Type: model.TransactionPayment, GroupID: 4, CreatedAt: at},
+			},
+			CreatedAt: at,
+		}, nil)
+
+		// when
+		h.TransferMulti(w, httptest.NewRequest(http.MethodPost, "/transfers/multi", strings.NewReader(reqBody)))
+
+		// then
+		resp := w.Result()
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		var body struct {
+			Data types.MultiTransferResponse `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, int64(4), body.Data.GroupID)
+		assert.Len(t, body.Data.Legs, 3)
+		require.Len(t, body.Data.Transactions, 2)
+		assert.Equal(t, int64(3), body.Data.Transactions[1].DestinationAccountID)
+		assert.Equal(t, int64(4), body.Data.Transactions[1].GroupID)
+		assert.Equal(t, "2025-01-31T12:00:00Z", body.Data.Timestamp)
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		h := NewTransactionHandler(mocks.NewTransactionService(t))
+		w := httptest.NewRecorder()
+		h.TransferMulti(w, httptest.NewRequest(http.MethodPost, "/transfers/multi", strings.NewReader(`{`)))
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	})
+
+	t.Run("account id with a wrong check digit", func(t *testing.T) {
+		h := NewTransactionHandler(mocks.NewTransactionService(t))
+		reqBody := `{"legs": [{"account_id": 1, "amount": -10}, {"account_id": 100000000009, "amount": 10}]}`
+		w := httptest.NewRecorder()
+		h.TransferMulti(w, httptest.NewRequest(http.MethodPost, "/transfers/multi", strings.NewReader(reqBody)))
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	})
+
+	t.Run("service errors", func(t *testing.T) {
+		for err, status := range map[error]int{
+			fmt.Errorf("%w: legs do not net to zero", domain.ErrInvalidTransaction): http.StatusBadRequest,
+			domain.ErrInsufficientFunds: http.StatusBadRequest,
+			domain.ErrAccountNotFound:   http.StatusNotFound,
+			domain.ErrPeriodClosed:      http.StatusConflict,
+			domain.ErrAccountClosed:     http.StatusConflict,
+			domain.ErrConcurrentUpdate:  http.StatusServiceUnavailable,
+			errors.New("db down"):       http.StatusInternalServerError,
+		} {
+			// given
+			mockSvc := mocks.NewTransactionService(t)
+			h := NewTransactionHandler(mockSvc)
+			w := httptest.NewRecorder()
+			mockSvc.EXPECT().TransferMulti(mock.Anything, legs, details).Return(nil, err)
+
+			// when
+			h.TransferMulti(w, httptest.NewRequest(http.MethodPost, "/transfers/multi", strings.NewReader(reqBody)))
+
+			// then
+			assert.Equal(t, status, w.Result().StatusCode, err.Error())
+		}
+	})
 }
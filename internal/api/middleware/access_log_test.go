@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessLog(t *testing.T) {
+	// given
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf)
+
+	h := AccessLog(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("hello"))
+	}))
+	req := httptest.NewRequest(http.MethodPost, "/accounts", nil)
+	req = req.WithContext(logger.WithContext(req.Context()))
+	w := httptest.NewRecorder()
+
+	// when
+	h.ServeHTTP(w, req)
+
+	// then
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "POST", entry["method"])
+	assert.Equal(t, "/accounts", entry["path"])
+	assert.EqualValues(t, http.StatusCreated, entry["status"])
+	assert.EqualValues(t, 5, entry["bytes"])
+	assert.Contains(t, entry, "latency")
+}
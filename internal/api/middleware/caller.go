@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+
+	"internal-transfers/internal/domain"
+)
+
+// HeaderCallerID names the system making a request. It is not authenticated, so it only guards against
+// mistakes by trusted internal callers.
+const HeaderCallerID = "X-Caller-ID"
+
+// Caller attaches the caller named by X-Caller-ID to the request context and its logger
+func Caller(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		caller := r.Header.Get(HeaderCallerID)
+		if caller == "" || len(caller) > maxRequestIDLength {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := domain.WithCaller(r.Context(), caller)
+		reqLogger := log.Ctx(ctx).With().Str("caller", caller).Logger()
+		next.ServeHTTP(w, r.WithContext(reqLogger.WithContext(ctx)))
+	})
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"internal-transfers/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCaller(t *testing.T) {
+	t.Run("attaches caller", func(t *testing.T) {
+		// given
+		var got string
+		h := Caller(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = domain.CallerFromContext(r.Context())
+		}))
+		req := httptest.NewRequest(http.MethodPost, "/accounts", nil)
+		req.Header.Set(HeaderCallerID, "onboarding")
+
+		// when
+		h.ServeHTTP(httptest.NewRecorder(), req)
+
+		// then
+		assert.Equal(t, "onboarding", got)
+	})
+
+	t.Run("no caller", func(t *testing.T) {
+		// given
+		got := "unset"
+		h := Caller(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = domain.CallerFromContext(r.Context())
+		}))
+
+		// when
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/accounts", nil))
+
+		// then
+		assert.Empty(t, got)
+	})
+}
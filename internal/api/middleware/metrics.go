@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"internal-transfers/internal/metrics"
+)
+
+// unmatchedRoute labels requests that did not match any registered pattern, keeping label cardinality bounded
+const unmatchedRoute = "unmatched"
+
+// Metrics records request counts and latencies per route; it must wrap the ServeMux directly so that
+// the matched pattern is visible on the request once the mux returns
+func Metrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := newResponseRecorder(w)
+
+		next.ServeHTTP(rec, r)
+
+		route := unmatchedRoute
+		if r.Pattern != "" {
+			// patterns are registered as "METHOD /path"; the method already has its own label
+			_, path, found := strings.Cut(r.Pattern, " ")
+			if !found {
+				path = r.Pattern
+			}
+			route = path
+		}
+		metrics.ObserveHTTPRequest(r.Method, route, strconv.Itoa(rec.status), time.Since(start))
+	})
+}
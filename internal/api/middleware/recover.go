@@ -1,9 +1,11 @@
 package middleware
 
 import (
-	"log"
+	"fmt"
 	"net/http"
 	"runtime/debug"
+
+	"github.com/rs/zerolog/log"
 )
 
 // RecoverPanic recovers from panics in HTTP handlers
@@ -11,7 +13,14 @@ func RecoverPanic(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		defer func() {
 			if err := recover(); err != nil {
-				log.Printf("panic recovered: %v\n%s", err, debug.Stack())
+				// deliberate aborts are left to net/http, which closes the connection without logging
+				if err == http.ErrAbortHandler {
+					panic(err)
+				}
+				log.Ctx(r.Context()).Error().
+					Str("panic", fmt.Sprint(err)).
+					Bytes("stack", debug.Stack()).
+					Msg("panic recovered")
 				http.Error(w, "internal server error", http.StatusInternalServerError)
 			}
 		}()
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+)
+
+const HeaderRequestID = "X-Request-ID"
+
+// maxRequestIDLength bounds caller-supplied ids so they cannot bloat every log line
+const maxRequestIDLength = 128
+
+type requestIDKey struct{}
+
+// RequestID assigns every request an id, reusing the caller's X-Request-ID when present,
+// echoes it back in the response and attaches a logger carrying it to the request context
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(HeaderRequestID)
+		if id == "" || len(id) > maxRequestIDLength {
+			id = newRequestID()
+		}
+		w.Header().Set(HeaderRequestID, id)
+
+		ctx := context.WithValue(r.Context(), requestIDKey{}, id)
+		reqLogger := log.Ctx(ctx).With().Str("request_id", id).Logger()
+		ctx = reqLogger.WithContext(ctx)
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequestIDFromContext returns the id assigned by RequestID, or an empty string if there is none
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
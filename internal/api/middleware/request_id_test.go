@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	t.Run("propagates caller request id", func(t *testing.T) {
+		// given
+		var gotID string
+		h := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotID = RequestIDFromContext(r.Context())
+		}))
+		req := httptest.NewRequest(http.MethodGet, "/accounts/1", nil)
+		req.Header.Set(HeaderRequestID, "abc-123")
+		w := httptest.NewRecorder()
+
+		// when
+		h.ServeHTTP(w, req)
+
+		// then
+		assert.Equal(t, "abc-123", gotID)
+		assert.Equal(t, "abc-123", w.Header().Get(HeaderRequestID))
+	})
+
+	t.Run("generates request id when missing", func(t *testing.T) {
+		// given
+		var gotID string
+		h := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotID = RequestIDFromContext(r.Context())
+		}))
+		req := httptest.NewRequest(http.MethodGet, "/accounts/1", nil)
+		w := httptest.NewRecorder()
+
+		// when
+		h.ServeHTTP(w, req)
+
+		// then
+		assert.Len(t, gotID, 32)
+		assert.Equal(t, gotID, w.Header().Get(HeaderRequestID))
+	})
+}
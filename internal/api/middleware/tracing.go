@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing starts a server span per request, continuing any W3C traceparent sent by the caller.
+// Like Metrics, the request it passes down must reach the ServeMux unchanged so the span can be named after the matched route.
+func Tracing(next http.Handler) http.Handler {
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		span := trace.SpanFromContext(r.Context())
+		if sc := span.SpanContext(); sc.HasTraceID() {
+			reqLogger := log.Ctx(r.Context()).With().Str("trace_id", sc.TraceID().String()).Logger()
+			r = r.WithContext(reqLogger.WithContext(r.Context()))
+		}
+
+		next.ServeHTTP(w, r)
+
+		if r.Pattern != "" {
+			span.SetName(r.Pattern)
+			if _, route, found := strings.Cut(r.Pattern, " "); found {
+				span.SetAttributes(semconv.HTTPRoute(route))
+			}
+		}
+	})
+	return otelhttp.NewHandler(inner, "http.request")
+}
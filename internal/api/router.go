@@ -5,36 +5,67 @@ import (
 
 	"internal-transfers/internal/api/handler"
 	"internal-transfers/internal/api/middleware"
+	"internal-transfers/internal/health"
+	"internal-transfers/internal/metrics"
 	"internal-transfers/internal/service"
 )
 
 func NewRouter(
 	accountSvc service.AccountService,
 	transactionSvc service.TransactionService,
+	statementSvc service.StatementService,
+	balanceSvc service.BalanceService,
+	reconciliationSvc service.ReconciliationService,
+	periodSvc service.PeriodService,
+	checker *health.Checker,
 ) http.Handler {
 
 	mux := http.NewServeMux()
 
 	accountHandler := handler.NewAccountHandler(accountSvc)
 	transactionHandler := handler.NewTransactionHandler(transactionSvc)
+	statementHandler := handler.NewStatementHandler(statementSvc)
+	balanceHandler := handler.NewBalanceHandler(balanceSvc)
+	reconciliationHandler := handler.NewReconciliationHandler(reconciliationSvc)
+	periodHandler := handler.NewPeriodHandler(periodSvc)
+	healthHandler := handler.NewHealthHandler(checker)
 
 	// Account endpoints
-	mux.HandleFunc("/accounts/", withMethod(http.MethodGet, accountHandler.GetAccount)) // expects /accounts/{id}
-	mux.HandleFunc("/accounts", withMethod(http.MethodPost, accountHandler.CreateAccount))
+	mux.HandleFunc("GET /accounts", accountHandler.ListAccounts)
+	mux.HandleFunc("GET /accounts/{id}", accountHandler.GetAccount)
+	mux.HandleFunc("PATCH /accounts/{id}", accountHandler.UpdateAccount)
+	mux.HandleFunc("POST /accounts", accountHandler.CreateAccount)
+	mux.HandleFunc("POST /accounts/import", accountHandler.ImportAccounts)
+	mux.HandleFunc("GET /accounts/{id}/statement", statementHandler.GetStatement)
+	mux.HandleFunc("GET /accounts/{id}/balance", balanceHandler.GetBalance)
 
 	// Transaction endpoints
-	mux.HandleFunc("/transactions", withMethod(http.MethodPost, transactionHandler.SubmitTransaction))
+	mux.HandleFunc("POST /transactions", transactionHandler.SubmitTransaction)
+	mux.HandleFunc("GET /transactions", transactionHandler.ListTransactions)
+	mux.HandleFunc("POST /transfers/multi", transactionHandler.TransferMulti)
+	mux.HandleFunc("POST /accounts/{id}/deposits", transactionHandler.Deposit)
+	mux.HandleFunc("POST /accounts/{id}/withdrawals", transactionHandler.Withdraw)
 
-	return middleware.RecoverPanic(mux)
-}
+	// Admin endpoints
+	mux.HandleFunc("GET /admin/reconciliation", reconciliationHandler.GetLastReconciliation)
+	mux.HandleFunc("GET /admin/supply", reconciliationHandler.GetSupply)
+	mux.HandleFunc("GET /admin/periods", periodHandler.ListPeriods)
+	mux.HandleFunc("POST /admin/periods/{id}/close", periodHandler.ClosePeriod)
+
+	// Operational endpoints
+	mux.Handle("GET /metrics", metrics.Handler())
+	mux.HandleFunc("GET /healthz", healthHandler.Liveness)
+	mux.HandleFunc("GET /readyz", healthHandler.Readiness)
 
-// helper to enforce allowed methods
-func withMethod(method string, next http.HandlerFunc) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != method {
-			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-			return
-		}
-		next(w, r)
-	}
+	return middleware.RequestID(
+		middleware.Caller(
+			middleware.Tracing(
+				middleware.AccessLog(
+					middleware.Metrics(
+						middleware.RecoverPanic(mux),
+					),
+				),
+			),
+		),
+	)
 }
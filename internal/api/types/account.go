@@ -1,11 +1,64 @@
 package types
 
+import "time"
+
 type CreateAccountRequest struct {
 	AccountID      int64         `json:"account_id"`
 	InitialBalance FlexibleFloat `json:"initial_balance"`
+	// Type defaults to customer
+	Type   string            `json:"type,omitempty"`
+	Name   string            `json:"name,omitempty"`
+	Owner  string            `json:"owner,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// UpdateAccountRequest changes the fields it sets and leaves the others as they are
+type UpdateAccountRequest struct {
+	Name  *string `json:"name"`
+	Owner *string `json:"owner"`
+	// Labels are merged into the account's labels; a null value removes the label
+	Labels map[string]*string `json:"labels"`
+	// Status closes or reopens the account
+	Status *string `json:"status"`
 }
 
 type AccountResponse struct {
-	AccountID int64   `json:"account_id"`
-	Balance   float64 `json:"balance"`
+	AccountID     int64             `json:"account_id"`
+	Balance       float64           `json:"balance"`
+	Type          string            `json:"type"`
+	NormalBalance string            `json:"normal_balance"`
+	Status        string            `json:"status"`
+	Name          string            `json:"name"`
+	Owner         string            `json:"owner"`
+	Labels        map[string]string `json:"labels,omitempty"`
+	Version       int64             `json:"version"`
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+}
+
+type ListAccountsResponse struct {
+	Accounts []AccountResponse `json:"accounts"`
+	// NextCursor is passed as cursor to fetch the next page; it is omitted on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+type ImportAccountsResponse struct {
+	DryRun    bool                `json:"dry_run"`
+	Created   int                 `json:"created"`
+	Duplicate int                 `json:"duplicate"`
+	Invalid   int                 `json:"invalid"`
+	Rows      []ImportRowResponse `json:"rows"`
+}
+
+type ImportRowResponse struct {
+	Line      int    `json:"line"`
+	AccountID int64  `json:"account_id,omitempty"`
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+}
+
+type BalanceResponse struct {
+	AccountID int64     `json:"account_id"`
+	Balance   float64   `json:"balance"`
+	AsOf      time.Time `json:"as_of"`
 }
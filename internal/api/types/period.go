@@ -0,0 +1,41 @@
+package types
+
+import "time"
+
+type PeriodResponse struct {
+	// ID is the day of the period, e.g. 2025-01-31
+	ID           string                `json:"id"`
+	Start        time.Time             `json:"start"`
+	End          time.Time             `json:"end"`
+	Status       string                `json:"status"`
+	ClosedAt     *time.Time            `json:"closed_at,omitempty"`
+	ClosedBy     string                `json:"closed_by,omitempty"`
+	TrialBalance *TrialBalanceResponse `json:"trial_balance,omitempty"`
+}
+
+type TrialBalanceResponse struct {
+	Lines []TrialBalanceLineResponse `json:"lines"`
+	// OpeningBalances balances the initial balances accounts were opened with outside of minting
+	OpeningBalances OpeningBalancesResponse `json:"opening_balances"`
+	Debits          float64                 `json:"debits"`
+	Credits         float64                 `json:"credits"`
+	// Balanced reports whether debits equal credits to the cent
+	Balanced bool `json:"balanced"`
+}
+
+type TrialBalanceLineResponse struct {
+	AccountType string  `json:"account_type"`
+	Accounts    int64   `json:"accounts"`
+	Debit       float64 `json:"debit"`
+	Credit      float64 `json:"credit"`
+}
+
+type OpeningBalancesResponse struct {
+	Accounts int64   `json:"accounts"`
+	Debit    float64 `json:"debit"`
+	Credit   float64 `json:"credit"`
+}
+
+type ListPeriodsResponse struct {
+	Periods []PeriodResponse `json:"periods"`
+}
@@ -0,0 +1,28 @@
+package types
+
+import "time"
+
+type ReconciliationResponse struct {
+	StartedAt       time.Time `json:"started_at"`
+	FinishedAt      time.Time `json:"finished_at"`
+	AccountsChecked int64     `json:"accounts_checked"`
+	// AccountsUnverifiable counts the checked accounts without a recorded initial balance
+	AccountsUnverifiable int64                 `json:"accounts_unverifiable"`
+	Discrepancies        []DiscrepancyResponse `json:"discrepancies"`
+}
+
+type DiscrepancyResponse struct {
+	AccountID       int64   `json:"account_id"`
+	Balance         float64 `json:"balance"`
+	ExpectedBalance float64 `json:"expected_balance"`
+	Difference      float64 `json:"difference"`
+}
+
+type SupplyResponse struct {
+	AsOf        time.Time `json:"as_of"`
+	Accounts    int64     `json:"accounts"`
+	Circulating float64   `json:"circulating"`
+	Minted      float64   `json:"minted"`
+	Settled     float64   `json:"settled"`
+	Unfunded    float64   `json:"unfunded"`
+}
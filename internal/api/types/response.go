@@ -27,11 +27,16 @@ func WriteResponseError(w http.ResponseWriter, code int, msg string) {
 }
 
 func WriteResponseSuccess(w http.ResponseWriter, data interface{}) {
+	WriteResponse(w, http.StatusOK, "success", data)
+}
+
+// WriteResponse writes data in the standard response envelope with an arbitrary status code
+func WriteResponse(w http.ResponseWriter, code int, msg string, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
+	w.WriteHeader(code)
 	resp := SuccessResponse{
-		Code:    http.StatusOK,
-		Message: "success",
+		Code:    code,
+		Message: msg,
 		Data:    data,
 	}
 	_ = json.NewEncoder(w).Encode(resp)
@@ -1,15 +1,65 @@
 package types
 
+import "time"
+
 type TransactionRequest struct {
-	SourceAccountID      int64         `json:"source_account_id"`
-	DestinationAccountID int64         `json:"destination_account_id"`
-	Amount               FlexibleFloat `json:"amount"`
+	SourceAccountID      int64             `json:"source_account_id"`
+	DestinationAccountID int64             `json:"destination_account_id"`
+	Amount               FlexibleFloat     `json:"amount"`
+	Type                 string            `json:"type,omitempty"`
+	Description          string            `json:"description,omitempty"`
+	Metadata             map[string]string `json:"metadata,omitempty"`
+	ExternalReference    string            `json:"external_reference,omitempty"`
+	// EffectiveAt backdates the transaction into an open accounting period
+	EffectiveAt time.Time `json:"effective_at,omitempty"`
+}
+
+// MultiTransferRequest is the body of a multi-leg transfer; the details apply to every transaction recorded
+type MultiTransferRequest struct {
+	Legs              []TransferLeg     `json:"legs"`
+	Type              string            `json:"type,omitempty"`
+	Description       string            `json:"description,omitempty"`
+	Metadata          map[string]string `json:"metadata,omitempty"`
+	ExternalReference string            `json:"external_reference,omitempty"`
+	EffectiveAt       time.Time         `json:"effective_at,omitempty"`
+}
+
+// TransferLeg debits the account by a negative amount or credits it by a positive one
+type TransferLeg struct {
+	AccountID int64         `json:"account_id"`
+	Amount    FlexibleFloat `json:"amount"`
+}
+
+type MultiTransferResponse struct {
+	GroupID      int64                 `json:"group_id"`
+	Legs         []TransferLeg         `json:"legs"`
+	Transactions []TransactionResponse `json:"transactions"`
+	Timestamp    string                `json:"timestamp"`
+}
+
+// SettlementRequest is the body of a deposit or withdrawal
+type SettlementRequest struct {
+	Amount            FlexibleFloat `json:"amount"`
+	Source            string        `json:"source"`
+	ExternalReference string        `json:"external_reference"`
 }
 
 type TransactionResponse struct {
-	TransactionID        int64         `json:"transaction_id"`
-	SourceAccountID      int64         `json:"source_account_id"`
-	DestinationAccountID int64         `json:"destination_account_id"`
-	Amount               FlexibleFloat `json:"amount"`
-	Timestamp            string        `json:"timestamp"`
+	TransactionID        int64             `json:"transaction_id"`
+	SourceAccountID      int64             `json:"source_account_id"`
+	DestinationAccountID int64             `json:"destination_account_id"`
+	Amount               FlexibleFloat     `json:"amount"`
+	Type                 string            `json:"type"`
+	Description          string            `json:"description,omitempty"`
+	Metadata             map[string]string `json:"metadata,omitempty"`
+	ExternalSource       string            `json:"external_source,omitempty"`
+	ExternalReference    string            `json:"external_reference,omitempty"`
+	GroupID              int64             `json:"group_id,omitempty"`
+	Timestamp            string            `json:"timestamp"`
+}
+
+type ListTransactionsResponse struct {
+	Transactions []TransactionResponse `json:"transactions"`
+	// NextAfter is passed as after to fetch the next page; it is omitted on the last page
+	NextAfter int64 `json:"next_after,omitempty"`
 }
@@ -0,0 +1,8 @@
+package config
+
+// AccountsConfig decides how new accounts are identified
+type AccountsConfig struct {
+	// CallerIDs lets callers choose the id of the accounts they create or import, from 1 to 99999999999.
+	// Accounts created without an id always get one allocated by the server.
+	CallerIDs bool `yaml:"caller_ids" env:"ACCOUNT_CALLER_IDS"`
+}
@@ -0,0 +1,160 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"internal-transfers/internal/model"
+)
+
+// Config is the complete service configuration. Values are resolved, from lowest to highest precedence,
+// from the defaults, the optional YAML file named by CONFIG_FILE, the .env file and the process environment.
+type Config struct {
+	// Storage selects the repository backend: postgres, or memory for tests and local demos
+	Storage    string           `yaml:"storage" env:"STORAGE"`
+	Server     ServerConfig     `yaml:"server"`
+	DB         DBConfig         `yaml:"db"`
+	Log        LogConfig        `yaml:"log"`
+	Tracing    TracingConfig    `yaml:"tracing"`
+	Features   FeatureConfig    `yaml:"features"`
+	Jobs       JobsConfig       `yaml:"jobs"`
+	Accounts   AccountsConfig   `yaml:"accounts"`
+	Mint       MintConfig       `yaml:"mint"`
+	Settlement SettlementConfig `yaml:"settlement"`
+	Interest   InterestConfig   `yaml:"interest"`
+}
+
+const (
+	StoragePostgres = "postgres"
+	StorageMemory   = "memory"
+)
+
+type LogConfig struct {
+	Level  string `yaml:"level" env:"LOG_LEVEL"`
+	Format string `yaml:"format" env:"LOG_FORMAT"`
+}
+
+type TracingConfig struct {
+	// Exporter is one of otlp, stdout or none; the OTLP endpoint is configured via the standard OTEL_EXPORTER_OTLP_* variables
+	Exporter string `yaml:"exporter" env:"OTEL_TRACES_EXPORTER"`
+}
+
+type FeatureConfig struct {
+	// AutoMigrate applies pending migrations when the server starts
+	AutoMigrate bool `yaml:"auto_migrate" env:"AUTO_MIGRATE"`
+}
+
+// Default returns the configuration used for every setting that is not explicitly provided
+func Default() Config {
+	return Config{
+		Storage: StoragePostgres,
+		Server: ServerConfig{
+			Port:             8080,
+			ReadTimeout:      10 * time.Second,
+			WriteTimeout:     30 * time.Second,
+			IdleTimeout:      60 * time.Second,
+			ShutdownDelay:    0,
+			ShutdownTimeout:  30 * time.Second,
+			ReadinessTimeout: 2 * time.Second,
+		},
+		DB: DBConfig{
+			Port:              5432,
+			SSLMode:           "disable",
+			MaxOpenConns:      25,
+			MaxIdleConns:      25,
+			ConnMaxLifetime:   30 * time.Minute,
+			ConnMaxIdleTime:   5 * time.Minute,
+			StatementTimeout:  30 * time.Second,
+			ConnectAttempts:   10,
+			ConnectBackoff:    500 * time.Millisecond,
+			ConnectMaxBackoff: 10 * time.Second,
+			Isolation:         "read_committed",
+			TxMaxRetries:      3,
+			TxRetryBackoff:    10 * time.Millisecond,
+			TxMaxRetryBackoff: 200 * time.Millisecond,
+		},
+		Log: LogConfig{
+			Level:  "info",
+			Format: "console",
+		},
+		Tracing: TracingConfig{
+			Exporter: "none",
+		},
+		Accounts: AccountsConfig{
+			CallerIDs: true,
+		},
+		Jobs: JobsConfig{
+			SnapshotInterval:        time.Hour,
+			SnapshotDelay:           5 * time.Minute,
+			InterestAccrualInterval: time.Hour,
+			InterestPostingInterval: time.Hour,
+		},
+	}
+}
+
+// Validate reports every invalid setting at once
+func (c Config) Validate() error {
+	var errs []error
+	errs = append(errs, c.Server.validate()...)
+	switch c.Storage {
+	case StoragePostgres:
+		errs = append(errs, c.DB.validate()...)
+	case StorageMemory:
+	default:
+		errs = append(errs, fmt.Errorf("storage: must be postgres or memory, got %q", c.Storage))
+	}
+
+	if _, err := zerolog.ParseLevel(c.Log.Level); err != nil || c.Log.Level == "" {
+		errs = append(errs, fmt.Errorf("log.level: unknown level %q", c.Log.Level))
+	}
+	if c.Log.Format != "json" && c.Log.Format != "console" {
+		errs = append(errs, fmt.Errorf("log.format: must be json or console, got %q", c.Log.Format))
+	}
+	switch c.Tracing.Exporter {
+	case "none", "otlp", "stdout":
+	default:
+		errs = append(errs, fmt.Errorf("tracing.exporter: must be otlp, stdout or none, got %q", c.Tracing.Exporter))
+	}
+	errs = append(errs, c.Jobs.validate()...)
+	errs = append(errs, c.Mint.validate()...)
+	errs = append(errs, c.Settlement.validate()...)
+	for source, id := range c.Settlement.AccountIDs() {
+		if id == c.Mint.AccountID {
+			errs = append(errs, fmt.Errorf("settlement.accounts: source %q uses the mint account", source))
+		}
+		if model.SeededAccountID(id) {
+			errs = append(errs, fmt.Errorf("settlement.accounts: source %q uses seeded account %d", source, id))
+		} else if id > model.MaxCallerAccountID {
+			errs = append(errs, fmt.Errorf("settlement.accounts: source %q uses account %d, above %d where ids are allocated",
+				source, id, model.MaxCallerAccountID))
+		}
+	}
+	if model.SeededAccountID(c.Mint.AccountID) {
+		errs = append(errs, fmt.Errorf("mint.account_id: %d is a seeded account", c.Mint.AccountID))
+	} else if c.Mint.AccountID > model.MaxCallerAccountID {
+		errs = append(errs, fmt.Errorf("mint.account_id: must be at most %d, larger ids are allocated", model.MaxCallerAccountID))
+	}
+	errs = append(errs, c.Interest.validate()...)
+	settlementIDs := slices.Collect(maps.Values(c.Settlement.AccountIDs()))
+	for id := range c.Interest.Enrollments() {
+		if id == c.Mint.AccountID || model.SeededAccountID(id) || slices.Contains(settlementIDs, id) {
+			errs = append(errs, fmt.Errorf("interest.accounts: account %d is a system account", id))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Redacted returns a copy of the config that is safe to print
+func (c Config) Redacted() Config {
+	if c.DB.Password != "" {
+		c.DB.Password = redacted
+	}
+	return c
+}
+
+const redacted = "[REDACTED]"
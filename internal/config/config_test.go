@@ -0,0 +1,207 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"internal-transfers/internal/model"
+)
+
+func setRequiredDBEnv(t *testing.T) {
+	t.Setenv("DB_HOST", "localhost")
+	t.Setenv("DB_USER", "postgres")
+	t.Setenv("DB_NAME", "internal_transfers")
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		// given
+		setRequiredDBEnv(t)
+
+		// when
+		cfg, err := Load()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 8080, cfg.Server.Port)
+		assert.Equal(t, 5432, cfg.DB.Port)
+		assert.Equal(t, "disable", cfg.DB.SSLMode)
+		assert.Equal(t, "info", cfg.Log.Level)
+		assert.Equal(t, "read_committed", cfg.DB.Isolation)
+	})
+
+	t.Run("env overrides yaml file", func(t *testing.T) {
+		// given
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		yamlCfg := "server:\n  port: 9000\n  read_timeout: 5s\ndb:\n  host: yaml-host\n  max_open_conns: 10\n  max_idle_conns: 5\n"
+		require.NoError(t, os.WriteFile(path, []byte(yamlCfg), 0o600))
+		setRequiredDBEnv(t)
+		t.Setenv("CONFIG_FILE", path)
+		t.Setenv("PORT", "9100")
+
+		// when
+		cfg, err := Load()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 9100, cfg.Server.Port)
+		assert.Equal(t, 5*time.Second, cfg.Server.ReadTimeout)
+		assert.Equal(t, "localhost", cfg.DB.Host)
+		assert.Equal(t, 10, cfg.DB.MaxOpenConns)
+	})
+
+	t.Run("list from env", func(t *testing.T) {
+		// given
+		setRequiredDBEnv(t)
+		t.Setenv("MINT_ACCOUNT_ID", "1")
+		t.Setenv("MINT_ALLOWED_CALLERS", "onboarding, backoffice,")
+
+		// when
+		cfg, err := Load()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), cfg.Mint.AccountID)
+		assert.Equal(t, []string{"onboarding", "backoffice"}, cfg.Mint.AllowedCallers)
+	})
+
+	t.Run("caller-chosen account ids", func(t *testing.T) {
+		// given
+		setRequiredDBEnv(t)
+
+		// when
+		cfg, err := Load()
+		require.NoError(t, err)
+		t.Setenv("ACCOUNT_CALLER_IDS", "false")
+		disabled, err := Load()
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, cfg.Accounts.CallerIDs)
+		assert.False(t, disabled.Accounts.CallerIDs)
+	})
+
+	t.Run("settlement accounts", func(t *testing.T) {
+		// given
+		setRequiredDBEnv(t)
+		t.Setenv("SETTLEMENT_ACCOUNTS", "bank=9001, card=9002")
+
+		// when
+		cfg, err := Load()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int64{"bank": 9001, "card": 9002}, cfg.Settlement.AccountIDs())
+	})
+
+	t.Run("interest products", func(t *testing.T) {
+		// given
+		setRequiredDBEnv(t)
+		t.Setenv("INTEREST_PRODUCTS", "savings=0.035:actual/365:monthly, notice=0.05:30/360:daily")
+		t.Setenv("INTEREST_ACCOUNTS", "1001=savings, 1002=notice")
+
+		// when
+		cfg, err := Load()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, map[int64]model.InterestProduct{
+			1001: {Name: "savings", Rate: 0.035, DayCount: model.DayCountActual365, Compounding: model.CompoundingMonthly},
+			1002: {Name: "notice", Rate: 0.05, DayCount: model.DayCount30360, Compounding: model.CompoundingDaily},
+		}, cfg.Interest.Enrollments())
+	})
+
+	t.Run("invalid interest products", func(t *testing.T) {
+		// given
+		setRequiredDBEnv(t)
+		t.Setenv("INTEREST_PRODUCTS", "savings=3.5:actual/365:monthly, notice=0.05:30/365:daily")
+		t.Setenv("INTEREST_ACCOUNTS", "1001=savings, 9000000000000000003=bonus")
+
+		// when
+		_, err := Load()
+
+		// then
+		assert.ErrorContains(t, err, `rate of "savings" must be a fraction`)
+		assert.ErrorContains(t, err, `unknown day count "30/365" for "notice"`)
+		assert.ErrorContains(t, err, `account 9000000000000000003 uses unknown product "bonus"`)
+	})
+
+	t.Run("unknown yaml field", func(t *testing.T) {
+		// given
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("db:\n  hots: typo\n"), 0o600))
+		t.Setenv("CONFIG_FILE", path)
+
+		// when
+		_, err := Load()
+
+		// then
+		assert.ErrorContains(t, err, "field hots not found")
+	})
+
+	t.Run("reports all errors together", func(t *testing.T) {
+		// given
+		t.Setenv("DB_HOST", "")
+		t.Setenv("DB_PORT", "not-a-port")
+		t.Setenv("HTTP_READ_TIMEOUT", "-1s")
+		t.Setenv("LOG_FORMAT", "xml")
+		t.Setenv("DB_ISOLATION_LEVEL", "snapshot")
+		t.Setenv("SETTLEMENT_ACCOUNTS", "bank")
+
+		// when
+		_, err := Load()
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "DB_PORT")
+		assert.ErrorContains(t, err, "db.host: required")
+		assert.ErrorContains(t, err, "server.read_timeout: must be positive")
+		assert.ErrorContains(t, err, "log.format")
+		assert.ErrorContains(t, err, "db.isolation")
+		assert.ErrorContains(t, err, "settlement.accounts")
+	})
+
+	t.Run("system accounts must not be seeded accounts", func(t *testing.T) {
+		// given
+		t.Setenv("MINT_ACCOUNT_ID", "9000000000000000001")
+		t.Setenv("SETTLEMENT_ACCOUNTS", "bank=9000000000000000002")
+
+		// when
+		_, err := Load()
+
+		// then
+		assert.ErrorContains(t, err, "mint.account_id: 9000000000000000001 is a seeded account")
+		assert.ErrorContains(t, err, `source "bank" uses seeded account 9000000000000000002`)
+	})
+
+	t.Run("system accounts must not take allocated ids", func(t *testing.T) {
+		// given
+		t.Setenv("MINT_ACCOUNT_ID", "100000000008")
+		t.Setenv("SETTLEMENT_ACCOUNTS", "bank=100000000016")
+
+		// when
+		_, err := Load()
+
+		// then
+		assert.ErrorContains(t, err, "mint.account_id: must be at most 99999999999")
+		assert.ErrorContains(t, err, `source "bank" uses account 100000000016, above 99999999999`)
+	})
+}
+
+func TestConfig_Redacted(t *testing.T) {
+	// given
+	cfg := Default()
+	cfg.DB.Password = "hunter2"
+
+	// when
+	got := cfg.Redacted()
+
+	// then
+	assert.Equal(t, redacted, got.DB.Password)
+	assert.Equal(t, "hunter2", cfg.DB.Password)
+}
@@ -1,21 +1,121 @@
 package config
 
-import "os"
+import (
+	"fmt"
+	"time"
+)
 
 type DBConfig struct {
-	Host     string
-	Port     string
-	User     string
-	Password string
-	Name     string
+	Host     string `yaml:"host" env:"DB_HOST"`
+	Port     int    `yaml:"port" env:"DB_PORT"`
+	User     string `yaml:"user" env:"DB_USER"`
+	Password string `yaml:"password" env:"DB_PASSWORD"`
+	Name     string `yaml:"name" env:"DB_NAME"`
+
+	SSLMode string `yaml:"sslmode" env:"DB_SSLMODE"`
+	// SSLRootCert is the CA bundle used to verify the server under verify-ca and verify-full
+	SSLRootCert string `yaml:"sslrootcert" env:"DB_SSLROOTCERT"`
+	// SSLCert and SSLKey enable client certificate authentication when both are set
+	SSLCert string `yaml:"sslcert" env:"DB_SSLCERT"`
+	SSLKey  string `yaml:"sslkey" env:"DB_SSLKEY"`
+
+	MaxOpenConns    int           `yaml:"max_open_conns" env:"DB_MAX_OPEN_CONNS"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time" env:"DB_CONN_MAX_IDLE_TIME"`
+
+	// StatementTimeout aborts any single statement running longer than this; 0 disables the limit
+	StatementTimeout time.Duration `yaml:"statement_timeout" env:"DB_STATEMENT_TIMEOUT"`
+
+	// ConnectAttempts is how many times to ping the database at startup before giving up
+	ConnectAttempts int `yaml:"connect_attempts" env:"DB_CONNECT_ATTEMPTS"`
+	// ConnectBackoff is the wait after the first failed attempt; it doubles on every retry up to ConnectMaxBackoff
+	ConnectBackoff    time.Duration `yaml:"connect_backoff" env:"DB_CONNECT_BACKOFF"`
+	ConnectMaxBackoff time.Duration `yaml:"connect_max_backoff" env:"DB_CONNECT_MAX_BACKOFF"`
+
+	// Isolation is the isolation level of units of work: read_committed, repeatable_read or serializable
+	Isolation string `yaml:"isolation" env:"DB_ISOLATION_LEVEL"`
+	// TxMaxRetries is how many times a unit of work aborted by a serialization failure or deadlock is re-run
+	TxMaxRetries int `yaml:"tx_max_retries" env:"DB_TX_MAX_RETRIES"`
+	// TxRetryBackoff caps the jittered wait before the first retry; the cap doubles on every retry up to TxMaxRetryBackoff
+	TxRetryBackoff    time.Duration `yaml:"tx_retry_backoff" env:"DB_TX_RETRY_BACKOFF"`
+	TxMaxRetryBackoff time.Duration `yaml:"tx_max_retry_backoff" env:"DB_TX_MAX_RETRY_BACKOFF"`
+}
+
+var validSSLModes = map[string]bool{
+	"disable":     true,
+	"require":     true,
+	"verify-ca":   true,
+	"verify-full": true,
 }
 
-func GetDBConfig() DBConfig {
-	return DBConfig{
-		Host:     os.Getenv("DB_HOST"),
-		Port:     os.Getenv("DB_PORT"),
-		User:     os.Getenv("DB_USER"),
-		Password: os.Getenv("DB_PASSWORD"),
-		Name:     os.Getenv("DB_NAME"),
+var validIsolationLevels = map[string]bool{
+	"read_committed":  true,
+	"repeatable_read": true,
+	"serializable":    true,
+}
+
+func (c DBConfig) validate() []error {
+	var errs []error
+	if c.Host == "" {
+		errs = append(errs, fmt.Errorf("db.host: required"))
+	}
+	if c.Port < 1 || c.Port > 65535 {
+		errs = append(errs, fmt.Errorf("db.port: must be between 1 and 65535, got %d", c.Port))
+	}
+	if c.User == "" {
+		errs = append(errs, fmt.Errorf("db.user: required"))
+	}
+	if c.Name == "" {
+		errs = append(errs, fmt.Errorf("db.name: required"))
+	}
+	if !validSSLModes[c.SSLMode] {
+		errs = append(errs, fmt.Errorf("db.sslmode: must be one of disable, require, verify-ca, verify-full, got %q", c.SSLMode))
+	}
+	if c.MaxOpenConns < 0 {
+		errs = append(errs, fmt.Errorf("db.max_open_conns: must not be negative"))
+	}
+	if c.MaxIdleConns < 0 {
+		errs = append(errs, fmt.Errorf("db.max_idle_conns: must not be negative"))
+	}
+	if c.MaxOpenConns > 0 && c.MaxIdleConns > c.MaxOpenConns {
+		errs = append(errs, fmt.Errorf("db.max_idle_conns: must not exceed max_open_conns (%d)", c.MaxOpenConns))
+	}
+	if (c.SSLMode == "verify-ca" || c.SSLMode == "verify-full") && c.SSLRootCert == "" {
+		errs = append(errs, fmt.Errorf("db.sslrootcert: required when sslmode is %s", c.SSLMode))
+	}
+	if (c.SSLCert == "") != (c.SSLKey == "") {
+		errs = append(errs, fmt.Errorf("db.sslcert and db.sslkey: must be set together"))
+	}
+	if c.ConnMaxLifetime < 0 {
+		errs = append(errs, fmt.Errorf("db.conn_max_lifetime: must not be negative"))
+	}
+	if c.ConnMaxIdleTime < 0 {
+		errs = append(errs, fmt.Errorf("db.conn_max_idle_time: must not be negative"))
+	}
+	if c.StatementTimeout < 0 {
+		errs = append(errs, fmt.Errorf("db.statement_timeout: must not be negative"))
+	}
+	if c.ConnectAttempts < 1 {
+		errs = append(errs, fmt.Errorf("db.connect_attempts: must be at least 1"))
+	}
+	if c.ConnectBackoff <= 0 {
+		errs = append(errs, fmt.Errorf("db.connect_backoff: must be positive"))
+	}
+	if c.ConnectMaxBackoff < c.ConnectBackoff {
+		errs = append(errs, fmt.Errorf("db.connect_max_backoff: must not be less than connect_backoff"))
+	}
+	if !validIsolationLevels[c.Isolation] {
+		errs = append(errs, fmt.Errorf("db.isolation: must be one of read_committed, repeatable_read, serializable, got %q", c.Isolation))
+	}
+	if c.TxMaxRetries < 0 {
+		errs = append(errs, fmt.Errorf("db.tx_max_retries: must not be negative"))
+	}
+	if c.TxRetryBackoff < 0 {
+		errs = append(errs, fmt.Errorf("db.tx_retry_backoff: must not be negative"))
+	}
+	if c.TxMaxRetryBackoff < c.TxRetryBackoff {
+		errs = append(errs, fmt.Errorf("db.tx_max_retry_backoff: must not be less than tx_retry_backoff"))
 	}
+	return errs
 }
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"internal-transfers/internal/model"
+)
+
+// InterestConfig defines the interest products and the accounts that earn them
+type InterestConfig struct {
+	// Products lists name=rate:day_count:compounding entries, e.g. savings=0.035:actual/365:monthly, where rate
+	// is the annual rate as a fraction
+	Products []string `yaml:"products" env:"INTEREST_PRODUCTS"`
+	// Accounts lists account_id=product pairs, e.g. 1001=savings
+	Accounts []string `yaml:"accounts" env:"INTEREST_ACCOUNTS"`
+}
+
+// Enrollments returns the product of each enrolled account; it assumes the configuration is valid
+func (c InterestConfig) Enrollments() map[int64]model.InterestProduct {
+	enrollments, _ := c.parse()
+	return enrollments
+}
+
+func (c InterestConfig) parse() (map[int64]model.InterestProduct, []error) {
+	products, errs := c.parseProducts()
+	enrollments := make(map[int64]model.InterestProduct, len(c.Accounts))
+	for _, entry := range c.Accounts {
+		rawID, name, ok := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		id, err := strconv.ParseInt(strings.TrimSpace(rawID), 10, 64)
+		product, known := products[name]
+		switch {
+		case !ok || name == "" || err != nil || id <= 0:
+			errs = append(errs, fmt.Errorf("interest.accounts: expected account_id=product, got %q", entry))
+		case !known:
+			errs = append(errs, fmt.Errorf("interest.accounts: account %d uses unknown product %q", id, name))
+		case enrollments[id].Name != "":
+			errs = append(errs, fmt.Errorf("interest.accounts: account %d is listed twice", id))
+		default:
+			enrollments[id] = product
+		}
+	}
+	return enrollments, errs
+}
+
+func (c InterestConfig) parseProducts() (map[string]model.InterestProduct, []error) {
+	var errs []error
+	products := make(map[string]model.InterestProduct, len(c.Products))
+	for _, entry := range c.Products {
+		name, terms, ok := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		parts := strings.Split(terms, ":")
+		if !ok || name == "" || len(parts) != 3 {
+			errs = append(errs, fmt.Errorf("interest.products: expected name=rate:day_count:compounding, got %q", entry))
+			continue
+		}
+		if _, ok := products[name]; ok {
+			errs = append(errs, fmt.Errorf("interest.products: product %q is listed twice", name))
+			continue
+		}
+
+		product := model.InterestProduct{
+			Name:        name,
+			DayCount:    model.DayCount(strings.TrimSpace(parts[1])),
+			Compounding: model.Compounding(strings.TrimSpace(parts[2])),
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+		if err != nil || rate < 0 || rate >= 1 {
+			errs = append(errs, fmt.Errorf("interest.products: rate of %q must be a fraction in [0, 1), got %q", name, parts[0]))
+			continue
+		}
+		product.Rate = rate
+		if !slices.Contains(model.DayCounts, product.DayCount) {
+			errs = append(errs, fmt.Errorf("interest.products: unknown day count %q for %q", product.DayCount, name))
+			continue
+		}
+		if !slices.Contains(model.Compoundings, product.Compounding) {
+			errs = append(errs, fmt.Errorf("interest.products: unknown compounding %q for %q", product.Compounding, name))
+			continue
+		}
+		products[name] = product
+	}
+	return products, errs
+}
+
+func (c InterestConfig) validate() []error {
+	_, errs := c.parse()
+	return errs
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// JobsConfig schedules the background jobs run by the server
+type JobsConfig struct {
+	// SnapshotInterval is how often to check for a missing daily balance snapshot; 0 disables snapshots
+	SnapshotInterval time.Duration `yaml:"snapshot_interval" env:"SNAPSHOT_INTERVAL"`
+	// SnapshotDelay is how long after midnight UTC a day's snapshot becomes due, so late commits are included
+	SnapshotDelay time.Duration `yaml:"snapshot_delay" env:"SNAPSHOT_DELAY"`
+	// ReconcileInterval is how often to check balances against the transaction history; 0 disables the job
+	ReconcileInterval time.Duration `yaml:"reconcile_interval" env:"RECONCILE_INTERVAL"`
+	// PeriodCloseInterval is how often to close the accounting periods of the days that ended since the last
+	// close, after SnapshotDelay like snapshots; 0 disables the job, leaving periods to be closed by hand
+	PeriodCloseInterval time.Duration `yaml:"period_close_interval" env:"PERIOD_CLOSE_INTERVAL"`
+	// InterestAccrualInterval is how often to accrue interest for the days that ended since the last run;
+	// 0 disables accrual. Days become due after SnapshotDelay, like snapshots.
+	InterestAccrualInterval time.Duration `yaml:"interest_accrual_interval" env:"INTEREST_ACCRUAL_INTERVAL"`
+	// InterestPostingInterval is how often to pay the interest of ended periods; 0 disables posting
+	InterestPostingInterval time.Duration `yaml:"interest_posting_interval" env:"INTEREST_POSTING_INTERVAL"`
+}
+
+func (c JobsConfig) validate() []error {
+	var errs []error
+	if c.SnapshotInterval < 0 {
+		errs = append(errs, fmt.Errorf("jobs.snapshot_interval: must not be negative"))
+	}
+	if c.SnapshotDelay < 0 {
+		errs = append(errs, fmt.Errorf("jobs.snapshot_delay: must not be negative"))
+	}
+	if c.ReconcileInterval < 0 {
+		errs = append(errs, fmt.Errorf("jobs.reconcile_interval: must not be negative"))
+	}
+	if c.PeriodCloseInterval < 0 {
+		errs = append(errs, fmt.Errorf("jobs.period_close_interval: must not be negative"))
+	}
+	if c.InterestAccrualInterval < 0 {
+		errs = append(errs, fmt.Errorf("jobs.interest_accrual_interval: must not be negative"))
+	}
+	if c.InterestPostingInterval < 0 {
+		errs = append(errs, fmt.Errorf("jobs.interest_posting_interval: must not be negative"))
+	}
+	return errs
+}
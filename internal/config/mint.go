@@ -0,0 +1,22 @@
+package config
+
+import "fmt"
+
+// MintConfig designates the system account that funds the initial balance of new accounts
+type MintConfig struct {
+	// AccountID is the mint account; 0 disables minting and new accounts start with an unfunded balance
+	AccountID int64 `yaml:"account_id" env:"MINT_ACCOUNT_ID"`
+	// AllowedCallers lists the X-Caller-ID values that may create accounts with a balance while minting is enabled
+	AllowedCallers []string `yaml:"allowed_callers" env:"MINT_ALLOWED_CALLERS"`
+}
+
+func (c MintConfig) validate() []error {
+	var errs []error
+	if c.AccountID < 0 {
+		errs = append(errs, fmt.Errorf("mint.account_id: must not be negative"))
+	}
+	if c.AccountID == 0 && len(c.AllowedCallers) > 0 {
+		errs = append(errs, fmt.Errorf("mint.allowed_callers: requires mint.account_id"))
+	}
+	return errs
+}
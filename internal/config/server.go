@@ -0,0 +1,46 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+type ServerConfig struct {
+	Port         int           `yaml:"port" env:"PORT"`
+	ReadTimeout  time.Duration `yaml:"read_timeout" env:"HTTP_READ_TIMEOUT"`
+	WriteTimeout time.Duration `yaml:"write_timeout" env:"HTTP_WRITE_TIMEOUT"`
+	IdleTimeout  time.Duration `yaml:"idle_timeout" env:"HTTP_IDLE_TIMEOUT"`
+	// ShutdownDelay is how long readiness fails before the listener closes, giving load balancers time to react
+	ShutdownDelay time.Duration `yaml:"shutdown_delay" env:"SHUTDOWN_DELAY"`
+	// ShutdownTimeout bounds how long in-flight requests and background workers may take to drain
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT"`
+	// ReadinessTimeout bounds each readiness check so a hung dependency cannot stall the probe
+	ReadinessTimeout time.Duration `yaml:"readiness_timeout" env:"READINESS_TIMEOUT"`
+}
+
+func (c ServerConfig) validate() []error {
+	var errs []error
+	if c.Port < 1 || c.Port > 65535 {
+		errs = append(errs, fmt.Errorf("server.port: must be between 1 and 65535, got %d", c.Port))
+	}
+
+	positive := []struct {
+		name string
+		val  time.Duration
+	}{
+		{"server.read_timeout", c.ReadTimeout},
+		{"server.write_timeout", c.WriteTimeout},
+		{"server.idle_timeout", c.IdleTimeout},
+		{"server.shutdown_timeout", c.ShutdownTimeout},
+		{"server.readiness_timeout", c.ReadinessTimeout},
+	}
+	for _, d := range positive {
+		if d.val <= 0 {
+			errs = append(errs, fmt.Errorf("%s: must be positive", d.name))
+		}
+	}
+	if c.ShutdownDelay < 0 {
+		errs = append(errs, fmt.Errorf("server.shutdown_delay: must not be negative"))
+	}
+	return errs
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SettlementConfig maps each external source system to the settlement account its deposits and withdrawals
+// clear through
+type SettlementConfig struct {
+	// Accounts lists source=account_id pairs, e.g. bank=9001
+	Accounts []string `yaml:"accounts" env:"SETTLEMENT_ACCOUNTS"`
+}
+
+// AccountIDs returns the settlement account of each source; it assumes the configuration is valid
+func (c SettlementConfig) AccountIDs() map[string]int64 {
+	ids, _ := c.parse()
+	return ids
+}
+
+func (c SettlementConfig) parse() (map[string]int64, []error) {
+	var errs []error
+	ids := make(map[string]int64, len(c.Accounts))
+	for _, entry := range c.Accounts {
+		source, rawID, ok := strings.Cut(entry, "=")
+		source = strings.TrimSpace(source)
+		id, err := strconv.ParseInt(strings.TrimSpace(rawID), 10, 64)
+		switch {
+		case !ok || source == "" || err != nil || id <= 0:
+			errs = append(errs, fmt.Errorf("settlement.accounts: expected source=account_id, got %q", entry))
+		case ids[source] != 0:
+			errs = append(errs, fmt.Errorf("settlement.accounts: source %q is listed twice", source))
+		default:
+			ids[source] = id
+		}
+	}
+	return ids, errs
+}
+
+func (c SettlementConfig) validate() []error {
+	_, errs := c.parse()
+	return errs
+}
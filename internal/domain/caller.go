@@ -0,0 +1,16 @@
+package domain
+
+import "context"
+
+type callerKey struct{}
+
+// WithCaller returns a copy of ctx identifying the system that made the request
+func WithCaller(ctx context.Context, caller string) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+// CallerFromContext returns the caller set by WithCaller, or an empty string if there is none
+func CallerFromContext(ctx context.Context) string {
+	caller, _ := ctx.Value(callerKey{}).(string)
+	return caller
+}
@@ -3,7 +3,30 @@ package domain
 import "errors"
 
 var (
-	ErrAccountDuplicate  = errors.New("account already exists")
-	ErrAccountNotFound   = errors.New("account not found")
+	ErrAccountDuplicate = errors.New("account already exists")
+	ErrAccountNotFound  = errors.New("account not found")
+	// ErrAccountClosed means a posting touches an account that is closed
+	ErrAccountClosed     = errors.New("account is closed")
 	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrSameAccount       = errors.New("source and destination accounts must differ")
+	ErrInvalidPeriod     = errors.New("period start must be before its end")
+	// ErrConcurrentUpdate means the operation kept conflicting with concurrent updates and may succeed if retried later
+	ErrConcurrentUpdate = errors.New("conflicting concurrent update")
+	// ErrMintNotAllowed means the caller may not fund accounts from the mint account
+	ErrMintNotAllowed = errors.New("caller is not allowed to mint")
+	// ErrDuplicateReference means the external system has already posted a transaction with the same reference
+	ErrDuplicateReference = errors.New("external reference already posted")
+	ErrUnknownSource      = errors.New("unknown external source")
+	// ErrInvalidTransaction is wrapped with the reason a transaction's details were rejected
+	ErrInvalidTransaction = errors.New("invalid transaction")
+	// ErrInvalidAccount is wrapped with the reason an account's details were rejected
+	ErrInvalidAccount = errors.New("invalid account")
+	// ErrAccountVersionMismatch means the account was modified since the version the caller based its update on
+	ErrAccountVersionMismatch = errors.New("account version mismatch")
+	// ErrInvalidAccountType means the type is not in the chart of accounts or cannot be opened by callers
+	ErrInvalidAccountType = errors.New("invalid account type")
+	// ErrPeriodClosed means an entry or close falls into an accounting period that is already closed
+	ErrPeriodClosed = errors.New("accounting period is closed")
+	// ErrPeriodNotClosable means the period ended less than the snapshot delay ago or an earlier period is still open
+	ErrPeriodNotClosable = errors.New("accounting period cannot be closed")
 )
@@ -0,0 +1,73 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecker_Check(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("all checks up", func(t *testing.T) {
+		// given
+		c := NewChecker(time.Second)
+		c.Register("database", func(ctx context.Context) error { return nil })
+
+		// when
+		report := c.Check(ctx)
+
+		// then
+		assert.Equal(t, StatusUp, report.Status)
+		require.Len(t, report.Checks, 2)
+		assert.Equal(t, "shutdown", report.Checks[0].Name)
+		assert.Equal(t, "database", report.Checks[1].Name)
+	})
+
+	t.Run("failing check", func(t *testing.T) {
+		// given
+		c := NewChecker(time.Second)
+		c.Register("database", func(ctx context.Context) error { return errors.New("connection refused") })
+
+		// when
+		report := c.Check(ctx)
+
+		// then
+		assert.Equal(t, StatusDown, report.Status)
+		assert.Equal(t, StatusDown, report.Checks[1].Status)
+		assert.Equal(t, "connection refused", report.Checks[1].Error)
+	})
+
+	t.Run("check exceeding timeout", func(t *testing.T) {
+		// given
+		c := NewChecker(10 * time.Millisecond)
+		c.Register("database", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		// when
+		report := c.Check(ctx)
+
+		// then
+		assert.Equal(t, StatusDown, report.Status)
+		assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks[1].Error)
+	})
+
+	t.Run("shutting down", func(t *testing.T) {
+		// given
+		c := NewChecker(time.Second)
+		c.SetShuttingDown()
+
+		// when
+		report := c.Check(ctx)
+
+		// then
+		assert.Equal(t, StatusDown, report.Status)
+		assert.Equal(t, errShuttingDown.Error(), report.Checks[0].Error)
+	})
+}
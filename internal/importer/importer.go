@@ -0,0 +1,161 @@
+// Package importer parses bulk account import files
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Format is the encoding of an import file
+type Format string
+
+const (
+	// FormatCSV is account_id,initial_balance per line, with an optional header row
+	FormatCSV Format = "csv"
+	// FormatJSONL is one {"account_id": ..., "initial_balance": ...} object per line
+	FormatJSONL Format = "jsonl"
+)
+
+// maxLineBytes bounds a single JSONL line
+const maxLineBytes = 64 * 1024
+
+// ParseFormat returns the Format named s
+func ParseFormat(s string) (Format, error) {
+	switch Format(strings.ToLower(s)) {
+	case FormatCSV:
+		return FormatCSV, nil
+	case FormatJSONL:
+		return FormatJSONL, nil
+	default:
+		return "", fmt.Errorf("unknown import format %q, expected csv or jsonl", s)
+	}
+}
+
+// Record is one data row of an import file; Err is set when the row could not be parsed
+type Record struct {
+	// Line is the 1-based line the row starts on
+	Line           int
+	AccountID      int64
+	InitialBalance float64
+	Err            error
+}
+
+// Parse reads every row of r. Malformed rows are returned with Err set rather than stopping the parse;
+// an error is only returned when r itself cannot be read.
+func Parse(r io.Reader, format Format) ([]Record, error) {
+	switch format {
+	case FormatCSV:
+		return parseCSV(r)
+	case FormatJSONL:
+		return parseJSONL(r)
+	default:
+		return nil, fmt.Errorf("unknown import format %q", format)
+	}
+}
+
+func parseCSV(r io.Reader) ([]Record, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1 // checked per row so one bad row does not fail the file
+	reader.TrimLeadingSpace = true
+
+	var records []Record
+	for {
+		fields, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			records = append(records, Record{Line: parseErr.StartLine, Err: parseErr.Err})
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read csv: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		if len(records) == 0 && line == 1 && strings.EqualFold(strings.TrimSpace(fields[0]), "account_id") {
+			continue // header
+		}
+		rec := Record{Line: line}
+		if len(fields) != 2 {
+			rec.Err = fmt.Errorf("expected 2 fields, got %d", len(fields))
+		} else {
+			rec.AccountID, rec.InitialBalance, rec.Err = parseFields(fields[0], fields[1])
+		}
+		records = append(records, rec)
+	}
+}
+
+type jsonRow struct {
+	AccountID      *json.Number    `json:"account_id"`
+	InitialBalance json.RawMessage `json:"initial_balance"`
+}
+
+func parseJSONL(r io.Reader) ([]Record, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), maxLineBytes)
+
+	var records []Record
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		records = append(records, parseJSONRow(line, text))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read jsonl: %w", err)
+	}
+	return records, nil
+}
+
+func parseJSONRow(line int, text []byte) Record {
+	rec := Record{Line: line}
+
+	var row jsonRow
+	dec := json.NewDecoder(bytes.NewReader(text))
+	dec.UseNumber()
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&row); err != nil {
+		rec.Err = fmt.Errorf("invalid json: %w", err)
+		return rec
+	}
+	if row.AccountID == nil {
+		rec.Err = errors.New("account_id is required")
+		return rec
+	}
+	if row.InitialBalance == nil {
+		rec.Err = errors.New("initial_balance is required")
+		return rec
+	}
+
+	// initial_balance may be a number or a string, as in POST /accounts
+	balance := string(row.InitialBalance)
+	var s string
+	if err := json.Unmarshal(row.InitialBalance, &s); err == nil {
+		balance = s
+	}
+	rec.AccountID, rec.InitialBalance, rec.Err = parseFields(row.AccountID.String(), balance)
+	return rec
+}
+
+func parseFields(accountID, balance string) (int64, float64, error) {
+	id, err := strconv.ParseInt(strings.TrimSpace(accountID), 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid account_id %q", accountID)
+	}
+	amount, err := strconv.ParseFloat(strings.TrimSpace(balance), 64)
+	if err != nil || math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return id, 0, fmt.Errorf("invalid initial_balance %q", balance)
+	}
+	return id, amount, nil
+}
@@ -0,0 +1,92 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_CSV(t *testing.T) {
+	t.Run("with header", func(t *testing.T) {
+		// given
+		input := "account_id,initial_balance\n1,100.5\n2, 20\n"
+
+		// when
+		records, err := Parse(strings.NewReader(input), FormatCSV)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []Record{
+			{Line: 2, AccountID: 1, InitialBalance: 100.5},
+			{Line: 3, AccountID: 2, InitialBalance: 20},
+		}, records)
+	})
+
+	t.Run("invalid rows are reported", func(t *testing.T) {
+		// given
+		input := "1,100\nabc,5\n3\n4,NaN\n5,\"unterminated\n"
+
+		// when
+		records, err := Parse(strings.NewReader(input), FormatCSV)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, records, 5)
+		assert.NoError(t, records[0].Err)
+		assert.ErrorContains(t, records[1].Err, "invalid account_id")
+		assert.ErrorContains(t, records[2].Err, "expected 2 fields")
+		assert.ErrorContains(t, records[3].Err, "invalid initial_balance")
+		assert.Equal(t, int64(4), records[3].AccountID)
+		assert.Error(t, records[4].Err)
+		assert.Equal(t, 5, records[4].Line)
+	})
+}
+
+func TestParse_JSONL(t *testing.T) {
+	t.Run("numbers and strings", func(t *testing.T) {
+		// given
+		input := `{"account_id": 1, "initial_balance": 100.5}` + "\n\n" + `{"account_id": 2, "initial_balance": "20"}` + "\n"
+
+		// when
+		records, err := Parse(strings.NewReader(input), FormatJSONL)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []Record{
+			{Line: 1, AccountID: 1, InitialBalance: 100.5},
+			{Line: 3, AccountID: 2, InitialBalance: 20},
+		}, records)
+	})
+
+	t.Run("invalid rows are reported", func(t *testing.T) {
+		// given
+		input := "{not json\n" +
+			`{"initial_balance": 1}` + "\n" +
+			`{"account_id": 1}` + "\n" +
+			`{"account_id": 1.5, "initial_balance": 1}` + "\n" +
+			`{"account_id": 1, "initial_balance": 1, "extra": true}` + "\n"
+
+		// when
+		records, err := Parse(strings.NewReader(input), FormatJSONL)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, records, 5)
+		assert.ErrorContains(t, records[0].Err, "invalid json")
+		assert.ErrorContains(t, records[1].Err, "account_id is required")
+		assert.ErrorContains(t, records[2].Err, "initial_balance is required")
+		assert.ErrorContains(t, records[3].Err, "invalid account_id")
+		assert.ErrorContains(t, records[4].Err, "unknown field")
+	})
+}
+
+func TestParseFormat(t *testing.T) {
+	f, err := ParseFormat("CSV")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatCSV, f)
+
+	_, err = ParseFormat("xml")
+	assert.Error(t, err)
+}
@@ -0,0 +1,167 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "internal_transfers"
+
+// Transfer outcomes used as the "outcome" label on transfer metrics
+const (
+	OutcomeSuccess           = "success"
+	OutcomeInsufficientFunds = "insufficient_funds"
+	OutcomeNotFound          = "not_found"
+	OutcomeConflict          = "conflict"
+	OutcomeDuplicate         = "duplicate_reference"
+	OutcomePeriodClosed      = "period_closed"
+	OutcomeAccountClosed     = "account_closed"
+	OutcomeError             = "error"
+)
+
+// DB transaction results used as the "result" label on db transaction metrics
+const (
+	TxCommit   = "commit"
+	TxRollback = "rollback"
+)
+
+// Reasons a database transaction is retried, used as the "reason" label on retry metrics
+const (
+	RetrySerializationFailure = "serialization_failure"
+	RetryDeadlock             = "deadlock"
+)
+
+var registry = prometheus.NewRegistry()
+
+var (
+	httpRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Number of HTTP requests handled, by method, route and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latency of HTTP requests, by method, route and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	transfersTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "transfers_total",
+		Help:      "Number of transfers attempted, by outcome.",
+	}, []string{"outcome"})
+
+	transferAmountTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "transfer_amount_total",
+		Help:      "Sum of transfer amounts attempted, by outcome.",
+	}, []string{"outcome"})
+
+	settlementsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "settlements_total",
+		Help:      "Number of deposits and withdrawals attempted, by type, external source and outcome.",
+	}, []string{"type", "source", "outcome"})
+
+	settlementAmountTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "settlement_amount_total",
+		Help:      "Sum of deposit and withdrawal amounts attempted, by type, external source and outcome.",
+	}, []string{"type", "source", "outcome"})
+
+	dbTxDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_transaction_duration_seconds",
+		Help:      "Time between BEGIN and COMMIT/ROLLBACK of database transactions.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"result"})
+
+	dbTxRollbacksTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "db_transaction_rollbacks_total",
+		Help:      "Number of database transactions rolled back.",
+	})
+
+	dbTxRetriesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "db_transaction_retries_total",
+		Help:      "Number of database transactions re-run after being aborted by a concurrent update, by reason.",
+	}, []string{"reason"})
+
+	dbTxRetriesExhaustedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "db_transaction_retries_exhausted_total",
+		Help:      "Number of units of work abandoned after using up their retries.",
+	})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequestsTotal,
+		httpRequestDuration,
+		transfersTotal,
+		transferAmountTotal,
+		settlementsTotal,
+		settlementAmountTotal,
+		dbTxDuration,
+		dbTxRollbacksTotal,
+		dbTxRetriesTotal,
+		dbTxRetriesExhaustedTotal,
+	)
+}
+
+// Handler serves all registered metrics in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// RegisterDBStats exposes the connection pool statistics of db as gauges
+func RegisterDBStats(db *sql.DB, dbName string) {
+	registry.MustRegister(collectors.NewDBStatsCollector(db, dbName))
+}
+
+// ObserveHTTPRequest records a completed HTTP request
+func ObserveHTTPRequest(method, route, status string, elapsed time.Duration) {
+	httpRequestsTotal.WithLabelValues(method, route, status).Inc()
+	httpRequestDuration.WithLabelValues(method, route, status).Observe(elapsed.Seconds())
+}
+
+// ObserveTransfer records the outcome of a transfer attempt
+func ObserveTransfer(outcome string, amount float64) {
+	transfersTotal.WithLabelValues(outcome).Inc()
+	transferAmountTotal.WithLabelValues(outcome).Add(amount)
+}
+
+// ObserveSettlement records the outcome of a deposit or withdrawal attempt. Sources come from configuration,
+// so they are bounded.
+func ObserveSettlement(txType, source, outcome string, amount float64) {
+	settlementsTotal.WithLabelValues(txType, source, outcome).Inc()
+	settlementAmountTotal.WithLabelValues(txType, source, outcome).Add(amount)
+}
+
+// ObserveDBTx records how long a database transaction was open and whether it was committed or rolled back
+func ObserveDBTx(result string, elapsed time.Duration) {
+	dbTxDuration.WithLabelValues(result).Observe(elapsed.Seconds())
+	if result == TxRollback {
+		dbTxRollbacksTotal.Inc()
+	}
+}
+
+// ObserveDBTxRetry records a database transaction being re-run after it was aborted for reason
+func ObserveDBTxRetry(reason string) {
+	dbTxRetriesTotal.WithLabelValues(reason).Inc()
+}
+
+// ObserveDBTxRetriesExhausted records a unit of work given up on after its last retry also failed
+func ObserveDBTxRetriesExhausted() {
+	dbTxRetriesExhaustedTotal.Inc()
+}
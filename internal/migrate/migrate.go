@@ -0,0 +1,273 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"time"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+// advisoryLockID is the pg_advisory_lock key serialising migrations across instances
+const advisoryLockID int64 = 0x6d69677261746531
+
+const createVersionTable = `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version BIGINT PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	)`
+
+// Status describes whether a migration has been applied
+type Status struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Migrator applies the embedded migrations, recording progress in schema_migrations
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+func New(db *sql.DB, fsys fs.FS) (*Migrator, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Latest returns the version of the newest known migration, or 0 if there are none
+func (m *Migrator) Latest() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Up applies every pending migration
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.Goto(ctx, m.Latest())
+}
+
+// Down reverts the most recently applied migration
+func (m *Migrator) Down(ctx context.Context) error {
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		target := int64(0)
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			if _, ok := applied[m.migrations[i].Version]; ok {
+				if i > 0 {
+					target = m.migrations[i-1].Version
+				}
+				break
+			}
+		}
+		return m.migrateTo(ctx, conn, applied, target)
+	})
+}
+
+// Goto migrates up or down until exactly the migrations up to and including target are applied; 0 reverts all
+func (m *Migrator) Goto(ctx context.Context, target int64) error {
+	if target != 0 && !m.known(target) {
+		return fmt.Errorf("unknown migration version %d", target)
+	}
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		return m.migrateTo(ctx, conn, applied, target)
+	})
+}
+
+// Status lists every known migration and whether it has been applied
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	appliedAt, err := m.appliedAt(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		at, ok := appliedAt[mig.Version]
+		statuses = append(statuses, Status{Version: mig.Version, Name: mig.Name, Applied: ok, AppliedAt: at})
+	}
+	return statuses, nil
+}
+
+// appliedAt maps each applied version to when it was applied; a missing schema_migrations table means none are
+func (m *Migrator) appliedAt(ctx context.Context) (map[int64]time.Time, error) {
+	appliedAt := make(map[int64]time.Time)
+	rows, err := m.db.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		if isUndefinedTable(err) {
+			return appliedAt, nil
+		}
+		return nil, fmt.Errorf("list applied migrations failed: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var version int64
+		var at time.Time
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, fmt.Errorf("row scan failed: %w", err)
+		}
+		appliedAt[version] = at
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return appliedAt, nil
+}
+
+// Version returns the highest applied migration version
+func (m *Migrator) Version(ctx context.Context) (int64, error) {
+	var version int64
+	err := m.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version)
+	if err != nil {
+		if isUndefinedTable(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("get schema version failed: %w", err)
+	}
+	return version, nil
+}
+
+// CheckVersion fails unless the database schema is at the latest known version; used as a readiness check
+func (m *Migrator) CheckVersion(ctx context.Context) error {
+	version, err := m.Version(ctx)
+	if err != nil {
+		return err
+	}
+	if version != m.Latest() {
+		return fmt.Errorf("schema at version %d, expected %d", version, m.Latest())
+	}
+	return nil
+}
+
+func (m *Migrator) known(version int64) bool {
+	for _, mig := range m.migrations {
+		if mig.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+// migrateTo reverts applied migrations above target, newest first, then applies pending ones up to target
+func (m *Migrator) migrateTo(ctx context.Context, conn *sql.Conn, applied map[int64]struct{}, target int64) error {
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		mig := m.migrations[i]
+		if _, ok := applied[mig.Version]; !ok || mig.Version <= target {
+			continue
+		}
+		if err := apply(ctx, conn, mig, false); err != nil {
+			return err
+		}
+	}
+	for _, mig := range m.migrations {
+		if _, ok := applied[mig.Version]; ok || mig.Version > target {
+			continue
+		}
+		if err := apply(ctx, conn, mig, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// withLock runs fn on a dedicated connection holding the migration advisory lock
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire connection failed: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, advisoryLockID); err != nil {
+		return fmt.Errorf("acquire migration lock failed: %w", err)
+	}
+	defer func() {
+		// unlock even if ctx was cancelled, otherwise the lock lingers until the connection is recycled
+		if _, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, advisoryLockID); err != nil {
+			log.Error().Err(err).Msg("failed to release migration lock")
+		}
+	}()
+
+	if _, err := conn.ExecContext(ctx, createVersionTable); err != nil {
+		return fmt.Errorf("create schema_migrations failed: %w", err)
+	}
+	return fn(conn)
+}
+
+// apply runs one direction of mig and records it in schema_migrations within a single transaction
+func apply(ctx context.Context, conn *sql.Conn, mig Migration, up bool) error {
+	direction, script := "down", mig.Down
+	if up {
+		direction, script = "up", mig.Up
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin migration %d failed: %w", mig.Version, err)
+	}
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("migration %d_%s %s failed: %w", mig.Version, mig.Name, direction, err)
+	}
+
+	if up {
+		_, err = tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, mig.Version, mig.Name)
+	} else {
+		_, err = tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = $1`, mig.Version)
+	}
+	if err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("record migration %d failed: %w", mig.Version, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit migration %d failed: %w", mig.Version, err)
+	}
+	log.Info().Int64("version", mig.Version).Str("name", mig.Name).Str("direction", direction).Msg("migration applied")
+	return nil
+}
+
+func appliedVersions(ctx context.Context, conn *sql.Conn) (map[int64]struct{}, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT version FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("list applied migrations failed: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]struct{})
+	for rows.Next() {
+		var version int64
+		if err := rows.Scan(&version); err != nil {
+			return nil, fmt.Errorf("row scan failed: %w", err)
+		}
+		applied[version] = struct{}{}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return applied, nil
+}
+
+func isUndefinedTable(err error) bool {
+	var pgErr *pq.Error
+	return errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UndefinedTable
+}
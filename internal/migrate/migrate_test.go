@@ -0,0 +1,159 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"testing/fstest"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgerrcode"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testFS = fstest.MapFS{
+	"000001_a.up.sql":   {Data: []byte("CREATE TABLE a ();")},
+	"000001_a.down.sql": {Data: []byte("DROP TABLE a;")},
+	"000002_b.up.sql":   {Data: []byte("CREATE TABLE b ();")},
+	"000002_b.down.sql": {Data: []byte("DROP TABLE b;")},
+}
+
+func newTestMigrator(t *testing.T) (*Migrator, sqlmock.Sqlmock, *sql.DB) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	m, err := New(db, testFS)
+	require.NoError(t, err)
+	return m, mock, db
+}
+
+func expectLocked(mock sqlmock.Sqlmock, applied ...int64) {
+	mock.ExpectExec(`SELECT pg_advisory_lock`).WithArgs(advisoryLockID).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`CREATE TABLE IF NOT EXISTS schema_migrations`).WillReturnResult(sqlmock.NewResult(0, 0))
+	rows := sqlmock.NewRows([]string{"version"})
+	for _, v := range applied {
+		rows.AddRow(v)
+	}
+	mock.ExpectQuery(`SELECT version FROM schema_migrations`).WillReturnRows(rows)
+}
+
+func expectUnlocked(mock sqlmock.Sqlmock) {
+	mock.ExpectExec(`SELECT pg_advisory_unlock`).WithArgs(advisoryLockID).WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func TestMigrator_Up(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("applies pending migrations only", func(t *testing.T) {
+		// given
+		m, mock, db := newTestMigrator(t)
+		defer db.Close()
+
+		expectLocked(mock, 1)
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE b ();")).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`INSERT INTO schema_migrations`).WithArgs(int64(2), "b").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+		expectUnlocked(mock)
+
+		// when
+		err := m.Up(ctx)
+
+		// then
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failed migration is rolled back", func(t *testing.T) {
+		// given
+		m, mock, db := newTestMigrator(t)
+		defer db.Close()
+
+		expectLocked(mock)
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE a ();")).WillReturnError(assert.AnError)
+		mock.ExpectRollback()
+		expectUnlocked(mock)
+
+		// when
+		err := m.Up(ctx)
+
+		// then
+		assert.ErrorContains(t, err, "migration 1_a up failed")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMigrator_Down(t *testing.T) {
+	// given
+	ctx := context.Background()
+	m, mock, db := newTestMigrator(t)
+	defer db.Close()
+
+	expectLocked(mock, 1, 2)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DROP TABLE b;")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`DELETE FROM schema_migrations`).WithArgs(int64(2)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	expectUnlocked(mock)
+
+	// when
+	err := m.Down(ctx)
+
+	// then
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Goto(t *testing.T) {
+	t.Run("unknown version", func(t *testing.T) {
+		// given
+		m, _, db := newTestMigrator(t)
+		defer db.Close()
+
+		// when
+		err := m.Goto(context.Background(), 7)
+
+		// then
+		assert.ErrorContains(t, err, "unknown migration version 7")
+	})
+}
+
+func TestMigrator_CheckVersion(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("at latest version", func(t *testing.T) {
+		// given
+		m, mock, db := newTestMigrator(t)
+		defer db.Close()
+		mock.ExpectQuery(`SELECT COALESCE\(MAX\(version\), 0\) FROM schema_migrations`).
+			WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(2))
+
+		// when/then
+		assert.NoError(t, m.CheckVersion(ctx))
+	})
+
+	t.Run("behind latest version", func(t *testing.T) {
+		// given
+		m, mock, db := newTestMigrator(t)
+		defer db.Close()
+		mock.ExpectQuery(`SELECT COALESCE\(MAX\(version\), 0\) FROM schema_migrations`).
+			WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(1))
+
+		// when/then
+		assert.ErrorContains(t, m.CheckVersion(ctx), "schema at version 1, expected 2")
+	})
+
+	t.Run("never migrated", func(t *testing.T) {
+		// given
+		m, mock, db := newTestMigrator(t)
+		defer db.Close()
+		mock.ExpectQuery(`SELECT COALESCE\(MAX\(version\), 0\) FROM schema_migrations`).
+			WillReturnError(&pq.Error{Code: pgerrcode.UndefinedTable})
+
+		// when/then
+		assert.ErrorContains(t, m.CheckVersion(ctx), "schema at version 0, expected 2")
+	})
+}
@@ -0,0 +1,55 @@
+package migrate
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"internal-transfers/migrations"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	t.Run("sorted by version", func(t *testing.T) {
+		// given
+		fsys := fstest.MapFS{
+			"000002_b.up.sql":   {Data: []byte("CREATE TABLE b ();")},
+			"000002_b.down.sql": {Data: []byte("DROP TABLE b;")},
+			"000001_a.up.sql":   {Data: []byte("CREATE TABLE a ();")},
+			"000001_a.down.sql": {Data: []byte("DROP TABLE a;")},
+			"migrations.go":     {Data: []byte("package migrations")},
+		}
+
+		// when
+		got, err := Load(fsys)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+		assert.Equal(t, Migration{Version: 1, Name: "a", Up: "CREATE TABLE a ();", Down: "DROP TABLE a;"}, got[0])
+		assert.Equal(t, int64(2), got[1].Version)
+	})
+
+	t.Run("missing down script", func(t *testing.T) {
+		// given
+		fsys := fstest.MapFS{
+			"000001_a.up.sql": {Data: []byte("CREATE TABLE a ();")},
+		}
+
+		// when
+		_, err := Load(fsys)
+
+		// then
+		assert.ErrorContains(t, err, "must have non-empty up and down scripts")
+	})
+
+	t.Run("embedded migrations are well formed", func(t *testing.T) {
+		// when
+		got, err := Load(migrations.FS)
+
+		// then
+		require.NoError(t, err)
+		assert.NotEmpty(t, got)
+	})
+}
@@ -1,6 +1,166 @@
 package model
 
+import (
+	"slices"
+	"time"
+)
+
 type Account struct {
 	AccountID int64
 	Balance   float64
+	// Type defaults to AccountCustomer when empty
+	Type AccountType
+	// Status is set to AccountOpen by the repository when the account is opened
+	Status AccountStatus
+	// Name is a human-readable display name
+	Name string
+	// Owner references whoever the account belongs to, such as a customer id in another system
+	Owner  string
+	Labels map[string]string
+	// Version counts the changes to the account's type and details, starting at 1; balance changes do not count
+	Version int64
+	// CreatedAt, like Version and UpdatedAt, is set by the repository when the account is opened
+	CreatedAt time.Time
+	// UpdatedAt is when the version last changed
+	UpdatedAt time.Time
+}
+
+// MaxCallerAccountID is the largest id callers may choose for an account. Larger ids are allocated by the
+// server: a number drawn from a sequence starting at FirstAccountSequence, followed by its Luhn check digit.
+const MaxCallerAccountID int64 = 99_999_999_999
+
+// FirstAccountSequence is the first number of the account id sequence, so allocated ids start above
+// MaxCallerAccountID
+const FirstAccountSequence int64 = (MaxCallerAccountID + 1) / 10
+
+// AllocatedAccountID returns the account id for a number of the account id sequence
+func AllocatedAccountID(seq int64) int64 {
+	return seq*10 + luhnCheckDigit(seq)
+}
+
+// ValidAllocatedAccountID reports whether id is above MaxCallerAccountID and ends in the check digit of the
+// rest of it, catching any single mistyped digit and most swapped adjacent digits
+func ValidAllocatedAccountID(id int64) bool {
+	return id > MaxCallerAccountID && id%10 == luhnCheckDigit(id/10)
+}
+
+// WellFormedAccountID reports whether id can name an account: one callers may choose, a seeded account or an
+// allocated id with the right check digit. Other ids are mistyped, as no account is ever opened with them.
+func WellFormedAccountID(id int64) bool {
+	return id <= MaxCallerAccountID || ValidAllocatedAccountID(id) || SeededAccountID(id)
+}
+
+// luhnCheckDigit returns the digit that completes the Luhn checksum of n
+func luhnCheckDigit(n int64) int64 {
+	var sum int64
+	for double := true; n > 0; n, double = n/10, !double {
+		d := n % 10
+		if double {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return (10 - sum%10) % 10
+}
+
+// AccountType places an account in the chart of accounts and decides the rules it follows
+type AccountType string
+
+const (
+	// AccountCustomer holds money on behalf of a customer
+	AccountCustomer AccountType = "customer"
+	// AccountRevenue collects the fees and other income of the ledger's operator
+	AccountRevenue AccountType = "revenue"
+	// AccountExpense pays out costs of the operator, such as interest
+	AccountExpense AccountType = "expense"
+	// AccountSuspense parks money until it can be allocated to the right account
+	AccountSuspense AccountType = "suspense"
+	// AccountSettlement mirrors the money an external source system holds for the ledger
+	AccountSettlement AccountType = "settlement"
+	// AccountSystem is used by the ledger itself, such as the mint account
+	AccountSystem AccountType = "system"
+)
+
+// AccountStatus says whether an account takes part in postings
+type AccountStatus string
+
+const (
+	AccountOpen AccountStatus = "open"
+	// AccountClosed accounts hold no money and reject postings until they are reopened
+	AccountClosed AccountStatus = "closed"
+)
+
+// AccountStatuses are the statuses an account can have
+var AccountStatuses = []AccountStatus{AccountOpen, AccountClosed}
+
+// BalanceSide is the side of the ledger on which an account type normally carries its balance. Balances are
+// stored as received less sent, so credit-normal accounts normally hold positive balances and debit-normal
+// accounts negative ones.
+type BalanceSide string
+
+const (
+	BalanceDebit  BalanceSide = "debit"
+	BalanceCredit BalanceSide = "credit"
+)
+
+// AccountRule is what an account type may do
+type AccountRule struct {
+	NormalBalance BalanceSide
+	// Overdraft lets the balance of the ledger's own accounts of the type go negative
+	Overdraft bool
+	// Reserved types are only opened by the service itself, for configured or seeded accounts
+	Reserved bool
+}
+
+// AccountRules is the chart of accounts: the rule of every account type
+var AccountRules = map[AccountType]AccountRule{
+	AccountCustomer:   {NormalBalance: BalanceCredit},
+	AccountRevenue:    {NormalBalance: BalanceCredit},
+	AccountExpense:    {NormalBalance: BalanceDebit, Overdraft: true},
+	AccountSuspense:   {NormalBalance: BalanceDebit, Overdraft: true},
+	AccountSettlement: {NormalBalance: BalanceDebit, Overdraft: true, Reserved: true},
+	AccountSystem:     {NormalBalance: BalanceDebit, Overdraft: true, Reserved: true},
+}
+
+// Rule returns the rule of the type, treating an empty type as AccountCustomer
+func (t AccountType) Rule() (AccountRule, bool) {
+	if t == "" {
+		t = AccountCustomer
+	}
+	rule, ok := AccountRules[t]
+	return rule, ok
+}
+
+// LedgerOwned reports whether the ledger opened the account itself, as a seeded account or one of a reserved type
+func (a Account) LedgerOwned() bool {
+	rule, _ := a.Type.Rule()
+	return rule.Reserved || SeededAccountID(a.AccountID)
+}
+
+// MayOverdraw reports whether the account may be left with a negative balance: its type must allow it and it
+// must be one of the ledger's own, so accounts callers open never go negative whatever their type
+func (a Account) MayOverdraw() bool {
+	rule, _ := a.Type.Rule()
+	return rule.Overdraft && a.LedgerOwned()
+}
+
+// Accounts seeded by migration 000009, and opened at startup when storage is in memory
+const (
+	SuspenseAccountID        int64 = 9_000_000_000_000_000_001
+	FeeRevenueAccountID      int64 = 9_000_000_000_000_000_002
+	InterestExpenseAccountID int64 = 9_000_000_000_000_000_003
+)
+
+// SeededAccounts are the accounts every ledger starts with, all empty
+var SeededAccounts = []Account{
+	{AccountID: SuspenseAccountID, Type: AccountSuspense},
+	{AccountID: FeeRevenueAccountID, Type: AccountRevenue},
+	{AccountID: InterestExpenseAccountID, Type: AccountExpense},
+}
+
+// SeededAccountID reports whether id belongs to one of the SeededAccounts
+func SeededAccountID(id int64) bool {
+	return slices.ContainsFunc(SeededAccounts, func(acc Account) bool { return acc.AccountID == id })
 }
@@ -0,0 +1,72 @@
+package model
+
+import "time"
+
+// DayCount is the convention deciding which fraction of the annual rate a day of interest earns
+type DayCount string
+
+const (
+	// DayCountActual365 earns 1/365 of the annual rate every day, also in leap years
+	DayCountActual365 DayCount = "actual/365"
+	// DayCountActual360 earns 1/360 of the annual rate every day
+	DayCountActual360 DayCount = "actual/360"
+	// DayCountActualActual earns 1/365 of the annual rate every day, or 1/366 in leap years
+	DayCountActualActual DayCount = "actual/actual"
+	// DayCount30360 counts every month as 30 days of a 360 day year: in 31 day months one day earns nothing
+	// and the last day of February earns the days up to the 30th
+	DayCount30360 DayCount = "30/360"
+)
+
+// DayCounts are the supported day count conventions
+var DayCounts = []DayCount{DayCountActual365, DayCountActual360, DayCountActualActual, DayCount30360}
+
+// Compounding is how often accrued interest is added to the balance it is earned on
+type Compounding string
+
+const (
+	// CompoundingDaily earns interest on the interest accrued so far; it is posted monthly
+	CompoundingDaily     Compounding = "daily"
+	CompoundingMonthly   Compounding = "monthly"
+	CompoundingQuarterly Compounding = "quarterly"
+	CompoundingAnnually  Compounding = "annually"
+)
+
+// Compoundings are the supported compounding frequencies
+var Compoundings = []Compounding{CompoundingDaily, CompoundingMonthly, CompoundingQuarterly, CompoundingAnnually}
+
+// InterestProduct is an interest bearing product accounts are enrolled in
+type InterestProduct struct {
+	Name string
+	// Rate is the annual rate as a fraction, e.g. 0.035 for 3.5%
+	Rate        float64
+	DayCount    DayCount
+	Compounding Compounding
+}
+
+// InterestAccrual is the interest an account earned on a single day, in fractions of a cent
+type InterestAccrual struct {
+	AccountID int64
+	// Date is the UTC midnight starting the day
+	Date    time.Time
+	Product string
+	// Balance is the balance interest was earned on: the end-of-day balance plus, when compounding daily, the
+	// interest accrued earlier in the period
+	Balance float64
+	Rate    float64
+	Amount  float64
+	// PeriodStart and PeriodEnd bound the posting period the accrual is paid in, PeriodEnd exclusive
+	PeriodStart time.Time
+	PeriodEnd   time.Time
+	// Stale marks an accrual a backdated entry made out of date; it is accrued again before its period is paid
+	Stale bool
+}
+
+// InterestPosting pays the interest an account accrued over a period
+type InterestPosting struct {
+	AccountID   int64
+	Product     string
+	PeriodStart time.Time
+	PeriodEnd   time.Time
+	// Amount is the sum of the period's accruals, before rounding to cents
+	Amount float64
+}
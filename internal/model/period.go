@@ -0,0 +1,42 @@
+package model
+
+import "time"
+
+// Period is an accounting period: a UTC day. Closing a period locks it and every earlier one against
+// backdated entries.
+type Period struct {
+	// Date is the UTC midnight starting the period
+	Date     time.Time
+	Closed   bool
+	ClosedAt time.Time
+	// ClosedBy is the caller that closed the period, or the close job
+	ClosedBy string
+	// TrialBalance is taken from the balances at the end of the period when it is closed; it is nil for
+	// periods that were locked by closing a later one before any period was closed
+	TrialBalance *TrialBalance
+}
+
+// End returns the exclusive end of the period
+func (p Period) End() time.Time {
+	return p.Date.AddDate(0, 0, 1)
+}
+
+// TrialBalance totals the debit and credit balances of every account type at a point in time. Balances
+// accounts were opened with outside of minting have no counterpart in any account, so they are balanced by the
+// opening balances line; debits and credits then agree unless a balance drifted from its history.
+type TrialBalance struct {
+	Lines []TrialBalanceLine
+	// OpeningBalances is the equity side of the initial balances of the accounts: positive ones as debits and
+	// negative ones as credits. Its AccountType is empty and Accounts counts the accounts opened with a balance.
+	OpeningBalances TrialBalanceLine
+	Debits          float64
+	Credits         float64
+}
+
+// TrialBalanceLine totals the accounts of one type: negative balances as debits and positive ones as credits
+type TrialBalanceLine struct {
+	AccountType AccountType
+	Accounts    int64
+	Debit       float64
+	Credit      float64
+}
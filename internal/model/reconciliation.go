@@ -0,0 +1,27 @@
+package model
+
+// BalanceCheck is the outcome of checking every stored balance against the transaction history
+type BalanceCheck struct {
+	Checked int64
+	// Unverifiable counts the checked accounts opened before initial balances were recorded, whose expected
+	// balance is unknown
+	Unverifiable  int64
+	Discrepancies []BalanceDiscrepancy
+}
+
+// BalanceDiscrepancy is an account whose stored balance differs from its initial balance plus its transactions
+type BalanceDiscrepancy struct {
+	AccountID       int64
+	Balance         float64
+	ExpectedBalance float64
+}
+
+// Supply totals the money held by every account other than the mint and settlement accounts
+type Supply struct {
+	Accounts    int64
+	Circulating float64
+	// Minted is the total ever drawn from the mint account, net of anything sent back to it
+	Minted float64
+	// Settled is the total deposited through settlement accounts, net of withdrawals
+	Settled float64
+}
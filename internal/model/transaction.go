@@ -2,10 +2,68 @@ package model
 
 import "time"
 
+// TransactionType says why money moved
+type TransactionType string
+
+const (
+	// TransactionTransfer moves money between two customer accounts
+	TransactionTransfer TransactionType = "transfer"
+	// TransactionPayment, TransactionPayroll, TransactionRefund and TransactionFee are transfers the caller
+	// has classified further
+	TransactionPayment TransactionType = "payment"
+	TransactionPayroll TransactionType = "payroll"
+	TransactionRefund  TransactionType = "refund"
+	TransactionFee     TransactionType = "fee"
+	// TransactionFunding moves an initial balance from the mint account to a new account
+	TransactionFunding TransactionType = "funding"
+	// TransactionDeposit moves money from a settlement account into a customer account
+	TransactionDeposit TransactionType = "deposit"
+	// TransactionWithdrawal moves money from a customer account out to a settlement account
+	TransactionWithdrawal TransactionType = "withdrawal"
+	// TransactionInterest pays interest accrued on an account from the interest expense account
+	TransactionInterest TransactionType = "interest"
+)
+
+// TransactionTypes are every type a transaction may have, as the transactions_type_check constraint allows
+var TransactionTypes = []TransactionType{
+	TransactionTransfer, TransactionPayment, TransactionPayroll, TransactionRefund, TransactionFee,
+	TransactionFunding, TransactionDeposit, TransactionWithdrawal, TransactionInterest,
+}
+
+// TransferTypes are the types a caller may give a transfer between two accounts; the others are set by the
+// operation that records them
+var TransferTypes = []TransactionType{
+	TransactionTransfer, TransactionPayment, TransactionPayroll, TransactionRefund, TransactionFee,
+}
+
 type Transaction struct {
 	TransactionID        int64
 	SourceAccountID      int64
 	DestinationAccountID int64
 	Amount               float64
-	CreatedAt            time.Time
+	Type                 TransactionType
+	Description          string
+	Metadata             map[string]string
+	// ExternalReference is the caller's id for the transaction. ExternalSource names the external system that
+	// posted a deposit or withdrawal; the pair is unique when both are set.
+	ExternalSource    string
+	ExternalReference string
+	CreatedAt         time.Time
+	// GroupID links the transactions recorded for one multi-leg transfer; 0 for any other transaction
+	GroupID int64
+}
+
+// TransferLeg is one account's share of a multi-leg transfer: negative amounts are debited from the account
+// and positive amounts credited to it
+type TransferLeg struct {
+	AccountID int64
+	Amount    float64
+}
+
+// TransferGroup is a multi-leg transfer, recorded as one transaction per pair of debited and credited legs
+type TransferGroup struct {
+	GroupID      int64
+	Legs         []TransferLeg
+	Transactions []*Transaction
+	CreatedAt    time.Time
 }
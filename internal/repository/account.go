@@ -3,37 +3,127 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"internal-transfers/internal/domain"
+	"strings"
+	"time"
 
 	"internal-transfers/internal/model"
 
 	"github.com/jackc/pgerrcode"
 	"github.com/lib/pq"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // AccountRepository defines db operations for account data
 //
 //go:generate mockery --name=AccountRepository --output=./mocks --filename=account_mock.go --with-expecter
 type AccountRepository interface {
+	// CreateAccount inserts account and fills in its version and timestamps
 	CreateAccount(ctx context.Context, account *model.Account) error
+	// NextAccountID allocates an id for a new account, see model.AllocatedAccountID. Allocated ids are never
+	// handed out twice, even when the account they were allocated for is not created.
+	NextAccountID(ctx context.Context) (int64, error)
+	// CreateAccounts inserts accounts in batches, skipping those whose id already exists, and returns the ids
+	// that were inserted
+	CreateAccounts(ctx context.Context, accounts []*model.Account) ([]int64, error)
 	GetAccount(ctx context.Context, accountID int64) (*model.Account, error)
-	UpdateBalance(ctx context.Context, tx *sql.Tx, accountID int64, newBalance float64) error
+	// GetAccountForUpdate is GetAccount that also locks the account until the enclosing unit of work ends.
+	// Under serializable isolation no lock is taken; conflicting units of work are aborted and retried instead.
+	GetAccountForUpdate(ctx context.Context, accountID int64) (*model.Account, error)
+	UpdateBalance(ctx context.Context, accountID int64, newBalance float64) error
+	// UpdateType moves an account to another type of the chart of accounts
+	UpdateType(ctx context.Context, accountID int64, accountType model.AccountType) error
+	// UpdateDetails writes the name, owner, labels and status of account provided it is still at
+	// account.Version, and advances account to the new version. It returns domain.ErrAccountVersionMismatch
+	// when another update came first or the account does not exist.
+	UpdateDetails(ctx context.Context, account *model.Account) error
+	// ListAccounts returns the accounts matching filter in the order it asks for, by default ascending id
+	ListAccounts(ctx context.Context, filter AccountFilter) ([]*model.Account, error)
 }
 
+// AccountFilter narrows ListAccounts; zero fields match every account
+type AccountFilter struct {
+	Types    []model.AccountType
+	Statuses []model.AccountStatus
+	Owner    string
+	// MinBalance and MaxBalance bound the balance inclusively when set
+	MinBalance, MaxBalance *float64
+	// CreatedFrom and CreatedTo bound created_at to [CreatedFrom, CreatedTo)
+	CreatedFrom, CreatedTo time.Time
+	// Sort orders the accounts, breaking ties by id; the zero value sorts by id
+	Sort AccountSort
+	// Desc reverses the order
+	Desc bool
+	// After resumes a listing after the last account of the previous page, which must use the same order
+	After *AccountCursor
+	// Limit caps the number of accounts returned; 0 means no limit
+	Limit int
+}
+
+// AccountSort is a field accounts can be listed by
+type AccountSort string
+
+const (
+	AccountSortID        AccountSort = "id"
+	AccountSortBalance   AccountSort = "balance"
+	AccountSortCreatedAt AccountSort = "created_at"
+)
+
+// accountSortColumns maps each sort to the column it orders by
+var accountSortColumns = map[AccountSort]string{
+	"":                   "account_id",
+	AccountSortID:        "account_id",
+	AccountSortBalance:   "balance",
+	AccountSortCreatedAt: "created_at",
+}
+
+// AccountCursor is the position of an account in a listing; only the fields of the listing's sort and the id
+// are compared
+type AccountCursor struct {
+	AccountID int64
+	Balance   float64
+	CreatedAt time.Time
+}
+
+// CursorOf returns the position of acc in a listing
+func CursorOf(acc *model.Account) *AccountCursor {
+	return &AccountCursor{AccountID: acc.AccountID, Balance: acc.Balance, CreatedAt: acc.CreatedAt}
+}
+
+// accountColumns are scanned by scanAccount
+const accountColumns = `account_id, balance, type, status, name, owner, labels, version, created_at, updated_at`
+
+// createAccountsBatchSize keeps each insert well below Postgres' limit of 65535 bind parameters
+const createAccountsBatchSize = 1000
+
 // accountRepository is the Postgres implementation
 type accountRepository struct {
-	db *sql.DB
+	db querier
+	// serializable skips row locks, relying on the database to detect conflicting transactions
+	serializable bool
 }
 
 func NewAccountRepository(db *sql.DB) AccountRepository {
 	return &accountRepository{db: db}
 }
 
-func (r *accountRepository) CreateAccount(ctx context.Context, account *model.Account) error {
-	query := `INSERT INTO accounts (account_id, balance) VALUES ($1, $2)`
-	_, err := r.db.ExecContext(ctx, query, account.AccountID, account.Balance)
+func (r *accountRepository) CreateAccount(ctx context.Context, account *model.Account) (err error) {
+	ctx, span := startSpan(ctx, "CreateAccount", attribute.Int64("account.id", account.AccountID))
+	defer func() { endSpan(span, err) }()
+
+	labels, err := marshalMetadata(account.Labels)
+	if err != nil {
+		return err
+	}
+	query := `
+        INSERT INTO accounts (account_id, balance, initial_balance, type, name, owner, labels)
+        VALUES ($1, $2, $2, COALESCE(NULLIF($3, ''), 'customer'), $4, $5, $6)
+        RETURNING status, version, created_at, updated_at`
+	err = r.db.QueryRowContext(ctx, query, account.AccountID, account.Balance, account.Type,
+		account.Name, account.Owner, labels).Scan(&account.Status, &account.Version, &account.CreatedAt, &account.UpdatedAt)
 	if err != nil {
 		// case where account already exists
 		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == pgerrcode.UniqueViolation {
@@ -44,25 +134,272 @@ func (r *accountRepository) CreateAccount(ctx context.Context, account *model.Ac
 	return nil
 }
 
-func (r *accountRepository) GetAccount(ctx context.Context, accountID int64) (*model.Account, error) {
-	query := `SELECT account_id, balance FROM accounts WHERE account_id = $1`
-	row := r.db.QueryRowContext(ctx, query, accountID)
+func (r *accountRepository) NextAccountID(ctx context.Context) (_ int64, err error) {
+	ctx, span := startSpan(ctx, "NextAccountID")
+	defer func() { endSpan(span, err) }()
+
+	var seq int64
+	if err = r.db.QueryRowContext(ctx, `SELECT nextval('account_id_seq')`).Scan(&seq); err != nil {
+		return 0, fmt.Errorf("allocate account id failed: %w", err)
+	}
+	return model.AllocatedAccountID(seq), nil
+}
+
+func (r *accountRepository) CreateAccounts(ctx context.Context, accounts []*model.Account) (_ []int64, err error) {
+	ctx, span := startSpan(ctx, "CreateAccounts", attribute.Int("accounts.count", len(accounts)))
+	defer func() { endSpan(span, err) }()
+
+	var created []int64
+	for start := 0; start < len(accounts); start += createAccountsBatchSize {
+		batch := accounts[start:min(start+createAccountsBatchSize, len(accounts))]
+		ids, err := r.insertAccounts(ctx, batch)
+		if err != nil {
+			return nil, err
+		}
+		created = append(created, ids...)
+	}
+	return created, nil
+}
+
+func (r *accountRepository) insertAccounts(ctx context.Context, batch []*model.Account) ([]int64, error) {
+	var query strings.Builder
+	query.WriteString(`INSERT INTO accounts (account_id, balance, initial_balance) VALUES `)
+	args := make([]any, 0, 2*len(batch))
+	for i, acc := range batch {
+		if i > 0 {
+			query.WriteString(", ")
+		}
+		fmt.Fprintf(&query, "($%d, $%d, $%[2]d)", 2*i+1, 2*i+2)
+		args = append(args, acc.AccountID, acc.Balance)
+	}
+	query.WriteString(` ON CONFLICT (account_id) DO NOTHING RETURNING account_id`)
+
+	rows, err := r.db.QueryContext(ctx, query.String(), args...)
+	if err != nil {
+		return nil, fmt.Errorf("create accounts failed: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("row scan failed: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return ids, nil
+}
+
+func (r *accountRepository) GetAccount(ctx context.Context, accountID int64) (_ *model.Account, err error) {
+	ctx, span := startSpan(ctx, "GetAccount", attribute.Int64("account.id", accountID))
+	defer func() { endSpan(span, err) }()
 
-	var acc model.Account
-	if err := row.Scan(&acc.AccountID, &acc.Balance); err != nil {
+	query := `SELECT ` + accountColumns + ` FROM accounts WHERE account_id = $1`
+	return r.getAccount(ctx, query, accountID)
+}
+
+func (r *accountRepository) GetAccountForUpdate(ctx context.Context, accountID int64) (_ *model.Account, err error) {
+	ctx, span := startSpan(ctx, "GetAccountForUpdate", attribute.Int64("account.id", accountID))
+	defer func() { endSpan(span, err) }()
+
+	query := `SELECT ` + accountColumns + ` FROM accounts WHERE account_id = $1 FOR UPDATE`
+	if r.serializable {
+		query = `SELECT ` + accountColumns + ` FROM accounts WHERE account_id = $1`
+	}
+	return r.getAccount(ctx, query, accountID)
+}
+
+func (r *accountRepository) getAccount(ctx context.Context, query string, accountID int64) (*model.Account, error) {
+	acc, err := scanAccount(r.db.QueryRowContext(ctx, query, accountID))
+	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, domain.ErrAccountNotFound
 		}
 		return nil, fmt.Errorf("get account failed: %w", err)
 	}
+	return acc, nil
+}
+
+// scanAccount reads a row of accountColumns
+func scanAccount(row interface{ Scan(dest ...any) error }) (*model.Account, error) {
+	var (
+		acc    model.Account
+		labels []byte
+	)
+	if err := row.Scan(&acc.AccountID, &acc.Balance, &acc.Type, &acc.Status, &acc.Name, &acc.Owner, &labels,
+		&acc.Version, &acc.CreatedAt, &acc.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(labels, &acc.Labels); err != nil {
+		return nil, fmt.Errorf("invalid labels: %w", err)
+	}
+	if len(acc.Labels) == 0 {
+		acc.Labels = nil
+	}
 	return &acc, nil
 }
 
-func (r *accountRepository) UpdateBalance(ctx context.Context, tx *sql.Tx, accountID int64, newBalance float64) error {
+func (r *accountRepository) UpdateBalance(ctx context.Context, accountID int64, newBalance float64) (err error) {
+	ctx, span := startSpan(ctx, "UpdateBalance", attribute.Int64("account.id", accountID))
+	defer func() { endSpan(span, err) }()
+
 	query := `UPDATE accounts SET balance = $1 WHERE account_id = $2`
-	_, err := tx.ExecContext(ctx, query, newBalance, accountID)
+	_, err = r.db.ExecContext(ctx, query, newBalance, accountID)
 	if err != nil {
 		return fmt.Errorf("update balance failed: %w", err)
 	}
 	return nil
 }
+
+func (r *accountRepository) UpdateType(ctx context.Context, accountID int64, accountType model.AccountType) (err error) {
+	ctx, span := startSpan(ctx, "UpdateType",
+		attribute.Int64("account.id", accountID),
+		attribute.String("account.type", string(accountType)),
+	)
+	defer func() { endSpan(span, err) }()
+
+	query := `UPDATE accounts SET type = $1, version = version + 1, updated_at = NOW() WHERE account_id = $2`
+	res, err := r.db.ExecContext(ctx, query, accountType, accountID)
+	if err != nil {
+		return fmt.Errorf("update type failed: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("update type failed: %w", err)
+	}
+	if n == 0 {
+		return domain.ErrAccountNotFound
+	}
+	return nil
+}
+
+func (r *accountRepository) UpdateDetails(ctx context.Context, account *model.Account) (err error) {
+	ctx, span := startSpan(ctx, "UpdateDetails",
+		attribute.Int64("account.id", account.AccountID),
+		attribute.Int64("account.version", account.Version),
+	)
+	defer func() { endSpan(span, err) }()
+
+	labels, err := marshalMetadata(account.Labels)
+	if err != nil {
+		return err
+	}
+	query := `
+        UPDATE accounts SET name = $1, owner = $2, labels = $3, status = $4, version = version + 1, updated_at = NOW()
+        WHERE account_id = $5 AND version = $6
+        RETURNING version, updated_at`
+	err = r.db.QueryRowContext(ctx, query, account.Name, account.Owner, labels, account.Status, account.AccountID,
+		account.Version).
+		Scan(&account.Version, &account.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrAccountVersionMismatch
+	}
+	if err != nil {
+		return fmt.Errorf("update details failed: %w", err)
+	}
+	return nil
+}
+
+func (r *accountRepository) ListAccounts(ctx context.Context, filter AccountFilter) (_ []*model.Account, err error) {
+	ctx, span := startSpan(ctx, "ListAccounts", attribute.String("list.sort", string(filter.Sort)))
+	defer func() { endSpan(span, err) }()
+
+	column, ok := accountSortColumns[filter.Sort]
+	if !ok {
+		return nil, fmt.Errorf("list accounts failed: unknown sort %q", filter.Sort)
+	}
+	var (
+		conds []string
+		args  []any
+	)
+	where := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+	if len(filter.Types) > 0 {
+		types := make([]string, len(filter.Types))
+		for i, t := range filter.Types {
+			types[i] = string(t)
+		}
+		where("type = ANY($%d)", pq.Array(types))
+	}
+	if len(filter.Statuses) > 0 {
+		statuses := make([]string, len(filter.Statuses))
+		for i, s := range filter.Statuses {
+			statuses[i] = string(s)
+		}
+		where("status = ANY($%d)", pq.Array(statuses))
+	}
+	if filter.Owner != "" {
+		where("owner = $%d", filter.Owner)
+	}
+	if filter.MinBalance != nil {
+		where("balance >= $%d", *filter.MinBalance)
+	}
+	if filter.MaxBalance != nil {
+		where("balance <= $%d", *filter.MaxBalance)
+	}
+	if !filter.CreatedFrom.IsZero() {
+		where("created_at >= $%d", filter.CreatedFrom)
+	}
+	if !filter.CreatedTo.IsZero() {
+		where("created_at < $%d", filter.CreatedTo)
+	}
+	direction, cmp := "", ">"
+	if filter.Desc {
+		direction, cmp = " DESC", "<"
+	}
+	if after := filter.After; after != nil {
+		switch filter.Sort {
+		case AccountSortBalance:
+			args = append(args, after.Balance, after.AccountID)
+		case AccountSortCreatedAt:
+			args = append(args, after.CreatedAt, after.AccountID)
+		default:
+			args = append(args, after.AccountID)
+		}
+		if column == "account_id" {
+			conds = append(conds, fmt.Sprintf("account_id %s $%d", cmp, len(args)))
+		} else {
+			conds = append(conds, fmt.Sprintf("(%s, account_id) %s ($%d, $%d)", column, cmp, len(args)-1, len(args)))
+		}
+	}
+
+	var query strings.Builder
+	query.WriteString(`SELECT ` + accountColumns + ` FROM accounts`)
+	if len(conds) > 0 {
+		query.WriteString(" WHERE " + strings.Join(conds, " AND "))
+	}
+	if column == "account_id" {
+		query.WriteString(" ORDER BY account_id" + direction)
+	} else {
+		fmt.Fprintf(&query, " ORDER BY %s%s, account_id%[2]s", column, direction)
+	}
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		fmt.Fprintf(&query, " LIMIT $%d", len(args))
+	}
+
+	rows, err := r.db.QueryContext(ctx, query.String(), args...)
+	if err != nil {
+		return nil, fmt.Errorf("list accounts failed: %w", err)
+	}
+	defer rows.Close()
+
+	var accounts []*model.Account
+	for rows.Next() {
+		acc, err := scanAccount(rows)
+		if err != nil {
+			return nil, fmt.Errorf("row scan failed: %w", err)
+		}
+		accounts = append(accounts, acc)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return accounts, nil
+}
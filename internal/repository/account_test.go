@@ -7,6 +7,7 @@ import (
 	"github.com/lib/pq"
 	"internal-transfers/internal/domain"
 	"testing"
+	"time"
 
 	"internal-transfers/internal/model"
 
@@ -15,6 +16,9 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// accountRowColumns name the columns of accountColumns in mocked rows
+var accountRowColumns = []string{"account_id", "balance", "type", "status", "name", "owner", "labels", "version", "created_at", "updated_at"}
+
 func TestAccountRepository_CreateAccount(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	require.NoError(t, err)
@@ -25,26 +29,35 @@ func TestAccountRepository_CreateAccount(t *testing.T) {
 	account := &model.Account{
 		AccountID: 123,
 		Balance:   100.0,
+		Type:      model.AccountRevenue,
+		Name:      "Card fees",
+		Owner:     "finance",
+		Labels:    map[string]string{"team": "billing"},
 	}
 
 	t.Run("create account successfully", func(t *testing.T) {
 		// given
-		mock.ExpectExec(`INSERT INTO accounts`).
-			WithArgs(account.AccountID, account.Balance).
-			WillReturnResult(sqlmock.NewResult(1, 1))
+		createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+		mock.ExpectQuery(`INSERT INTO accounts .* RETURNING status, version, created_at, updated_at`).
+			WithArgs(account.AccountID, account.Balance, account.Type, "Card fees", "finance", []byte(`{"team":"billing"}`)).
+			WillReturnRows(sqlmock.NewRows([]string{"status", "version", "created_at", "updated_at"}).AddRow("open", 1, createdAt, createdAt))
 
 		// when
 		err = repo.CreateAccount(ctx, account)
 
 		// then
 		assert.NoError(t, err)
+		assert.Equal(t, model.AccountOpen, account.Status)
+		assert.Equal(t, int64(1), account.Version)
+		assert.Equal(t, createdAt, account.CreatedAt)
+		assert.Equal(t, createdAt, account.UpdatedAt)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("create account fail due to duplicate", func(t *testing.T) {
 		// given
-		mock.ExpectExec(`INSERT INTO accounts`).
-			WithArgs(account.AccountID, account.Balance).
+		mock.ExpectQuery(`INSERT INTO accounts`).
+			WithArgs(account.AccountID, account.Balance, account.Type, "Card fees", "finance", []byte(`{"team":"billing"}`)).
 			WillReturnError(&pq.Error{Code: pgerrcode.UniqueViolation})
 
 		// when
@@ -57,8 +70,8 @@ func TestAccountRepository_CreateAccount(t *testing.T) {
 
 	t.Run("create account fail due to database error", func(t *testing.T) {
 		// given
-		mock.ExpectExec(`INSERT INTO accounts`).
-			WithArgs(account.AccountID, account.Balance).
+		mock.ExpectQuery(`INSERT INTO accounts`).
+			WithArgs(account.AccountID, account.Balance, account.Type, "Card fees", "finance", []byte(`{"team":"billing"}`)).
 			WillReturnError(assert.AnError) // any unexpected error
 
 		// when
@@ -71,6 +84,97 @@ func TestAccountRepository_CreateAccount(t *testing.T) {
 	})
 }
 
+func TestAccountRepository_NextAccountID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := &accountRepository{db: db}
+
+	t.Run("appends the check digit to the sequence number", func(t *testing.T) {
+		// given
+		mock.ExpectQuery(`SELECT nextval\('account_id_seq'\)`).
+			WillReturnRows(sqlmock.NewRows([]string{"nextval"}).AddRow(int64(10_000_000_001)))
+
+		// when
+		id, err := repo.NextAccountID(context.Background())
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, int64(100_000_000_016), id)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		// given
+		mock.ExpectQuery(`SELECT nextval`).WillReturnError(assert.AnError)
+
+		// when
+		_, err := repo.NextAccountID(context.Background())
+
+		// then
+		assert.ErrorContains(t, err, "allocate account id failed")
+	})
+}
+
+func TestAccountRepository_CreateAccounts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &accountRepository{db: db}
+	ctx := context.Background()
+
+	t.Run("skips existing accounts", func(t *testing.T) {
+		// given
+		accounts := []*model.Account{{AccountID: 1, Balance: 10}, {AccountID: 2, Balance: 20}}
+		mock.ExpectQuery(`INSERT INTO accounts \(account_id, balance, initial_balance\) VALUES \(\$1, \$2, \$2\), \(\$3, \$4, \$4\) ON CONFLICT \(account_id\) DO NOTHING RETURNING account_id`).
+			WithArgs(int64(1), 10.0, int64(2), 20.0).
+			WillReturnRows(sqlmock.NewRows([]string{"account_id"}).AddRow(2))
+
+		// when
+		created, err := repo.CreateAccounts(ctx, accounts)
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{2}, created)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("inserts in batches", func(t *testing.T) {
+		// given
+		accounts := make([]*model.Account, createAccountsBatchSize+1)
+		for i := range accounts {
+			accounts[i] = &model.Account{AccountID: int64(i + 1), Balance: 1}
+		}
+		mock.ExpectQuery(`INSERT INTO accounts`).
+			WillReturnRows(sqlmock.NewRows([]string{"account_id"}).AddRow(1))
+		mock.ExpectQuery(`INSERT INTO accounts \(account_id, balance, initial_balance\) VALUES \(\$1, \$2, \$2\) ON CONFLICT`).
+			WithArgs(int64(createAccountsBatchSize+1), 1.0).
+			WillReturnRows(sqlmock.NewRows([]string{"account_id"}).AddRow(createAccountsBatchSize + 1))
+
+		// when
+		created, err := repo.CreateAccounts(ctx, accounts)
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{1, createAccountsBatchSize + 1}, created)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		// given
+		mock.ExpectQuery(`INSERT INTO accounts`).WillReturnError(assert.AnError)
+
+		// when
+		created, err := repo.CreateAccounts(ctx, []*model.Account{{AccountID: 1, Balance: 10}})
+
+		// then
+		assert.Nil(t, created)
+		assert.ErrorContains(t, err, "create accounts failed")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
 func TestAccountRepository_GetAccount(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	require.NoError(t, err)
@@ -79,13 +183,14 @@ func TestAccountRepository_GetAccount(t *testing.T) {
 	repo := &accountRepository{db: db}
 	ctx := context.Background()
 	accountID := int64(123)
+	createdAt := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
 
 	t.Run("get account successfully", func(t *testing.T) {
 		// given
-		rows := sqlmock.NewRows([]string{"account_id", "balance"}).
-			AddRow(accountID, 100.0)
+		rows := sqlmock.NewRows(accountRowColumns).
+			AddRow(accountID, 100.0, "customer", "open", "", "", []byte("{}"), 1, createdAt, createdAt)
 
-		mock.ExpectQuery(`SELECT account_id, balance FROM accounts WHERE account_id = \$1`).
+		mock.ExpectQuery(`SELECT account_id, balance, type, status, name, owner, labels, version, created_at, updated_at FROM accounts WHERE account_id = \$1`).
 			WithArgs(accountID).
 			WillReturnRows(rows)
 
@@ -97,12 +202,14 @@ func TestAccountRepository_GetAccount(t *testing.T) {
 		assert.NotNil(t, account)
 		assert.Equal(t, accountID, account.AccountID)
 		assert.Equal(t, 100.0, account.Balance)
+		assert.Equal(t, model.AccountCustomer, account.Type)
+		assert.Equal(t, createdAt, account.CreatedAt)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("get account fail due to account not found", func(t *testing.T) {
 		// given
-		mock.ExpectQuery(`SELECT account_id, balance FROM accounts WHERE account_id = \$1`).
+		mock.ExpectQuery(`SELECT account_id, balance, type, status, name, owner, labels, version, created_at, updated_at FROM accounts WHERE account_id = \$1`).
 			WithArgs(accountID).
 			WillReturnError(sql.ErrNoRows)
 
@@ -117,7 +224,7 @@ func TestAccountRepository_GetAccount(t *testing.T) {
 
 	t.Run("get account fail due to database error", func(t *testing.T) {
 		// given
-		mock.ExpectQuery(`SELECT account_id, balance FROM accounts WHERE account_id = \$1`).
+		mock.ExpectQuery(`SELECT account_id, balance, type, status, name, owner, labels, version, created_at, updated_at FROM accounts WHERE account_id = \$1`).
 			WithArgs(accountID).
 			WillReturnError(assert.AnError)
 
@@ -132,6 +239,58 @@ func TestAccountRepository_GetAccount(t *testing.T) {
 	})
 }
 
+func TestAccountRepository_GetAccountForUpdate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &accountRepository{db: db}
+	ctx := context.Background()
+	accountID := int64(123)
+	createdAt := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	t.Run("locks the row", func(t *testing.T) {
+		// given
+		rows := sqlmock.NewRows(accountRowColumns).
+			AddRow(accountID, 100.0, "customer", "open", "", "", []byte("{}"), 1, createdAt, createdAt)
+
+		mock.ExpectQuery(`SELECT account_id, balance, type, status, name, owner, labels, version, created_at, updated_at FROM accounts WHERE account_id = \$1 FOR UPDATE`).
+			WithArgs(accountID).
+			WillReturnRows(rows)
+
+		// when
+		account, err := repo.GetAccountForUpdate(ctx, accountID)
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, &model.Account{
+			AccountID: accountID,
+			Balance:   100.0,
+			Type:      model.AccountCustomer,
+			Status:    model.AccountOpen,
+			Version:   1,
+			CreatedAt: createdAt,
+			UpdatedAt: createdAt,
+		}, account)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("account not found", func(t *testing.T) {
+		// given
+		mock.ExpectQuery(`SELECT account_id, balance, type, status, name, owner, labels, version, created_at, updated_at FROM accounts WHERE account_id = \$1 FOR UPDATE`).
+			WithArgs(accountID).
+			WillReturnError(sql.ErrNoRows)
+
+		// when
+		account, err := repo.GetAccountForUpdate(ctx, accountID)
+
+		// then
+		assert.Nil(t, account)
+		assert.ErrorIs(t, err, domain.ErrAccountNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
 func TestAccountRepository_UpdateBalance(t *testing.T) {
 	// given
 	db, mock, err := sqlmock.New()
@@ -144,16 +303,12 @@ func TestAccountRepository_UpdateBalance(t *testing.T) {
 	newBalance := 200.0
 
 	t.Run("success", func(t *testing.T) {
-		mock.ExpectBegin()
-		tx, err := db.Begin()
-		require.NoError(t, err)
-
 		mock.ExpectExec(`UPDATE accounts SET balance =`).
 			WithArgs(newBalance, accountID).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 
 		// when
-		err = repo.UpdateBalance(ctx, tx, accountID, newBalance)
+		err := repo.UpdateBalance(ctx, accountID, newBalance)
 
 		// then
 		assert.NoError(t, err)
@@ -161,16 +316,12 @@ func TestAccountRepository_UpdateBalance(t *testing.T) {
 	})
 
 	t.Run("db error", func(t *testing.T) {
-		mock.ExpectBegin()
-		tx, err := db.Begin()
-		require.NoError(t, err)
-
 		mock.ExpectExec(`UPDATE accounts SET balance =`).
 			WithArgs(newBalance, accountID).
 			WillReturnError(assert.AnError)
 
 		// when
-		err = repo.UpdateBalance(ctx, tx, accountID, newBalance)
+		err := repo.UpdateBalance(ctx, accountID, newBalance)
 
 		// then
 		assert.Error(t, err)
@@ -178,3 +329,202 @@ func TestAccountRepository_UpdateBalance(t *testing.T) {
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
+
+func TestAccountRepository_UpdateType(t *testing.T) {
+	// given
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &accountRepository{db: db}
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectExec(`UPDATE accounts SET type = \$1, version = version \+ 1, updated_at = NOW\(\) WHERE account_id = \$2`).
+			WithArgs(model.AccountSystem, int64(1000)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		// when
+		err := repo.UpdateType(ctx, 1000, model.AccountSystem)
+
+		// then
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("account not found", func(t *testing.T) {
+		mock.ExpectExec(`UPDATE accounts SET type`).
+			WithArgs(model.AccountSystem, int64(1000)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		// when
+		err := repo.UpdateType(ctx, 1000, model.AccountSystem)
+
+		// then
+		assert.ErrorIs(t, err, domain.ErrAccountNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestAccountRepository_ListAccounts(t *testing.T) {
+	// given
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &accountRepository{db: db}
+	ctx := context.Background()
+	createdAt := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	t.Run("filtered by type and status", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT account_id, balance, type, status, name, owner, labels, version, created_at, updated_at FROM accounts `+
+			`WHERE type = ANY\(\$1\) AND status = ANY\(\$2\) ORDER BY account_id LIMIT \$3`).
+			WithArgs(pq.Array([]string{"revenue", "expense"}), pq.Array([]string{"open"}), 10).
+			WillReturnRows(sqlmock.NewRows(accountRowColumns).
+				AddRow(7, 12.5, "revenue", "open", "", "", []byte("{}"), 1, createdAt, createdAt).
+				AddRow(8, -3.0, "expense", "open", "", "", []byte("{}"), 1, createdAt, createdAt))
+
+		// when
+		accounts, err := repo.ListAccounts(ctx, AccountFilter{
+			Types:    []model.AccountType{model.AccountRevenue, model.AccountExpense},
+			Statuses: []model.AccountStatus{model.AccountOpen},
+			Limit:    10,
+		})
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, accounts, 2)
+		assert.Equal(t, model.AccountExpense, accounts[1].Type)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("filtered by owner, balance and creation time", func(t *testing.T) {
+		minBalance, maxBalance := 10.0, 500.0
+		mock.ExpectQuery(`SELECT account_id, balance, type, status, name, owner, labels, version, created_at, updated_at FROM accounts `+
+			`WHERE owner = \$1 AND balance >= \$2 AND balance <= \$3 AND created_at >= \$4 AND created_at < \$5 ORDER BY account_id$`).
+			WithArgs("cust-1", minBalance, maxBalance, createdAt, createdAt.AddDate(0, 0, 1)).
+			WillReturnRows(sqlmock.NewRows(accountRowColumns).
+				AddRow(7, 12.5, "customer", "open", "", "", []byte("{}"), 1, createdAt, createdAt))
+
+		// when
+		accounts, err := repo.ListAccounts(ctx, AccountFilter{
+			Owner:       "cust-1",
+			MinBalance:  &minBalance,
+			MaxBalance:  &maxBalance,
+			CreatedFrom: createdAt,
+			CreatedTo:   createdAt.AddDate(0, 0, 1),
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Len(t, accounts, 1)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("resumes after the cursor in descending balance order", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT account_id, balance, type, status, name, owner, labels, version, created_at, updated_at FROM accounts `+
+			`WHERE type = ANY\(\$1\) AND \(balance, account_id\) < \(\$2, \$3\) ORDER BY balance DESC, account_id DESC LIMIT \$4`).
+			WithArgs(pq.Array([]string{"customer"}), 12.5, int64(7), 2).
+			WillReturnRows(sqlmock.NewRows(accountRowColumns).
+				AddRow(9, 12.5, "customer", "open", "", "", []byte("{}"), 1, createdAt, createdAt).
+				AddRow(3, 4.0, "customer", "open", "", "", []byte("{}"), 1, createdAt, createdAt))
+
+		// when
+		accounts, err := repo.ListAccounts(ctx, AccountFilter{
+			Types: []model.AccountType{model.AccountCustomer},
+			Sort:  AccountSortBalance,
+			Desc:  true,
+			After: &AccountCursor{AccountID: 7, Balance: 12.5},
+			Limit: 2,
+		})
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, accounts, 2)
+		assert.Equal(t, int64(3), accounts[1].AccountID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("resumes after the cursor in creation order", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT account_id, balance, type, status, name, owner, labels, version, created_at, updated_at FROM accounts `+
+			`WHERE \(created_at, account_id\) > \(\$1, \$2\) ORDER BY created_at, account_id$`).
+			WithArgs(createdAt, int64(7)).
+			WillReturnRows(sqlmock.NewRows(accountRowColumns))
+
+		// when
+		accounts, err := repo.ListAccounts(ctx, AccountFilter{
+			Sort:  AccountSortCreatedAt,
+			After: &AccountCursor{AccountID: 7, CreatedAt: createdAt},
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, accounts)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("unknown sort", func(t *testing.T) {
+		// when
+		_, err := repo.ListAccounts(ctx, AccountFilter{Sort: "owner"})
+
+		// then
+		assert.ErrorContains(t, err, "unknown sort")
+	})
+
+	t.Run("db error", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT account_id, balance, type, status, name, owner, labels, version, created_at, updated_at FROM accounts ORDER BY account_id`).
+			WillReturnError(assert.AnError)
+
+		// when
+		accounts, err := repo.ListAccounts(ctx, AccountFilter{})
+
+		// then
+		assert.Nil(t, accounts)
+		assert.ErrorContains(t, err, "list accounts failed")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestAccountRepository_UpdateDetails(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &accountRepository{db: db}
+	ctx := context.Background()
+	updatedAt := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	t.Run("advances the version", func(t *testing.T) {
+		// given
+		account := &model.Account{
+			AccountID: 7, Status: model.AccountClosed, Name: "Savings", Owner: "cust-1", Labels: map[string]string{"tier": "gold"}, Version: 2,
+		}
+		mock.ExpectQuery(`UPDATE accounts SET name = \$1, owner = \$2, labels = \$3, status = \$4, version = version \+ 1, `+
+			`updated_at = NOW\(\)\s+WHERE account_id = \$5 AND version = \$6`).
+			WithArgs("Savings", "cust-1", []byte(`{"tier":"gold"}`), model.AccountClosed, int64(7), int64(2)).
+			WillReturnRows(sqlmock.NewRows([]string{"version", "updated_at"}).AddRow(3, updatedAt))
+
+		// when
+		err := repo.UpdateDetails(ctx, account)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), account.Version)
+		assert.Equal(t, updatedAt, account.UpdatedAt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("version mismatch", func(t *testing.T) {
+		// given
+		mock.ExpectQuery(`UPDATE accounts SET name`).
+			WithArgs("", "", []byte(`{}`), model.AccountStatus(""), int64(7), int64(2)).
+			WillReturnRows(sqlmock.NewRows([]string{"version", "updated_at"}))
+
+		// when
+		err := repo.UpdateDetails(ctx, &model.Account{AccountID: 7, Version: 2})
+
+		// then
+		assert.ErrorIs(t, err, domain.ErrAccountVersionMismatch)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel/attribute"
+
+	"internal-transfers/internal/domain"
+)
+
+// BalanceRepository computes historical balances and maintains the snapshots that bound that work
+//
+//go:generate mockery --name=BalanceRepository --filename=balance_mock.go --output=./mocks --with-expecter
+type BalanceRepository interface {
+	// BalanceAt returns the balance of an account once every transaction created before at is applied
+	BalanceAt(ctx context.Context, accountID int64, at time.Time) (float64, error)
+	// CreateSnapshots records the balance of every account as of at, skipping accounts that already have a
+	// snapshot then, and returns how many were written
+	CreateSnapshots(ctx context.Context, at time.Time) (int64, error)
+	// DeleteSnapshotsAfter drops the snapshots of the accounts taken after at, which a backdated entry at at
+	// makes stale
+	DeleteSnapshotsAfter(ctx context.Context, accountIDs []int64, at time.Time) error
+}
+
+type balanceRepository struct {
+	db querier
+}
+
+func NewBalanceRepository(db *sql.DB) BalanceRepository {
+	return &balanceRepository{db: db}
+}
+
+func (r *balanceRepository) BalanceAt(ctx context.Context, accountID int64, at time.Time) (_ float64, err error) {
+	ctx, span := startSpan(ctx, "BalanceAt", attribute.Int64("account.id", accountID))
+	defer func() { endSpan(span, err) }()
+
+	// roll forward from the latest snapshot at or before at; without one, roll back from the current balance
+	query := `
+        WITH snap AS (
+            SELECT balance, as_of FROM balance_snapshots
+            WHERE account_id = $1 AND as_of <= $2
+            ORDER BY as_of DESC
+            LIMIT 1
+        )
+        SELECT CASE WHEN EXISTS (SELECT 1 FROM snap) THEN
+            (SELECT balance FROM snap) + COALESCE((
+                SELECT SUM(CASE WHEN t.destination_account_id = $1 THEN t.amount ELSE -t.amount END)
+                FROM transactions t, snap s
+                WHERE (t.source_account_id = $1 OR t.destination_account_id = $1)
+                    AND t.created_at >= s.as_of AND t.created_at < $2
+            ), 0)
+        ELSE
+            a.balance - COALESCE((
+                SELECT SUM(CASE WHEN t.destination_account_id = $1 THEN t.amount ELSE -t.amount END)
+                FROM transactions t
+                WHERE (t.source_account_id = $1 OR t.destination_account_id = $1) AND t.created_at >= $2
+            ), 0)
+        END
+        FROM accounts a
+        WHERE a.account_id = $1`
+
+	var balance float64
+	if err := r.db.QueryRowContext(ctx, query, accountID, at).Scan(&balance); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, domain.ErrAccountNotFound
+		}
+		return 0, fmt.Errorf("get balance at failed: %w", err)
+	}
+	return balance, nil
+}
+
+func (r *balanceRepository) CreateSnapshots(ctx context.Context, at time.Time) (_ int64, err error) {
+	ctx, span := startSpan(ctx, "CreateSnapshots", attribute.String("snapshot.as_of", at.Format(time.RFC3339)))
+	defer func() { endSpan(span, err) }()
+
+	query := `
+        INSERT INTO balance_snapshots (account_id, as_of, balance)
+        SELECT a.account_id, $1, a.balance - COALESCE(f.net, 0)
+        FROM accounts a
+        LEFT JOIN (
+            SELECT account_id, SUM(delta) AS net FROM (
+                SELECT destination_account_id AS account_id, amount AS delta FROM transactions WHERE created_at >= $1
+                UNION ALL
+                SELECT source_account_id, -amount FROM transactions WHERE created_at >= $1
+            ) d
+            GROUP BY account_id
+        ) f ON f.account_id = a.account_id
+        ON CONFLICT (account_id, as_of) DO NOTHING`
+	res, err := r.db.ExecContext(ctx, query, at)
+	if err != nil {
+		return 0, fmt.Errorf("create snapshots failed: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("create snapshots failed: %w", err)
+	}
+	return n, nil
+}
+
+func (r *balanceRepository) DeleteSnapshotsAfter(ctx context.Context, accountIDs []int64, at time.Time) (err error) {
+	ctx, span := startSpan(ctx, "DeleteSnapshotsAfter", attribute.String("snapshot.after", at.Format(time.RFC3339)))
+	defer func() { endSpan(span, err) }()
+
+	query := `DELETE FROM balance_snapshots WHERE account_id = ANY($1) AND as_of > $2`
+	if _, err := r.db.ExecContext(ctx, query, pq.Array(accountIDs), at); err != nil {
+		return fmt.Errorf("delete snapshots failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"internal-transfers/internal/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBalanceRepository_BalanceAt(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &balanceRepository{db: db}
+	ctx := context.Background()
+	at := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		// given
+		mock.ExpectQuery(`WITH snap AS`).
+			WithArgs(int64(1), at).
+			WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow(75.5))
+
+		// when
+		balance, err := repo.BalanceAt(ctx, 1, at)
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, 75.5, balance)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("account not found", func(t *testing.T) {
+		// given
+		mock.ExpectQuery(`WITH snap AS`).WillReturnError(sql.ErrNoRows)
+
+		// when
+		_, err := repo.BalanceAt(ctx, 1, at)
+
+		// then
+		assert.ErrorIs(t, err, domain.ErrAccountNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		// given
+		mock.ExpectQuery(`WITH snap AS`).WillReturnError(assert.AnError)
+
+		// when
+		_, err := repo.BalanceAt(ctx, 1, at)
+
+		// then
+		assert.ErrorContains(t, err, "get balance at failed")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestBalanceRepository_CreateSnapshots(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &balanceRepository{db: db}
+	at := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	// given
+	mock.ExpectExec(`INSERT INTO balance_snapshots .* ON CONFLICT \(account_id, as_of\) DO NOTHING`).
+		WithArgs(at).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	// when
+	n, err := repo.CreateSnapshots(context.Background(), at)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBalanceRepository_DeleteSnapshotsAfter(t *testing.T) {
+	// given
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := &balanceRepository{db: db}
+	at := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	mock.ExpectExec(`DELETE FROM balance_snapshots WHERE account_id = ANY\(\$1\) AND as_of > \$2`).
+		WithArgs(pq.Array([]int64{1, 2}), at).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	// when
+	err = repo.DeleteSnapshotsAfter(context.Background(), []int64{1, 2}, at)
+
+	// then
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
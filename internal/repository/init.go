@@ -1,29 +1,92 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"internal-transfers/internal/config"
 
 	_ "github.com/lib/pq"
+	"github.com/rs/zerolog/log"
 )
 
-func InitDB(cfg config.DBConfig) (*sql.DB, error) {
-	dsn := fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
-		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Name,
-	)
-
-	db, err := sql.Open("postgres", dsn)
+// InitDB opens a tuned connection pool and waits, retrying with exponential backoff, until Postgres accepts connections
+func InitDB(ctx context.Context, cfg config.DBConfig) (*sql.DB, error) {
+	db, err := sql.Open("postgres", buildDSN(cfg))
 	if err != nil {
 		return nil, err
 	}
 
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
 	// verify connection
-	if err := db.Ping(); err != nil {
+	if err := pingWithRetry(ctx, db, cfg); err != nil {
+		_ = db.Close()
 		return nil, err
 	}
 
 	return db, nil
 }
+
+func pingWithRetry(ctx context.Context, db *sql.DB, cfg config.DBConfig) error {
+	backoff := cfg.ConnectBackoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = db.PingContext(ctx); err == nil {
+			return nil
+		}
+		if attempt >= cfg.ConnectAttempts {
+			return fmt.Errorf("database unreachable after %d attempts: %w", attempt, err)
+		}
+
+		log.Warn().Err(err).Int("attempt", attempt).Dur("retry_in", backoff).Msg("database not ready, retrying")
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("gave up connecting to database: %w", ctx.Err())
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, cfg.ConnectMaxBackoff)
+	}
+}
+
+// buildDSN renders cfg as a key/value connection string. Keys lib/pq does not recognise, such as
+// statement_timeout, are sent to the server as run-time parameters of every connection.
+func buildDSN(cfg config.DBConfig) string {
+	params := [][2]string{
+		{"host", cfg.Host},
+		{"port", strconv.Itoa(cfg.Port)},
+		{"user", cfg.User},
+		{"password", cfg.Password},
+		{"dbname", cfg.Name},
+		{"sslmode", cfg.SSLMode},
+		{"sslrootcert", cfg.SSLRootCert},
+		{"sslcert", cfg.SSLCert},
+		{"sslkey", cfg.SSLKey},
+	}
+	if cfg.StatementTimeout > 0 {
+		params = append(params, [2]string{"statement_timeout", strconv.FormatInt(cfg.StatementTimeout.Milliseconds(), 10)})
+	}
+
+	var parts []string
+	for _, p := range params {
+		if p[1] == "" {
+			continue
+		}
+		parts = append(parts, p[0]+"="+quoteDSNValue(p[1]))
+	}
+	return strings.Join(parts, " ")
+}
+
+// quoteDSNValue single-quotes v, escaping backslashes and quotes, so values may contain spaces
+func quoteDSNValue(v string) string {
+	v = strings.ReplaceAll(v, `\`, `\\`)
+	v = strings.ReplaceAll(v, `'`, `\'`)
+	return "'" + v + "'"
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"internal-transfers/internal/config"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildDSN(t *testing.T) {
+	t.Run("tls and statement timeout", func(t *testing.T) {
+		// given
+		cfg := config.DBConfig{
+			Host:             "db.internal",
+			Port:             5432,
+			User:             "app",
+			Password:         `it's a \secret`,
+			Name:             "internal_transfers",
+			SSLMode:          "verify-full",
+			SSLRootCert:      "/etc/ssl/root.crt",
+			StatementTimeout: 5 * time.Second,
+		}
+
+		// when
+		dsn := buildDSN(cfg)
+
+		// then
+		assert.Equal(t,
+			`host='db.internal' port='5432' user='app' password='it\'s a \\secret' dbname='internal_transfers' `+
+				`sslmode='verify-full' sslrootcert='/etc/ssl/root.crt' statement_timeout='5000'`,
+			dsn)
+	})
+
+	t.Run("omits unset options", func(t *testing.T) {
+		// given
+		cfg := config.DBConfig{Host: "localhost", Port: 5432, User: "app", Name: "db", SSLMode: "disable"}
+
+		// when
+		dsn := buildDSN(cfg)
+
+		// then
+		assert.Equal(t, `host='localhost' port='5432' user='app' dbname='db' sslmode='disable'`, dsn)
+	})
+}
+
+func TestPingWithRetry(t *testing.T) {
+	cfg := config.DBConfig{
+		ConnectAttempts:   3,
+		ConnectBackoff:    time.Millisecond,
+		ConnectMaxBackoff: 2 * time.Millisecond,
+	}
+
+	t.Run("succeeds after transient failures", func(t *testing.T) {
+		// given
+		db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectPing().WillReturnError(assert.AnError)
+		mock.ExpectPing().WillReturnError(assert.AnError)
+		mock.ExpectPing()
+
+		// when
+		err = pingWithRetry(context.Background(), db, cfg)
+
+		// then
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		// given
+		db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+		require.NoError(t, err)
+		defer db.Close()
+
+		for i := 0; i < cfg.ConnectAttempts; i++ {
+			mock.ExpectPing().WillReturnError(assert.AnError)
+		}
+
+		// when
+		err = pingWithRetry(context.Background(), db, cfg)
+
+		// then
+		assert.ErrorContains(t, err, "database unreachable after 3 attempts")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,220 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel/attribute"
+
+	"internal-transfers/internal/domain"
+	"internal-transfers/internal/model"
+)
+
+// InterestRepository records the interest accounts accrue each day and the postings that pay it out
+//
+//go:generate mockery --name=InterestRepository --filename=interest_mock.go --output=./mocks --with-expecter
+type InterestRepository interface {
+	// RecordAccrual stores the accrual of an account for a day, unless that day was already accrued and is not
+	// stale
+	RecordAccrual(ctx context.Context, accrual *model.InterestAccrual) error
+	// LastAccrualDate returns the latest day accrued for an account, or the zero time if none was. Once accruals
+	// are reopened it returns the day before the earliest stale one, so they are accrued again in order.
+	LastAccrualDate(ctx context.Context, accountID int64) (time.Time, error)
+	// AccruedInterest sums the accruals of an account for the days in [from, to)
+	AccruedInterest(ctx context.Context, accountID int64, from, to time.Time) (float64, error)
+	// PendingPostings returns, oldest first, the periods ending on or before end that were not posted yet and
+	// whose accruals are all current and cover every day from the first one through the period's last day
+	PendingPostings(ctx context.Context, end time.Time) ([]model.InterestPosting, error)
+	// ClaimPosting records that the posting is being paid, and reports false if it was posted already or is
+	// being paid by a claim made at or after staleBefore
+	ClaimPosting(ctx context.Context, posting model.InterestPosting, now, staleBefore time.Time) (bool, error)
+	// CompletePosting marks a claimed posting as paid
+	CompletePosting(ctx context.Context, accountID int64, periodStart, postedAt time.Time) error
+	// ReopenAccruals marks the accruals of the accounts for the days from from on stale, as a backdated entry
+	// changed the balances they were earned on. It fails with an error wrapping domain.ErrPeriodClosed if
+	// interest for any of those days was already claimed or paid.
+	ReopenAccruals(ctx context.Context, accountIDs []int64, from time.Time) error
+}
+
+type interestRepository struct {
+	db querier
+}
+
+func NewInterestRepository(db *sql.DB) InterestRepository {
+	return &interestRepository{db: db}
+}
+
+// sqlDate formats the UTC day of t for a DATE column, so the session time zone cannot shift it
+func sqlDate(t time.Time) string {
+	return t.UTC().Format(time.DateOnly)
+}
+
+func (r *interestRepository) RecordAccrual(ctx context.Context, accrual *model.InterestAccrual) (err error) {
+	ctx, span := startSpan(ctx, "RecordAccrual",
+		attribute.Int64("account.id", accrual.AccountID),
+		attribute.String("interest.date", sqlDate(accrual.Date)),
+	)
+	defer func() { endSpan(span, err) }()
+
+	query := `
+        INSERT INTO interest_accruals (account_id, accrual_date, product, balance, rate, amount, period_start, period_end)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+        ON CONFLICT (account_id, accrual_date) DO UPDATE
+            SET product = EXCLUDED.product, balance = EXCLUDED.balance, rate = EXCLUDED.rate, amount = EXCLUDED.amount,
+                period_start = EXCLUDED.period_start, period_end = EXCLUDED.period_end, stale = false
+            WHERE interest_accruals.stale`
+	_, err = r.db.ExecContext(ctx, query, accrual.AccountID, sqlDate(accrual.Date), accrual.Product, accrual.Balance,
+		accrual.Rate, accrual.Amount, sqlDate(accrual.PeriodStart), sqlDate(accrual.PeriodEnd))
+	if err != nil {
+		return fmt.Errorf("record accrual failed: %w", err)
+	}
+	return nil
+}
+
+func (r *interestRepository) LastAccrualDate(ctx context.Context, accountID int64) (_ time.Time, err error) {
+	ctx, span := startSpan(ctx, "LastAccrualDate", attribute.Int64("account.id", accountID))
+	defer func() { endSpan(span, err) }()
+
+	var last sql.NullTime
+	query := `
+        SELECT COALESCE(MIN(accrual_date) FILTER (WHERE stale) - 1, MAX(accrual_date))
+        FROM interest_accruals WHERE account_id = $1`
+	if err := r.db.QueryRowContext(ctx, query, accountID).Scan(&last); err != nil {
+		return time.Time{}, fmt.Errorf("get last accrual failed: %w", err)
+	}
+	if !last.Valid {
+		return time.Time{}, nil
+	}
+	return utcDate(last.Time), nil
+}
+
+func (r *interestRepository) AccruedInterest(ctx context.Context, accountID int64, from, to time.Time) (_ float64, err error) {
+	ctx, span := startSpan(ctx, "AccruedInterest", attribute.Int64("account.id", accountID))
+	defer func() { endSpan(span, err) }()
+
+	var accrued float64
+	query := `
+        SELECT COALESCE(SUM(amount), 0) FROM interest_accruals
+        WHERE account_id = $1 AND accrual_date >= $2 AND accrual_date < $3`
+	if err := r.db.QueryRowContext(ctx, query, accountID, sqlDate(from), sqlDate(to)).Scan(&accrued); err != nil {
+		return 0, fmt.Errorf("sum accruals failed: %w", err)
+	}
+	return accrued, nil
+}
+
+func (r *interestRepository) PendingPostings(ctx context.Context, end time.Time) (_ []model.InterestPosting, err error) {
+	ctx, span := startSpan(ctx, "PendingPostings", attribute.String("interest.period_end", sqlDate(end)))
+	defer func() { endSpan(span, err) }()
+
+	query := `
+        SELECT a.account_id, MAX(a.product), a.period_start, a.period_end, SUM(a.amount)
+        FROM interest_accruals a
+        WHERE a.period_end <= $1 AND NOT EXISTS (
+            SELECT 1 FROM interest_postings p
+            WHERE p.account_id = a.account_id AND p.period_start = a.period_start AND p.status = 'posted'
+        )
+        GROUP BY a.account_id, a.period_start, a.period_end
+        HAVING NOT BOOL_OR(a.stale)
+            AND MAX(a.accrual_date) = a.period_end - 1
+            AND COUNT(*) = MAX(a.accrual_date) - MIN(a.accrual_date) + 1
+        ORDER BY a.period_start, a.account_id`
+	rows, err := r.db.QueryContext(ctx, query, sqlDate(end))
+	if err != nil {
+		return nil, fmt.Errorf("list pending postings failed: %w", err)
+	}
+	defer rows.Close()
+
+	var postings []model.InterestPosting
+	for rows.Next() {
+		var p model.InterestPosting
+		if err := rows.Scan(&p.AccountID, &p.Product, &p.PeriodStart, &p.PeriodEnd, &p.Amount); err != nil {
+			return nil, fmt.Errorf("row scan failed: %w", err)
+		}
+		p.PeriodStart, p.PeriodEnd = utcDate(p.PeriodStart), utcDate(p.PeriodEnd)
+		postings = append(postings, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return postings, nil
+}
+
+func (r *interestRepository) ClaimPosting(ctx context.Context, posting model.InterestPosting, now, staleBefore time.Time) (_ bool, err error) {
+	ctx, span := startSpan(ctx, "ClaimPosting",
+		attribute.Int64("account.id", posting.AccountID),
+		attribute.String("interest.period_start", sqlDate(posting.PeriodStart)),
+	)
+	defer func() { endSpan(span, err) }()
+
+	query := `
+        INSERT INTO interest_postings (account_id, period_start, period_end, amount, claimed_at)
+        VALUES ($1, $2, $3, $4, $5)
+        ON CONFLICT (account_id, period_start) DO UPDATE
+            SET claimed_at = EXCLUDED.claimed_at, amount = EXCLUDED.amount
+            WHERE interest_postings.status = 'pending' AND interest_postings.claimed_at < $6
+        RETURNING account_id`
+	var id int64
+	err = r.db.QueryRowContext(ctx, query, posting.AccountID, sqlDate(posting.PeriodStart), sqlDate(posting.PeriodEnd),
+		posting.Amount, now, staleBefore).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("claim posting failed: %w", err)
+	}
+	return true, nil
+}
+
+func (r *interestRepository) CompletePosting(ctx context.Context, accountID int64, periodStart, postedAt time.Time) (err error) {
+	ctx, span := startSpan(ctx, "CompletePosting",
+		attribute.Int64("account.id", accountID),
+		attribute.String("interest.period_start", sqlDate(periodStart)),
+	)
+	defer func() { endSpan(span, err) }()
+
+	query := `
+        UPDATE interest_postings SET status = 'posted', posted_at = $3
+        WHERE account_id = $1 AND period_start = $2`
+	if _, err := r.db.ExecContext(ctx, query, accountID, sqlDate(periodStart), postedAt); err != nil {
+		return fmt.Errorf("complete posting failed: %w", err)
+	}
+	return nil
+}
+
+func (r *interestRepository) ReopenAccruals(ctx context.Context, accountIDs []int64, from time.Time) (err error) {
+	ctx, span := startSpan(ctx, "ReopenAccruals", attribute.String("interest.from", sqlDate(from)))
+	defer func() { endSpan(span, err) }()
+
+	var (
+		accountID int64
+		paidUntil time.Time
+	)
+	query := `
+        SELECT account_id, period_end FROM interest_postings
+        WHERE account_id = ANY($1) AND period_end > $2
+        ORDER BY period_end DESC
+        LIMIT 1`
+	err = r.db.QueryRowContext(ctx, query, pq.Array(accountIDs), sqlDate(from)).Scan(&accountID, &paidUntil)
+	if err == nil {
+		return fmt.Errorf("%w: interest of account %d is paid through %s", domain.ErrPeriodClosed, accountID,
+			utcDate(paidUntil).AddDate(0, 0, -1).Format(time.DateOnly))
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("check interest postings failed: %w", err)
+	}
+
+	query = `UPDATE interest_accruals SET stale = true WHERE account_id = ANY($1) AND accrual_date >= $2`
+	if _, err := r.db.ExecContext(ctx, query, pq.Array(accountIDs), sqlDate(from)); err != nil {
+		return fmt.Errorf("reopen accruals failed: %w", err)
+	}
+	return nil
+}
+
+// utcDate returns the day of a scanned DATE as a UTC midnight
+func utcDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
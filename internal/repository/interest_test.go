@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"internal-transfers/internal/domain"
+	"internal-transfers/internal/model"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterestRepository_RecordAccrual(t *testing.T) {
+	// given
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := &interestRepository{db: db}
+	accrual := &model.InterestAccrual{
+		AccountID:   1,
+		Date:        time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+		Product:     "savings",
+		Balance:     1000,
+		Rate:        0.0365,
+		Amount:      0.1,
+		PeriodStart: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:   time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+	}
+	mock.ExpectExec(`INSERT INTO interest_accruals .* ON CONFLICT \(account_id, accrual_date\) DO UPDATE .* WHERE interest_accruals.stale`).
+		WithArgs(int64(1), "2025-01-31", "savings", 1000.0, 0.0365, 0.1, "2025-01-01", "2025-02-01").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// when
+	err = repo.RecordAccrual(context.Background(), accrual)
+
+	// then
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInterestRepository_LastAccrualDate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := &interestRepository{db: db}
+	ctx := context.Background()
+
+	t.Run("latest day", func(t *testing.T) {
+		// given
+		mock.ExpectQuery(`SELECT COALESCE\(MIN\(accrual_date\) FILTER \(WHERE stale\) - 1, MAX\(accrual_date\)\)`).
+			WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(time.Date(2025, 1, 31, 0, 0, 0, 0, time.FixedZone("", 0))))
+
+		// when
+		last, err := repo.LastAccrualDate(ctx, 1)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC), last)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("never accrued", func(t *testing.T) {
+		// given
+		mock.ExpectQuery(`SELECT COALESCE\(MIN\(accrual_date\) FILTER \(WHERE stale\) - 1, MAX\(accrual_date\)\)`).
+			WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(nil))
+
+		// when
+		last, err := repo.LastAccrualDate(ctx, 1)
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, last.IsZero())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestInterestRepository_PendingPostings(t *testing.T) {
+	// given
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := &interestRepository{db: db}
+	jan := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	feb := jan.AddDate(0, 1, 0)
+	mock.ExpectQuery(`FROM interest_accruals a\s+WHERE a.period_end <= \$1 AND NOT EXISTS .*` +
+		`AND MAX\(a.accrual_date\) = a.period_end - 1\s+AND COUNT\(\*\) = MAX\(a.accrual_date\) - MIN\(a.accrual_date\) \+ 1`).
+		WithArgs("2025-02-01").
+		WillReturnRows(sqlmock.NewRows([]string{"account_id", "product", "period_start", "period_end", "sum"}).
+			AddRow(int64(1), "savings", jan, feb, 3.1))
+
+	// when
+	postings, err := repo.PendingPostings(context.Background(), feb)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, []model.InterestPosting{{AccountID: 1, Product: "savings", PeriodStart: jan, PeriodEnd: feb, Amount: 3.1}}, postings)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInterestRepository_ClaimPosting(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := &interestRepository{db: db}
+	ctx := context.Background()
+	now := time.Date(2025, 2, 1, 1, 0, 0, 0, time.UTC)
+	staleBefore := now.Add(-10 * time.Minute)
+	posting := model.InterestPosting{
+		AccountID:   1,
+		PeriodStart: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:   time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+		Amount:      3.1,
+	}
+
+	t.Run("claimed", func(t *testing.T) {
+		// given
+		mock.ExpectQuery(`INSERT INTO interest_postings .* ON CONFLICT \(account_id, period_start\) DO UPDATE`).
+			WithArgs(int64(1), "2025-01-01", "2025-02-01", 3.1, now, staleBefore).
+			WillReturnRows(sqlmock.NewRows([]string{"account_id"}).AddRow(int64(1)))
+
+		// when
+		claimed, err := repo.ClaimPosting(ctx, posting, now, staleBefore)
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, claimed)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("posted or claimed by another run", func(t *testing.T) {
+		// given
+		mock.ExpectQuery(`INSERT INTO interest_postings`).
+			WillReturnRows(sqlmock.NewRows([]string{"account_id"}))
+
+		// when
+		claimed, err := repo.ClaimPosting(ctx, posting, now, staleBefore)
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, claimed)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestInterestRepository_ReopenAccruals(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := &interestRepository{db: db}
+	ctx := context.Background()
+	from := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	t.Run("marks the accruals from the day on stale", func(t *testing.T) {
+		// given
+		mock.ExpectQuery(`SELECT account_id, period_end FROM interest_postings`).
+			WithArgs(pq.Array([]int64{1, 2}), "2025-01-15").
+			WillReturnRows(sqlmock.NewRows([]string{"account_id", "period_end"}))
+		mock.ExpectExec(`UPDATE interest_accruals SET stale = true`).
+			WithArgs(pq.Array([]int64{1, 2}), "2025-01-15").
+			WillReturnResult(sqlmock.NewResult(0, 3))
+
+		// when
+		err := repo.ReopenAccruals(ctx, []int64{1, 2}, from)
+
+		// then
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("interest of those days already paid", func(t *testing.T) {
+		// given
+		mock.ExpectQuery(`SELECT account_id, period_end FROM interest_postings`).
+			WillReturnRows(sqlmock.NewRows([]string{"account_id", "period_end"}).
+				AddRow(int64(2), time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)))
+
+		// when
+		err := repo.ReopenAccruals(ctx, []int64{1, 2}, from)
+
+		// then
+		assert.ErrorIs(t, err, domain.ErrPeriodClosed)
+		assert.ErrorContains(t, err, "interest of account 2 is paid through 2025-01-31")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
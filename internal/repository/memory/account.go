@@ -0,0 +1,248 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"internal-transfers/internal/domain"
+	"internal-transfers/internal/model"
+	"internal-transfers/internal/repository"
+)
+
+type accountRepository struct {
+	store *Store
+	tx    *tx // nil outside of a unit of work
+}
+
+func NewAccountRepository(store *Store) repository.AccountRepository {
+	return &accountRepository{store: store}
+}
+
+func (r *accountRepository) CreateAccount(ctx context.Context, account *model.Account) error {
+	stored := *account
+	if stored.Type == "" {
+		stored.Type = model.AccountCustomer
+	}
+	stored.Status = model.AccountOpen
+	if stored.CreatedAt.IsZero() {
+		stored.CreatedAt = time.Now()
+	}
+	stored.Labels = maps.Clone(account.Labels)
+	stored.Version, stored.UpdatedAt = 1, stored.CreatedAt
+	account.Status, account.Version = stored.Status, stored.Version
+	account.CreatedAt, account.UpdatedAt = stored.CreatedAt, stored.UpdatedAt
+	if r.tx == nil {
+		r.store.mu.Lock()
+		defer r.store.mu.Unlock()
+		if _, ok := r.store.accounts[account.AccountID]; ok {
+			return domain.ErrAccountDuplicate
+		}
+		r.store.accounts[account.AccountID] = stored
+		r.store.initialBalances[account.AccountID] = account.Balance
+		return nil
+	}
+
+	if _, ok := r.lookup(account.AccountID); ok {
+		return domain.ErrAccountDuplicate
+	}
+	r.tx.accounts[account.AccountID] = stored
+	r.tx.initialBalances[account.AccountID] = account.Balance
+	return nil
+}
+
+// NextAccountID draws from the store's sequence even inside a unit of work, so like a database sequence it
+// is not rolled back
+func (r *accountRepository) NextAccountID(ctx context.Context) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	seq := r.store.nextAccountSeq
+	r.store.nextAccountSeq++
+	return model.AllocatedAccountID(seq), nil
+}
+
+func (r *accountRepository) CreateAccounts(ctx context.Context, accounts []*model.Account) ([]int64, error) {
+	var created []int64
+	for _, acc := range accounts {
+		err := r.CreateAccount(ctx, acc)
+		if errors.Is(err, domain.ErrAccountDuplicate) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		created = append(created, acc.AccountID)
+	}
+	return created, nil
+}
+
+func (r *accountRepository) GetAccount(ctx context.Context, accountID int64) (*model.Account, error) {
+	acc, ok := r.lookup(accountID)
+	if !ok {
+		return nil, domain.ErrAccountNotFound
+	}
+	return &acc, nil
+}
+
+// GetAccountForUpdate needs no extra locking as units of work already run one at a time
+func (r *accountRepository) GetAccountForUpdate(ctx context.Context, accountID int64) (*model.Account, error) {
+	return r.GetAccount(ctx, accountID)
+}
+
+// UpdateBalance ignores unknown accounts, like an UPDATE matching no rows
+func (r *accountRepository) UpdateBalance(ctx context.Context, accountID int64, newBalance float64) error {
+	if r.tx == nil {
+		r.store.mu.Lock()
+		defer r.store.mu.Unlock()
+		if acc, ok := r.store.accounts[accountID]; ok {
+			acc.Balance = newBalance
+			r.store.accounts[accountID] = acc
+		}
+		return nil
+	}
+
+	if acc, ok := r.lookup(accountID); ok {
+		acc.Balance = newBalance
+		r.tx.accounts[accountID] = acc
+	}
+	return nil
+}
+
+func (r *accountRepository) UpdateType(ctx context.Context, accountID int64, accountType model.AccountType) error {
+	if r.tx == nil {
+		r.store.mu.Lock()
+		defer r.store.mu.Unlock()
+		acc, ok := r.store.accounts[accountID]
+		if !ok {
+			return domain.ErrAccountNotFound
+		}
+		acc.Type = accountType
+		acc.Version, acc.UpdatedAt = acc.Version+1, time.Now()
+		r.store.accounts[accountID] = acc
+		return nil
+	}
+
+	acc, ok := r.lookup(accountID)
+	if !ok {
+		return domain.ErrAccountNotFound
+	}
+	acc.Type = accountType
+	acc.Version, acc.UpdatedAt = acc.Version+1, time.Now()
+	r.tx.accounts[accountID] = acc
+	return nil
+}
+
+func (r *accountRepository) UpdateDetails(ctx context.Context, account *model.Account) error {
+	update := func(acc model.Account) (model.Account, error) {
+		if acc.Version != account.Version {
+			return acc, domain.ErrAccountVersionMismatch
+		}
+		acc.Name, acc.Owner, acc.Labels, acc.Status = account.Name, account.Owner, maps.Clone(account.Labels), account.Status
+		acc.Version, acc.UpdatedAt = acc.Version+1, time.Now()
+		account.Version, account.UpdatedAt = acc.Version, acc.UpdatedAt
+		return acc, nil
+	}
+	if r.tx == nil {
+		r.store.mu.Lock()
+		defer r.store.mu.Unlock()
+		acc, ok := r.store.accounts[account.AccountID]
+		if !ok {
+			return domain.ErrAccountVersionMismatch
+		}
+		acc, err := update(acc)
+		if err != nil {
+			return err
+		}
+		r.store.accounts[account.AccountID] = acc
+		return nil
+	}
+
+	acc, ok := r.lookup(account.AccountID)
+	if !ok {
+		return domain.ErrAccountVersionMismatch
+	}
+	acc, err := update(acc)
+	if err != nil {
+		return err
+	}
+	r.tx.accounts[account.AccountID] = acc
+	return nil
+}
+
+func (r *accountRepository) ListAccounts(ctx context.Context, filter repository.AccountFilter) ([]*model.Account, error) {
+	r.store.mu.RLock()
+	all := maps.Clone(r.store.accounts)
+	r.store.mu.RUnlock()
+	if r.tx != nil {
+		maps.Copy(all, r.tx.accounts)
+	}
+
+	compare, err := compareAccounts(filter.Sort, filter.Desc)
+	if err != nil {
+		return nil, err
+	}
+	var accounts []*model.Account
+	for _, acc := range all {
+		if matchesAccount(&acc, filter) && (filter.After == nil || compare(&acc, filter.After) > 0) {
+			accounts = append(accounts, &acc)
+		}
+	}
+	slices.SortFunc(accounts, func(a, b *model.Account) int { return compare(a, repository.CursorOf(b)) })
+	if filter.Limit > 0 && len(accounts) > filter.Limit {
+		accounts = accounts[:filter.Limit]
+	}
+	return accounts, nil
+}
+
+func matchesAccount(acc *model.Account, filter repository.AccountFilter) bool {
+	switch {
+	case len(filter.Types) > 0 && !slices.Contains(filter.Types, acc.Type),
+		len(filter.Statuses) > 0 && !slices.Contains(filter.Statuses, acc.Status),
+		filter.Owner != "" && acc.Owner != filter.Owner,
+		filter.MinBalance != nil && acc.Balance < *filter.MinBalance,
+		filter.MaxBalance != nil && acc.Balance > *filter.MaxBalance,
+		!filter.CreatedFrom.IsZero() && acc.CreatedAt.Before(filter.CreatedFrom),
+		!filter.CreatedTo.IsZero() && !acc.CreatedAt.Before(filter.CreatedTo):
+		return false
+	}
+	return true
+}
+
+// compareAccounts returns how an account orders against a listing position, ties broken by id like in Postgres
+func compareAccounts(sort repository.AccountSort, desc bool) (func(*model.Account, *repository.AccountCursor) int, error) {
+	var key func(*model.Account, *repository.AccountCursor) int
+	switch sort {
+	case "", repository.AccountSortID:
+		key = func(*model.Account, *repository.AccountCursor) int { return 0 }
+	case repository.AccountSortBalance:
+		key = func(a *model.Account, c *repository.AccountCursor) int { return cmp.Compare(a.Balance, c.Balance) }
+	case repository.AccountSortCreatedAt:
+		key = func(a *model.Account, c *repository.AccountCursor) int { return a.CreatedAt.Compare(c.CreatedAt) }
+	default:
+		return nil, fmt.Errorf("list accounts failed: unknown sort %q", sort)
+	}
+	return func(a *model.Account, c *repository.AccountCursor) int {
+		n := cmp.Or(key(a, c), cmp.Compare(a.AccountID, c.AccountID))
+		if desc {
+			return -n
+		}
+		return n
+	}, nil
+}
+
+// lookup returns the account as seen by this repository, including writes buffered in its unit of work
+func (r *accountRepository) lookup(accountID int64) (model.Account, bool) {
+	if r.tx != nil {
+		if acc, ok := r.tx.accounts[accountID]; ok {
+			return acc, true
+		}
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	acc, ok := r.store.accounts[accountID]
+	return acc, ok
+}
@@ -0,0 +1,335 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"internal-transfers/internal/domain"
+	"internal-transfers/internal/model"
+	"internal-transfers/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountRepository_CreateAccount(t *testing.T) {
+	ctx := context.Background()
+	repo := NewAccountRepository(NewStore())
+
+	t.Run("create account successfully", func(t *testing.T) {
+		// given
+		acc := &model.Account{AccountID: 1, Balance: 100}
+
+		// when
+		err := repo.CreateAccount(ctx, acc)
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), acc.Version)
+		assert.False(t, acc.CreatedAt.IsZero())
+	})
+
+	t.Run("create account fail due to duplicate", func(t *testing.T) {
+		// when
+		err := repo.CreateAccount(ctx, &model.Account{AccountID: 1, Balance: 50})
+
+		// then
+		assert.ErrorIs(t, err, domain.ErrAccountDuplicate)
+	})
+}
+
+func TestAccountRepository_NextAccountID(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore()
+	repo := NewAccountRepository(store)
+
+	first, err := repo.NextAccountID(ctx)
+	require.NoError(t, err)
+	// ids drawn inside a unit of work are not handed out again after a rollback
+	_ = NewUnitOfWork(store).WithinTx(ctx, func(ctx context.Context, repos repository.Repositories) error {
+		_, err := repos.Accounts.NextAccountID(ctx)
+		require.NoError(t, err)
+		return assert.AnError
+	})
+	third, err := repo.NextAccountID(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, model.AllocatedAccountID(model.FirstAccountSequence), first)
+	assert.Equal(t, model.AllocatedAccountID(model.FirstAccountSequence+2), third)
+}
+
+func TestAccountRepository_GetAccount(t *testing.T) {
+	ctx := context.Background()
+	repo := NewAccountRepository(NewStore())
+	createdAt := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, repo.CreateAccount(ctx, &model.Account{AccountID: 1, Balance: 100, CreatedAt: createdAt}))
+
+	t.Run("get account successfully", func(t *testing.T) {
+		// when
+		acc, err := repo.GetAccount(ctx, 1)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, &model.Account{
+			AccountID: 1,
+			Balance:   100,
+			Type:      model.AccountCustomer,
+			Status:    model.AccountOpen,
+			Version:   1,
+			CreatedAt: createdAt,
+			UpdatedAt: createdAt,
+		}, acc)
+	})
+
+	t.Run("account not found", func(t *testing.T) {
+		// when
+		acc, err := repo.GetAccount(ctx, 2)
+
+		// then
+		assert.Nil(t, acc)
+		assert.ErrorIs(t, err, domain.ErrAccountNotFound)
+	})
+}
+
+func TestAccountRepository_UpdateBalance(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("visible only after commit", func(t *testing.T) {
+		// given
+		store := NewStore()
+		repo := NewAccountRepository(store)
+		require.NoError(t, repo.CreateAccount(ctx, &model.Account{AccountID: 1, Balance: 100}))
+
+		// when
+		var inside, before *model.Account
+		err := NewUnitOfWork(store).WithinTx(ctx, func(ctx context.Context, repos repository.Repositories) error {
+			require.NoError(t, repos.Accounts.UpdateBalance(ctx, 1, 40))
+			inside, _ = repos.Accounts.GetAccount(ctx, 1)
+			before, _ = repo.GetAccount(ctx, 1)
+			return nil
+		})
+		after, _ := repo.GetAccount(ctx, 1)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 40.0, inside.Balance)
+		assert.Equal(t, 100.0, before.Balance)
+		assert.Equal(t, 40.0, after.Balance)
+	})
+
+	t.Run("discarded on error", func(t *testing.T) {
+		// given
+		store := NewStore()
+		repo := NewAccountRepository(store)
+		require.NoError(t, repo.CreateAccount(ctx, &model.Account{AccountID: 1, Balance: 100}))
+
+		// when
+		err := NewUnitOfWork(store).WithinTx(ctx, func(ctx context.Context, repos repository.Repositories) error {
+			require.NoError(t, repos.Accounts.UpdateBalance(ctx, 1, 40))
+			return assert.AnError
+		})
+
+		// then
+		assert.ErrorIs(t, err, assert.AnError)
+		acc, _ := repo.GetAccount(ctx, 1)
+		assert.Equal(t, 100.0, acc.Balance)
+	})
+
+	t.Run("discarded on panic", func(t *testing.T) {
+		// given
+		store := NewStore()
+		repo := NewAccountRepository(store)
+		require.NoError(t, repo.CreateAccount(ctx, &model.Account{AccountID: 1, Balance: 100}))
+		uow := NewUnitOfWork(store)
+
+		// when
+		assert.Panics(t, func() {
+			_ = uow.WithinTx(ctx, func(ctx context.Context, repos repository.Repositories) error {
+				require.NoError(t, repos.Accounts.UpdateBalance(ctx, 1, 40))
+				panic("boom")
+			})
+		})
+
+		// then
+		acc, _ := repo.GetAccount(ctx, 1)
+		assert.Equal(t, 100.0, acc.Balance)
+		assert.NoError(t, uow.WithinTx(ctx, func(ctx context.Context, repos repository.Repositories) error { return nil }))
+	})
+
+	t.Run("duplicate create within unit of work", func(t *testing.T) {
+		// given
+		store := NewStore()
+
+		// when
+		err := NewUnitOfWork(store).WithinTx(ctx, func(ctx context.Context, repos repository.Repositories) error {
+			require.NoError(t, repos.Accounts.CreateAccount(ctx, &model.Account{AccountID: 1, Balance: 100}))
+			return repos.Accounts.CreateAccount(ctx, &model.Account{AccountID: 1, Balance: 100})
+		})
+
+		// then
+		assert.ErrorIs(t, err, domain.ErrAccountDuplicate)
+		_, err = NewAccountRepository(store).GetAccount(ctx, 1)
+		assert.ErrorIs(t, err, domain.ErrAccountNotFound)
+	})
+}
+
+func TestAccountRepository_ListAccounts(t *testing.T) {
+	// given
+	ctx := context.Background()
+	repo := NewAccountRepository(NewStore())
+	for _, acc := range []*model.Account{
+		{AccountID: 3, Type: model.AccountRevenue},
+		{AccountID: 1, Balance: 10},
+		{AccountID: 2, Type: model.AccountExpense},
+	} {
+		require.NoError(t, repo.CreateAccount(ctx, acc))
+	}
+	ids := func(filter repository.AccountFilter) []int64 {
+		accounts, err := repo.ListAccounts(ctx, filter)
+		require.NoError(t, err)
+		var ids []int64
+		for _, acc := range accounts {
+			ids = append(ids, acc.AccountID)
+		}
+		return ids
+	}
+
+	// then
+	assert.Equal(t, []int64{1, 2, 3}, ids(repository.AccountFilter{}))
+	assert.Equal(t, []int64{1, 2}, ids(repository.AccountFilter{Limit: 2}))
+	assert.Equal(t, []int64{2, 3}, ids(repository.AccountFilter{Types: []model.AccountType{model.AccountRevenue, model.AccountExpense}}))
+
+	// when
+	require.NoError(t, repo.UpdateType(ctx, 1, model.AccountSuspense))
+
+	// then
+	assert.Equal(t, []int64{1}, ids(repository.AccountFilter{Types: []model.AccountType{model.AccountSuspense}}))
+
+	// when
+	acc, err := repo.GetAccount(ctx, 2)
+	require.NoError(t, err)
+	acc.Status = model.AccountClosed
+	require.NoError(t, repo.UpdateDetails(ctx, acc))
+
+	// then
+	assert.Equal(t, []int64{2}, ids(repository.AccountFilter{Statuses: []model.AccountStatus{model.AccountClosed}}))
+	assert.Equal(t, []int64{1, 3}, ids(repository.AccountFilter{Statuses: []model.AccountStatus{model.AccountOpen}}))
+	assert.ErrorIs(t, repo.UpdateType(ctx, 4, model.AccountSuspense), domain.ErrAccountNotFound)
+}
+
+func TestAccountRepository_ListAccounts_SortedPages(t *testing.T) {
+	// given
+	ctx := context.Background()
+	repo := NewAccountRepository(NewStore())
+	day := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	for _, acc := range []*model.Account{
+		{AccountID: 1, Balance: 30, CreatedAt: day.Add(3 * time.Hour)},
+		{AccountID: 2, Balance: 10, CreatedAt: day.Add(2 * time.Hour)},
+		{AccountID: 3, Balance: 30, CreatedAt: day.Add(time.Hour)},
+		{AccountID: 4, Balance: 20, CreatedAt: day.AddDate(0, 0, 1)},
+	} {
+		require.NoError(t, repo.CreateAccount(ctx, acc))
+	}
+	pages := func(filter repository.AccountFilter) [][]int64 {
+		var pages [][]int64
+		for {
+			accounts, err := repo.ListAccounts(ctx, filter)
+			require.NoError(t, err)
+			if len(accounts) == 0 {
+				return pages
+			}
+			var ids []int64
+			for _, acc := range accounts {
+				ids = append(ids, acc.AccountID)
+			}
+			pages = append(pages, ids)
+			filter.After = repository.CursorOf(accounts[len(accounts)-1])
+		}
+	}
+	minBalance, maxBalance := 15.0, 30.0
+
+	// then
+	assert.Equal(t, [][]int64{{1, 2}, {3, 4}}, pages(repository.AccountFilter{Limit: 2}))
+	assert.Equal(t, [][]int64{{4, 3}, {2, 1}}, pages(repository.AccountFilter{Desc: true, Limit: 2}))
+	assert.Equal(t, [][]int64{{3, 1, 4}, {2}}, pages(repository.AccountFilter{Sort: repository.AccountSortBalance, Desc: true, Limit: 3}))
+	assert.Equal(t, [][]int64{{3, 2}, {1, 4}}, pages(repository.AccountFilter{Sort: repository.AccountSortCreatedAt, Limit: 2}))
+	assert.Equal(t, [][]int64{{4, 1}, {3}}, pages(repository.AccountFilter{
+		MinBalance: &minBalance,
+		MaxBalance: &maxBalance,
+		Sort:       repository.AccountSortBalance,
+		Limit:      2,
+	}))
+	assert.Equal(t, [][]int64{{2, 1}}, pages(repository.AccountFilter{
+		CreatedFrom: day.Add(2 * time.Hour),
+		CreatedTo:   day.AddDate(0, 0, 1),
+		Sort:        repository.AccountSortCreatedAt,
+	}))
+
+	// when
+	_, err := repo.ListAccounts(ctx, repository.AccountFilter{Sort: "owner"})
+
+	// then
+	assert.ErrorContains(t, err, "unknown sort")
+}
+
+func TestAccountRepository_UpdateDetails(t *testing.T) {
+	// given
+	ctx := context.Background()
+	store := NewStore()
+	repo := NewAccountRepository(store)
+	labels := map[string]string{"tier": "gold"}
+	require.NoError(t, repo.CreateAccount(ctx, &model.Account{AccountID: 1, Owner: "cust-1", Labels: labels}))
+
+	t.Run("advances the version", func(t *testing.T) {
+		// given
+		acc, err := repo.GetAccount(ctx, 1)
+		require.NoError(t, err)
+		acc.Name = "Savings"
+		acc.Labels = map[string]string{"tier": "silver"}
+
+		// when
+		err = repo.UpdateDetails(ctx, acc)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), acc.Version)
+		stored, _ := repo.GetAccount(ctx, 1)
+		assert.Equal(t, "Savings", stored.Name)
+		assert.Equal(t, "cust-1", stored.Owner)
+		assert.Equal(t, map[string]string{"tier": "silver"}, stored.Labels)
+		assert.Equal(t, int64(2), stored.Version)
+		assert.Equal(t, map[string]string{"tier": "gold"}, labels, "the caller's labels are not aliased")
+	})
+
+	t.Run("rejects a stale version", func(t *testing.T) {
+		err := repo.UpdateDetails(ctx, &model.Account{AccountID: 1, Name: "Stale", Version: 1})
+		assert.ErrorIs(t, err, domain.ErrAccountVersionMismatch)
+
+		err = repo.UpdateDetails(ctx, &model.Account{AccountID: 2, Version: 1})
+		assert.ErrorIs(t, err, domain.ErrAccountVersionMismatch)
+	})
+
+	t.Run("type changes advance the version", func(t *testing.T) {
+		require.NoError(t, repo.UpdateType(ctx, 1, model.AccountSuspense))
+
+		acc, _ := repo.GetAccount(ctx, 1)
+		assert.Equal(t, int64(3), acc.Version)
+		assert.Equal(t, "Savings", acc.Name)
+	})
+
+	t.Run("buffers updates within a unit of work", func(t *testing.T) {
+		err := NewUnitOfWork(store).WithinTx(ctx, func(ctx context.Context, repos repository.Repositories) error {
+			acc, err := repos.Accounts.GetAccountForUpdate(ctx, 1)
+			require.NoError(t, err)
+			acc.Owner = "cust-2"
+			require.NoError(t, repos.Accounts.UpdateDetails(ctx, acc))
+			return assert.AnError
+		})
+		require.ErrorIs(t, err, assert.AnError)
+
+		acc, _ := repo.GetAccount(ctx, 1)
+		assert.Equal(t, "cust-1", acc.Owner)
+		assert.Equal(t, int64(3), acc.Version)
+	})
+}
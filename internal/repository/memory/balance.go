@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"internal-transfers/internal/domain"
+	"internal-transfers/internal/repository"
+)
+
+type balanceRepository struct {
+	store *Store
+	// tx is nil outside of a unit of work
+	tx *tx
+}
+
+func NewBalanceRepository(store *Store) repository.BalanceRepository {
+	return &balanceRepository{store: store}
+}
+
+// BalanceAt rolls back from the current balance; history is small enough in memory that snapshots are not needed
+func (r *balanceRepository) BalanceAt(ctx context.Context, accountID int64, at time.Time) (float64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	acc, ok := r.store.accounts[accountID]
+	if !ok {
+		return 0, domain.ErrAccountNotFound
+	}
+	return acc.Balance - r.store.netSince(accountID, at), nil
+}
+
+func (r *balanceRepository) CreateSnapshots(ctx context.Context, at time.Time) (int64, error) {
+	var n int64
+	r.store.update(r.tx, func(d *derived) {
+		n = 0
+		for id, acc := range r.store.accounts {
+			key := snapshotKey{accountID: id, asOf: at.UnixNano()}
+			if _, ok := d.snapshots[key]; ok {
+				continue
+			}
+			d.snapshots[key] = acc.Balance - r.store.netSince(id, at)
+			n++
+		}
+	})
+	return n, nil
+}
+
+func (r *balanceRepository) DeleteSnapshotsAfter(ctx context.Context, accountIDs []int64, at time.Time) error {
+	r.store.update(r.tx, func(d *derived) {
+		for key := range d.snapshots {
+			if key.asOf > at.UnixNano() && slices.Contains(accountIDs, key.accountID) {
+				delete(d.snapshots, key)
+			}
+		}
+	})
+	return nil
+}
+
+// netSince sums what accountID received less what it sent in transactions created at or after t; callers
+// must hold s.mu
+func (s *Store) netSince(accountID int64, t time.Time) float64 {
+	var net float64
+	for _, tx := range s.transactions {
+		if tx.CreatedAt.Before(t) {
+			continue
+		}
+		if tx.DestinationAccountID == accountID {
+			net += tx.Amount
+		}
+		if tx.SourceAccountID == accountID {
+			net -= tx.Amount
+		}
+	}
+	return net
+}
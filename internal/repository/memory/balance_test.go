@@ -0,0 +1,52 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"internal-transfers/internal/domain"
+	"internal-transfers/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBalanceRepository(t *testing.T) {
+	// given
+	ctx := context.Background()
+	store := NewStore()
+	repo := NewBalanceRepository(store)
+	day := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	require.NoError(t, NewAccountRepository(store).CreateAccount(ctx, &model.Account{AccountID: 1, Balance: 70}))
+	transactions := NewTransactionRepository(store)
+	require.NoError(t, transactions.CreateTransaction(ctx, &model.Transaction{SourceAccountID: 2, DestinationAccountID: 1, Amount: 50, CreatedAt: day}))
+	require.NoError(t, transactions.CreateTransaction(ctx, &model.Transaction{SourceAccountID: 1, DestinationAccountID: 2, Amount: 30, CreatedAt: day.Add(time.Hour)}))
+
+	t.Run("balance at", func(t *testing.T) {
+		for at, want := range map[time.Time]float64{
+			day:                      50,
+			day.Add(time.Minute):     100,
+			day.Add(2 * time.Hour):   70,
+			day.Add(-24 * time.Hour): 50,
+		} {
+			got, err := repo.BalanceAt(ctx, 1, at)
+			require.NoError(t, err)
+			assert.Equal(t, want, got, at)
+		}
+
+		_, err := repo.BalanceAt(ctx, 9, day)
+		assert.ErrorIs(t, err, domain.ErrAccountNotFound)
+	})
+
+	t.Run("snapshots are written once", func(t *testing.T) {
+		n, err := repo.CreateSnapshots(ctx, day.Add(time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), n)
+
+		n, err = repo.CreateSnapshots(ctx, day.Add(time.Minute))
+		require.NoError(t, err)
+		assert.Zero(t, n)
+	})
+}
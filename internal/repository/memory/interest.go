@@ -0,0 +1,189 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"internal-transfers/internal/domain"
+	"internal-transfers/internal/model"
+	"internal-transfers/internal/repository"
+)
+
+type postingKey struct {
+	accountID   int64
+	periodStart int64 // unix nanoseconds, as time.Time is not comparable across locations
+}
+
+type posting struct {
+	periodEnd time.Time
+	amount    float64
+	posted    bool
+	claimedAt time.Time
+}
+
+// coverage is the range of days accrued for a period and how many of them are
+type coverage struct {
+	first, last time.Time
+	days        int
+}
+
+func (c *coverage) add(day time.Time) {
+	if day.Before(c.first) {
+		c.first = day
+	}
+	if day.After(c.last) {
+		c.last = day
+	}
+	c.days++
+}
+
+type interestRepository struct {
+	store *Store
+	// tx is nil outside of a unit of work
+	tx *tx
+}
+
+func NewInterestRepository(store *Store) repository.InterestRepository {
+	return &interestRepository{store: store}
+}
+
+func (r *interestRepository) RecordAccrual(ctx context.Context, accrual *model.InterestAccrual) error {
+	recorded := *accrual
+	r.store.update(r.tx, func(d *derived) {
+		for i, a := range d.accruals {
+			if a.AccountID == recorded.AccountID && a.Date.Equal(recorded.Date) {
+				if a.Stale {
+					d.accruals[i] = recorded
+				}
+				return
+			}
+		}
+		d.accruals = append(d.accruals, recorded)
+	})
+	return nil
+}
+
+func (r *interestRepository) LastAccrualDate(ctx context.Context, accountID int64) (time.Time, error) {
+	var last, firstStale time.Time
+	r.store.view(r.tx, func(d *derived) {
+		for _, a := range d.accruals {
+			if a.AccountID != accountID {
+				continue
+			}
+			if a.Date.After(last) {
+				last = a.Date
+			}
+			if a.Stale && (firstStale.IsZero() || a.Date.Before(firstStale)) {
+				firstStale = a.Date
+			}
+		}
+	})
+	if !firstStale.IsZero() {
+		return firstStale.AddDate(0, 0, -1), nil
+	}
+	return last, nil
+}
+
+func (r *interestRepository) AccruedInterest(ctx context.Context, accountID int64, from, to time.Time) (float64, error) {
+	var accrued float64
+	r.store.view(r.tx, func(d *derived) {
+		for _, a := range d.accruals {
+			if a.AccountID == accountID && !a.Date.Before(from) && a.Date.Before(to) {
+				accrued += a.Amount
+			}
+		}
+	})
+	return accrued, nil
+}
+
+func (r *interestRepository) PendingPostings(ctx context.Context, end time.Time) ([]model.InterestPosting, error) {
+	var postings []model.InterestPosting
+	r.store.view(r.tx, func(d *derived) {
+		stale := make(map[postingKey]bool)
+		for _, a := range d.accruals {
+			if a.Stale {
+				stale[postingKey{accountID: a.AccountID, periodStart: a.PeriodStart.UnixNano()}] = true
+			}
+		}
+
+		index := make(map[postingKey]int)
+		var covered []coverage
+		for _, a := range d.accruals {
+			key := postingKey{accountID: a.AccountID, periodStart: a.PeriodStart.UnixNano()}
+			if a.PeriodEnd.After(end) || stale[key] || (d.postings[key] != nil && d.postings[key].posted) {
+				continue
+			}
+			i, ok := index[key]
+			if !ok {
+				i = len(postings)
+				index[key] = i
+				postings = append(postings, model.InterestPosting{
+					AccountID: a.AccountID, Product: a.Product, PeriodStart: a.PeriodStart, PeriodEnd: a.PeriodEnd,
+				})
+				covered = append(covered, coverage{first: a.Date, last: a.Date})
+			}
+			postings[i].Amount += a.Amount
+			covered[i].add(a.Date)
+		}
+		// a period is only paid once every day from its first accrual through its last day is accrued
+		postings = slices.DeleteFunc(postings, func(p model.InterestPosting) bool {
+			c := covered[index[postingKey{accountID: p.AccountID, periodStart: p.PeriodStart.UnixNano()}]]
+			return !c.last.Equal(p.PeriodEnd.AddDate(0, 0, -1)) || c.days != int(c.last.Sub(c.first).Hours()/24)+1
+		})
+	})
+	slices.SortFunc(postings, func(a, b model.InterestPosting) int {
+		if c := a.PeriodStart.Compare(b.PeriodStart); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.AccountID, b.AccountID)
+	})
+	return postings, nil
+}
+
+func (r *interestRepository) ClaimPosting(ctx context.Context, p model.InterestPosting, now, staleBefore time.Time) (bool, error) {
+	key := postingKey{accountID: p.AccountID, periodStart: p.PeriodStart.UnixNano()}
+	var claimed bool
+	r.store.update(r.tx, func(d *derived) {
+		existing := d.postings[key]
+		if claimed = existing == nil || (!existing.posted && existing.claimedAt.Before(staleBefore)); claimed {
+			d.postings[key] = &posting{periodEnd: p.PeriodEnd, amount: p.Amount, claimedAt: now}
+		}
+	})
+	return claimed, nil
+}
+
+func (r *interestRepository) CompletePosting(ctx context.Context, accountID int64, periodStart, postedAt time.Time) error {
+	r.store.update(r.tx, func(d *derived) {
+		if p := d.postings[postingKey{accountID: accountID, periodStart: periodStart.UnixNano()}]; p != nil {
+			p.posted = true
+		}
+	})
+	return nil
+}
+
+func (r *interestRepository) ReopenAccruals(ctx context.Context, accountIDs []int64, from time.Time) error {
+	var err error
+	r.store.view(r.tx, func(d *derived) {
+		for key, p := range d.postings {
+			if slices.Contains(accountIDs, key.accountID) && p.periodEnd.After(from) {
+				err = fmt.Errorf("%w: interest of account %d is paid through %s", domain.ErrPeriodClosed, key.accountID,
+					p.periodEnd.AddDate(0, 0, -1).Format(time.DateOnly))
+				return
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+	r.store.update(r.tx, func(d *derived) {
+		for i, a := range d.accruals {
+			if slices.Contains(accountIDs, a.AccountID) && !a.Date.Before(from) {
+				d.accruals[i].Stale = true
+			}
+		}
+	})
+	return nil
+}
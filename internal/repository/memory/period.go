@@ -0,0 +1,128 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"math"
+	"slices"
+	"time"
+
+	"internal-transfers/internal/domain"
+	"internal-transfers/internal/model"
+	"internal-transfers/internal/repository"
+)
+
+// periodRepository needs no locks, as units of work are already serialized by the store
+type periodRepository struct {
+	store *Store
+	// tx is nil outside of a unit of work
+	tx *tx
+}
+
+func NewPeriodRepository(store *Store) repository.PeriodRepository {
+	return &periodRepository{store: store}
+}
+
+func (r *periodRepository) LockShared(ctx context.Context) error {
+	return nil
+}
+
+func (r *periodRepository) LockExclusive(ctx context.Context) error {
+	return nil
+}
+
+func (r *periodRepository) LastClosed(ctx context.Context) (time.Time, error) {
+	var last time.Time
+	for _, p := range r.periods() {
+		if p.Date.After(last) {
+			last = p.Date
+		}
+	}
+	return last, nil
+}
+
+func (r *periodRepository) ClosePeriod(ctx context.Context, period *model.Period) error {
+	if slices.ContainsFunc(r.periods(), func(p model.Period) bool { return p.Date.Equal(period.Date) }) {
+		return domain.ErrPeriodClosed
+	}
+	closed := *period
+	closed.Closed = true
+	if r.tx != nil {
+		r.tx.periods = append(r.tx.periods, closed)
+		return nil
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	r.store.periods = append(r.store.periods, closed)
+	return nil
+}
+
+func (r *periodRepository) ListPeriods(ctx context.Context, from, to time.Time) ([]*model.Period, error) {
+	var periods []*model.Period
+	for _, p := range r.periods() {
+		if !p.Date.Before(from) && !p.Date.After(to) {
+			periods = append(periods, &p)
+		}
+	}
+	slices.SortFunc(periods, func(a, b *model.Period) int { return a.Date.Compare(b.Date) })
+	return periods, nil
+}
+
+func (r *periodRepository) TrialBalance(ctx context.Context, at time.Time) (*model.TrialBalance, error) {
+	lines := make(map[model.AccountType]*model.TrialBalanceLine)
+	var opening model.TrialBalanceLine
+	r.store.view(r.tx, func(d *derived) {
+		for key, balance := range d.snapshots {
+			if key.asOf != at.UnixNano() {
+				continue
+			}
+			switch initial := r.store.initialBalances[key.accountID]; {
+			case initial > 0:
+				opening.Accounts++
+				opening.Debit += initial
+			case initial < 0:
+				opening.Accounts++
+				opening.Credit -= initial
+			}
+			accountType := r.store.accounts[key.accountID].Type
+			line := lines[accountType]
+			if line == nil {
+				line = &model.TrialBalanceLine{AccountType: accountType}
+				lines[accountType] = line
+			}
+			line.Accounts++
+			if balance < 0 {
+				line.Debit -= balance
+			} else {
+				line.Credit += balance
+			}
+		}
+	})
+
+	tb := &model.TrialBalance{}
+	for _, line := range lines {
+		line.Debit, line.Credit = math.Round(line.Debit*100)/100, math.Round(line.Credit*100)/100
+		tb.Lines = append(tb.Lines, *line)
+		tb.Debits += line.Debit
+		tb.Credits += line.Credit
+	}
+	slices.SortFunc(tb.Lines, func(a, b model.TrialBalanceLine) int { return cmp.Compare(a.AccountType, b.AccountType) })
+	opening.Debit, opening.Credit = math.Round(opening.Debit*100)/100, math.Round(opening.Credit*100)/100
+	tb.OpeningBalances = opening
+	tb.Debits += opening.Debit
+	tb.Credits += opening.Credit
+	tb.Debits, tb.Credits = math.Round(tb.Debits*100)/100, math.Round(tb.Credits*100)/100
+	return tb, nil
+}
+
+// periods returns the committed periods followed by those closed in the unit of work
+func (r *periodRepository) periods() []model.Period {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	periods := slices.Clone(r.store.periods)
+	if r.tx != nil {
+		periods = append(periods, r.tx.periods...)
+	}
+	return periods
+}
@@ -0,0 +1,62 @@
+package memory
+
+import (
+	"context"
+	"math"
+	"slices"
+	"sort"
+
+	"internal-transfers/internal/model"
+	"internal-transfers/internal/repository"
+)
+
+type reconciliationRepository struct {
+	store *Store
+}
+
+func NewReconciliationRepository(store *Store) repository.ReconciliationRepository {
+	return &reconciliationRepository{store: store}
+}
+
+// FindDiscrepancies compares in cents, as the balances are NUMERIC(20,2) in Postgres. Every account in memory
+// records its initial balance, so none is unverifiable.
+func (r *reconciliationRepository) FindDiscrepancies(ctx context.Context) (*model.BalanceCheck, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	net := make(map[int64]float64, len(r.store.accounts))
+	for _, tx := range r.store.transactions {
+		net[tx.DestinationAccountID] += tx.Amount
+		net[tx.SourceAccountID] -= tx.Amount
+	}
+
+	check := &model.BalanceCheck{Checked: int64(len(r.store.accounts))}
+	for id, acc := range r.store.accounts {
+		expected := math.Round((r.store.initialBalances[id]+net[id])*100) / 100
+		if math.Round(acc.Balance*100) != math.Round(expected*100) {
+			check.Discrepancies = append(check.Discrepancies, model.BalanceDiscrepancy{AccountID: id, Balance: acc.Balance, ExpectedBalance: expected})
+		}
+	}
+	sort.Slice(check.Discrepancies, func(i, j int) bool { return check.Discrepancies[i].AccountID < check.Discrepancies[j].AccountID })
+	return check, nil
+}
+
+func (r *reconciliationRepository) Supply(ctx context.Context, mintAccountID int64, settlementAccountIDs []int64) (*model.Supply, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var supply model.Supply
+	for id, acc := range r.store.accounts {
+		if id == mintAccountID {
+			supply.Minted = -acc.Balance
+			continue
+		}
+		if slices.Contains(settlementAccountIDs, id) {
+			supply.Settled -= acc.Balance
+			continue
+		}
+		supply.Accounts++
+		supply.Circulating += acc.Balance
+	}
+	return &supply, nil
+}
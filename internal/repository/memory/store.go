@@ -0,0 +1,152 @@
+// Package memory provides in-process implementations of the repositories, with the same semantics as the
+// Postgres ones, for tests and for running the service locally without a database
+package memory
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+
+	"internal-transfers/internal/model"
+	"internal-transfers/internal/repository"
+)
+
+// Store holds the data shared by the in-memory repositories
+type Store struct {
+	// txMu serializes units of work, giving them the equivalent of serializable isolation
+	txMu sync.Mutex
+
+	mu                sync.RWMutex
+	accounts          map[int64]model.Account
+	initialBalances   map[int64]float64
+	nextAccountSeq    int64
+	transactions      []model.Transaction
+	nextTransactionID int64
+	nextGroupID       int64
+	derived
+	periods []model.Period
+}
+
+// derived holds the balance snapshots and the interest bookkeeping. A unit of work changes a copy of it and
+// replays its changes onto the store when it commits, as the interest jobs also write to it outside of one.
+type derived struct {
+	snapshots map[snapshotKey]float64
+	accruals  []model.InterestAccrual
+	postings  map[postingKey]*posting
+}
+
+func (d *derived) clone() *derived {
+	postings := make(map[postingKey]*posting, len(d.postings))
+	for key, p := range d.postings {
+		copied := *p
+		postings[key] = &copied
+	}
+	return &derived{snapshots: maps.Clone(d.snapshots), accruals: slices.Clone(d.accruals), postings: postings}
+}
+
+type snapshotKey struct {
+	accountID int64
+	asOf      int64 // unix nanoseconds, as time.Time is not comparable across locations
+}
+
+func NewStore() *Store {
+	return &Store{
+		accounts:          make(map[int64]model.Account),
+		initialBalances:   make(map[int64]float64),
+		nextAccountSeq:    model.FirstAccountSequence,
+		nextTransactionID: 1,
+		nextGroupID:       1,
+		derived: derived{
+			snapshots: make(map[snapshotKey]float64),
+			postings:  make(map[postingKey]*posting),
+		},
+	}
+}
+
+// tx buffers the writes of a unit of work until it commits
+type tx struct {
+	accounts        map[int64]model.Account
+	initialBalances map[int64]float64
+	transactions    []model.Transaction
+	periods         []model.Period
+	// derived is the unit of work's copy of the store's, made on first use, and changes the writes made to it
+	derived *derived
+	changes []func(d *derived)
+}
+
+func (s *Store) commit(t *tx) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, acc := range t.accounts {
+		s.accounts[id] = acc
+	}
+	for id, balance := range t.initialBalances {
+		s.initialBalances[id] = balance
+	}
+	s.transactions = append(s.transactions, t.transactions...)
+	s.periods = append(s.periods, t.periods...)
+	for _, change := range t.changes {
+		change(&s.derived)
+	}
+}
+
+// view runs fn on the derived data as t sees it, or as committed when t is nil, holding the store's read lock
+func (s *Store) view(t *tx, fn func(d *derived)) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if t != nil && t.derived != nil {
+		fn(t.derived)
+		return
+	}
+	fn(&s.derived)
+}
+
+// update applies change to the derived data, or within a unit of work to its copy, keeping change to replay
+// on commit. change runs while the store is locked and may read the rest of it.
+func (s *Store) update(t *tx, change func(d *derived)) {
+	if t == nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		change(&s.derived)
+		return
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if t.derived == nil {
+		t.derived = s.derived.clone()
+	}
+	change(t.derived)
+	t.changes = append(t.changes, change)
+}
+
+type unitOfWork struct {
+	store *Store
+}
+
+func NewUnitOfWork(store *Store) repository.UnitOfWork {
+	return &unitOfWork{store: store}
+}
+
+// WithinTx runs fn while holding the store's unit of work lock; writes are discarded if fn fails or panics
+func (u *unitOfWork) WithinTx(ctx context.Context, fn func(ctx context.Context, repos repository.Repositories) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	u.store.txMu.Lock()
+	defer u.store.txMu.Unlock()
+
+	t := &tx{accounts: make(map[int64]model.Account), initialBalances: make(map[int64]float64)}
+	repos := repository.Repositories{
+		Accounts:     &accountRepository{store: u.store, tx: t},
+		Transactions: &transactionRepository{store: u.store, tx: t},
+		Balances:     &balanceRepository{store: u.store, tx: t},
+		Periods:      &periodRepository{store: u.store, tx: t},
+		Interest:     &interestRepository{store: u.store, tx: t},
+	}
+	if err := fn(ctx, repos); err != nil {
+		return err
+	}
+	u.store.commit(t)
+	return nil
+}
@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"internal-transfers/internal/model"
+	"internal-transfers/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnitOfWork_SnapshotsAndInterest(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	newStore := func(t *testing.T) *Store {
+		store := NewStore()
+		require.NoError(t, NewAccountRepository(store).CreateAccount(ctx, &model.Account{AccountID: 1, Balance: 70}))
+		require.NoError(t, NewInterestRepository(store).RecordAccrual(ctx, &model.InterestAccrual{
+			AccountID: 1, Date: day, PeriodStart: day, PeriodEnd: day.AddDate(0, 1, 0), Amount: 0.5,
+		}))
+		return store
+	}
+	write := func(ctx context.Context, repos repository.Repositories) {
+		_, err := repos.Balances.CreateSnapshots(ctx, day)
+		require.NoError(t, err)
+		require.NoError(t, repos.Interest.ReopenAccruals(ctx, []int64{1}, day))
+		require.NoError(t, repos.Interest.RecordAccrual(ctx, &model.InterestAccrual{
+			AccountID: 1, Date: day.AddDate(0, 0, 1), PeriodStart: day, PeriodEnd: day.AddDate(0, 1, 0), Amount: 0.5,
+		}))
+	}
+
+	t.Run("visible inside and applied on commit", func(t *testing.T) {
+		// given
+		store := newStore(t)
+		interest := NewInterestRepository(store)
+
+		// when
+		var inside, before time.Time
+		err := NewUnitOfWork(store).WithinTx(ctx, func(ctx context.Context, repos repository.Repositories) error {
+			write(ctx, repos)
+			inside, _ = repos.Interest.LastAccrualDate(ctx, 1)
+			before, _ = interest.LastAccrualDate(ctx, 1)
+			return nil
+		})
+		after, _ := interest.LastAccrualDate(ctx, 1)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, day.AddDate(0, 0, -1), inside)
+		assert.Equal(t, day, before)
+		assert.Equal(t, day.AddDate(0, 0, -1), after)
+		assert.Len(t, store.snapshots, 1)
+	})
+
+	t.Run("discarded on error", func(t *testing.T) {
+		// given
+		store := newStore(t)
+
+		// when
+		err := NewUnitOfWork(store).WithinTx(ctx, func(ctx context.Context, repos repository.Repositories) error {
+			write(ctx, repos)
+			return assert.AnError
+		})
+
+		// then
+		assert.ErrorIs(t, err, assert.AnError)
+		last, _ := NewInterestRepository(store).LastAccrualDate(ctx, 1)
+		assert.Equal(t, day, last)
+		assert.Empty(t, store.snapshots)
+		assert.Len(t, store.accruals, 1)
+	})
+}
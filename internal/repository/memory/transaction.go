@@ -0,0 +1,168 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sort"
+	"time"
+
+	"internal-transfers/internal/domain"
+	"internal-transfers/internal/model"
+	"internal-transfers/internal/repository"
+)
+
+type transactionRepository struct {
+	store *Store
+	tx    *tx // nil outside of a unit of work
+}
+
+func NewTransactionRepository(store *Store) repository.TransactionRepository {
+	return &transactionRepository{store: store}
+}
+
+// CreateTransaction assigns the next id; like a sequence, ids are not reused when a unit of work rolls back
+func (r *transactionRepository) CreateTransaction(ctx context.Context, transaction *model.Transaction) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if transaction.ExternalReference != "" {
+		posted := func(transactions []model.Transaction) bool {
+			for _, t := range transactions {
+				if t.ExternalSource == transaction.ExternalSource && t.ExternalReference == transaction.ExternalReference {
+					return true
+				}
+			}
+			return false
+		}
+		if posted(r.store.transactions) || r.tx != nil && posted(r.tx.transactions) {
+			return domain.ErrDuplicateReference
+		}
+	}
+
+	transaction.TransactionID = r.store.nextTransactionID
+	r.store.nextTransactionID++
+
+	stored := *transaction
+	stored.Metadata = maps.Clone(transaction.Metadata)
+	if r.tx != nil {
+		r.tx.transactions = append(r.tx.transactions, stored)
+	} else {
+		r.store.transactions = append(r.store.transactions, stored)
+	}
+	return nil
+}
+
+// CreateTransferGroup assigns the next group id, which like a transaction id is not reused on rollback
+func (r *transactionRepository) CreateTransferGroup(ctx context.Context, createdAt time.Time) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	groupID := r.store.nextGroupID
+	r.store.nextGroupID++
+	return groupID, nil
+}
+
+// GetTransaction returns nil without an error when the transaction does not exist
+func (r *transactionRepository) GetTransaction(ctx context.Context, transactionID int64) (*model.Transaction, error) {
+	for _, t := range r.all() {
+		if t.TransactionID == transactionID {
+			return &t, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *transactionRepository) ListTransactions(ctx context.Context, filter repository.TransactionFilter) ([]*model.Transaction, error) {
+	var transactions []*model.Transaction
+	for _, t := range r.all() {
+		if matches(&t, filter) {
+			transactions = append(transactions, &t)
+		}
+	}
+	sort.Slice(transactions, func(i, j int) bool { return transactions[i].TransactionID < transactions[j].TransactionID })
+	if filter.Limit > 0 && len(transactions) > filter.Limit {
+		transactions = transactions[:filter.Limit]
+	}
+	return transactions, nil
+}
+
+func matches(t *model.Transaction, filter repository.TransactionFilter) bool {
+	switch {
+	case filter.AccountID != 0 && t.SourceAccountID != filter.AccountID && t.DestinationAccountID != filter.AccountID,
+		filter.GroupID != 0 && t.GroupID != filter.GroupID,
+		len(filter.Types) > 0 && !slices.Contains(filter.Types, t.Type),
+		filter.ExternalReference != "" && t.ExternalReference != filter.ExternalReference,
+		!filter.From.IsZero() && t.CreatedAt.Before(filter.From),
+		!filter.To.IsZero() && !t.CreatedAt.Before(filter.To),
+		t.TransactionID <= filter.AfterID:
+		return false
+	}
+	for k, v := range filter.Metadata {
+		if got, ok := t.Metadata[k]; !ok || got != v {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *transactionRepository) StreamStatement(ctx context.Context, accountID int64, from, to time.Time,
+	opening func(balance float64) error, entry func(tx *model.Transaction) error) error {
+	// snapshot the balance and transactions together, as the Postgres implementation does in one statement
+	r.store.mu.RLock()
+	acc, ok := r.store.accounts[accountID]
+	transactions := append([]model.Transaction(nil), r.store.transactions...)
+	r.store.mu.RUnlock()
+	if r.tx != nil {
+		if staged, found := r.tx.accounts[accountID]; found {
+			acc, ok = staged, true
+		}
+		transactions = append(transactions, r.tx.transactions...)
+	}
+	if !ok {
+		return domain.ErrAccountNotFound
+	}
+
+	balance := acc.Balance
+	var period []model.Transaction
+	for _, t := range transactions {
+		if t.SourceAccountID != accountID && t.DestinationAccountID != accountID || t.CreatedAt.Before(from) {
+			continue
+		}
+		if t.DestinationAccountID == accountID {
+			balance -= t.Amount
+		} else {
+			balance += t.Amount
+		}
+		if t.CreatedAt.Before(to) {
+			period = append(period, t)
+		}
+	}
+	sort.SliceStable(period, func(i, j int) bool {
+		if !period[i].CreatedAt.Equal(period[j].CreatedAt) {
+			return period[i].CreatedAt.Before(period[j].CreatedAt)
+		}
+		return period[i].TransactionID < period[j].TransactionID
+	})
+
+	if err := opening(balance); err != nil {
+		return err
+	}
+	for _, t := range period {
+		if err := entry(&t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// all returns a snapshot of the committed transactions followed by those buffered in this repository's unit of work
+func (r *transactionRepository) all() []model.Transaction {
+	r.store.mu.RLock()
+	all := append([]model.Transaction(nil), r.store.transactions...)
+	r.store.mu.RUnlock()
+
+	if r.tx != nil {
+		all = append(all, r.tx.transactions...)
+	}
+	return all
+}
@@ -0,0 +1,150 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"internal-transfers/internal/domain"
+	"internal-transfers/internal/model"
+	"internal-transfers/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionRepository_CreateTransaction(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("assigns ids and persists on commit", func(t *testing.T) {
+		// given
+		store := NewStore()
+		repo := NewTransactionRepository(store)
+		transaction := &model.Transaction{SourceAccountID: 1, DestinationAccountID: 2, Amount: 10, CreatedAt: time.Now()}
+
+		// when
+		err := NewUnitOfWork(store).WithinTx(ctx, func(ctx context.Context, repos repository.Repositories) error {
+			return repos.Transactions.CreateTransaction(ctx, transaction)
+		})
+		require.NoError(t, err)
+
+		// then
+		assert.Equal(t, int64(1), transaction.TransactionID)
+		got, err := repo.GetTransaction(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, transaction, got)
+	})
+
+	t.Run("rejects a repeated external reference", func(t *testing.T) {
+		// given
+		store := NewStore()
+		repo := NewTransactionRepository(store)
+		deposit := func(source, ref string) *model.Transaction {
+			return &model.Transaction{SourceAccountID: 1, DestinationAccountID: 2, Amount: 10, Type: model.TransactionDeposit,
+				ExternalSource: source, ExternalReference: ref}
+		}
+		require.NoError(t, repo.CreateTransaction(ctx, deposit("bank", "ref-1")))
+
+		// when
+		err := NewUnitOfWork(store).WithinTx(ctx, func(ctx context.Context, repos repository.Repositories) error {
+			require.NoError(t, repos.Transactions.CreateTransaction(ctx, deposit("card", "ref-1")))
+			return repos.Transactions.CreateTransaction(ctx, deposit("bank", "ref-1"))
+		})
+
+		// then
+		assert.ErrorIs(t, err, domain.ErrDuplicateReference)
+	})
+
+	t.Run("discarded on error", func(t *testing.T) {
+		// given
+		store := NewStore()
+		repo := NewTransactionRepository(store)
+
+		// when
+		err := NewUnitOfWork(store).WithinTx(ctx, func(ctx context.Context, repos repository.Repositories) error {
+			require.NoError(t, repos.Transactions.CreateTransaction(ctx, &model.Transaction{SourceAccountID: 1, DestinationAccountID: 2, Amount: 10}))
+			return assert.AnError
+		})
+		require.ErrorIs(t, err, assert.AnError)
+
+		// then
+		got, err := repo.GetTransaction(ctx, 1)
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+		list, err := repo.ListTransactions(ctx, repository.TransactionFilter{})
+		assert.NoError(t, err)
+		assert.Empty(t, list)
+	})
+}
+
+func TestTransactionRepository_ListTransactions(t *testing.T) {
+	// given
+	ctx := context.Background()
+	repo := NewTransactionRepository(NewStore())
+	at := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, tx := range []*model.Transaction{
+		{SourceAccountID: 1, DestinationAccountID: 2, Amount: 10, Type: model.TransactionPayroll, CreatedAt: at,
+			Metadata: map[string]string{"run": "2025-01"}},
+		{SourceAccountID: 2, DestinationAccountID: 3, Amount: 5, Type: model.TransactionRefund, CreatedAt: at.Add(time.Hour),
+			ExternalReference: "inv-7"},
+		{SourceAccountID: 1, DestinationAccountID: 3, Amount: 1, Type: model.TransactionPayroll, CreatedAt: at.Add(2 * time.Hour),
+			Metadata: map[string]string{"run": "2025-02"}, GroupID: 7},
+	} {
+		require.NoError(t, repo.CreateTransaction(ctx, tx))
+	}
+	ids := func(filter repository.TransactionFilter) []int64 {
+		list, err := repo.ListTransactions(ctx, filter)
+		require.NoError(t, err)
+		var ids []int64
+		for _, tx := range list {
+			ids = append(ids, tx.TransactionID)
+		}
+		return ids
+	}
+
+	// then
+	assert.Equal(t, []int64{1, 2, 3}, ids(repository.TransactionFilter{}))
+	assert.Equal(t, []int64{1, 2}, ids(repository.TransactionFilter{AccountID: 2}))
+	assert.Equal(t, []int64{1, 3}, ids(repository.TransactionFilter{Types: []model.TransactionType{model.TransactionPayroll}}))
+	assert.Equal(t, []int64{2}, ids(repository.TransactionFilter{ExternalReference: "inv-7"}))
+	assert.Equal(t, []int64{3}, ids(repository.TransactionFilter{Metadata: map[string]string{"run": "2025-02"}}))
+	assert.Equal(t, []int64{2}, ids(repository.TransactionFilter{From: at.Add(time.Hour), To: at.Add(2 * time.Hour)}))
+	assert.Equal(t, []int64{2}, ids(repository.TransactionFilter{AfterID: 1, Limit: 1}))
+	assert.Equal(t, []int64{3}, ids(repository.TransactionFilter{GroupID: 7}))
+}
+
+func TestTransactionRepository_StreamStatement(t *testing.T) {
+	// given
+	ctx := context.Background()
+	store := NewStore()
+	accounts := NewAccountRepository(store)
+	repo := NewTransactionRepository(store)
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	require.NoError(t, accounts.CreateAccount(ctx, &model.Account{AccountID: 1, Balance: 60}))
+	for _, tx := range []*model.Transaction{
+		{SourceAccountID: 1, DestinationAccountID: 2, Amount: 10, CreatedAt: from.Add(-time.Hour)}, // before the period
+		{SourceAccountID: 2, DestinationAccountID: 1, Amount: 50, CreatedAt: from.Add(time.Hour)},
+		{SourceAccountID: 1, DestinationAccountID: 3, Amount: 20, CreatedAt: from.Add(2 * time.Hour)},
+		{SourceAccountID: 3, DestinationAccountID: 1, Amount: 5, CreatedAt: to}, // after the period
+	} {
+		require.NoError(t, repo.CreateTransaction(ctx, tx))
+	}
+
+	// when
+	var opening float64
+	var entries []int64
+	err := repo.StreamStatement(ctx, 1, from, to,
+		func(balance float64) error { opening = balance; return nil },
+		func(tx *model.Transaction) error { entries = append(entries, tx.TransactionID); return nil },
+	)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, 25.0, opening) // 60 now, less 5 received at to, less 50 received, plus 20 sent
+	assert.Equal(t, []int64{2, 3}, entries)
+
+	err = repo.StreamStatement(ctx, 9, from, to, func(float64) error { return nil }, func(*model.Transaction) error { return nil })
+	assert.ErrorIs(t, err, domain.ErrAccountNotFound)
+}
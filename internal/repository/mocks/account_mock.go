@@ -8,7 +8,7 @@ import (
 
 	mock "github.com/stretchr/testify/mock"
 
-	sql "database/sql"
+	repository "internal-transfers/internal/repository"
 )
 
 // AccountRepository is an autogenerated mock type for the AccountRepository type
@@ -71,6 +71,65 @@ func (_c *AccountRepository_CreateAccount_Call) RunAndReturn(run func(context.Co
 	return _c
 }
 
+// CreateAccounts provides a mock function with given fields: ctx, accounts
+func (_m *AccountRepository) CreateAccounts(ctx context.Context, accounts []*model.Account) ([]int64, error) {
+	ret := _m.Called(ctx, accounts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAccounts")
+	}
+
+	var r0 []int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*model.Account) ([]int64, error)); ok {
+		return rf(ctx, accounts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []*model.Account) []int64); ok {
+		r0 = rf(ctx, accounts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []*model.Account) error); ok {
+		r1 = rf(ctx, accounts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AccountRepository_CreateAccounts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateAccounts'
+type AccountRepository_CreateAccounts_Call struct {
+	*mock.Call
+}
+
+// CreateAccounts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accounts []*model.Account
+func (_e *AccountRepository_Expecter) CreateAccounts(ctx interface{}, accounts interface{}) *AccountRepository_CreateAccounts_Call {
+	return &AccountRepository_CreateAccounts_Call{Call: _e.mock.On("CreateAccounts", ctx, accounts)}
+}
+
+func (_c *AccountRepository_CreateAccounts_Call) Run(run func(ctx context.Context, accounts []*model.Account)) *AccountRepository_CreateAccounts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]*model.Account))
+	})
+	return _c
+}
+
+func (_c *AccountRepository_CreateAccounts_Call) Return(_a0 []int64, _a1 error) *AccountRepository_CreateAccounts_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AccountRepository_CreateAccounts_Call) RunAndReturn(run func(context.Context, []*model.Account) ([]int64, error)) *AccountRepository_CreateAccounts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetAccount provides a mock function with given fields: ctx, accountID
 func (_m *AccountRepository) GetAccount(ctx context.Context, accountID int64) (*model.Account, error) {
 	ret := _m.Called(ctx, accountID)
@@ -130,17 +189,191 @@ func (_c *AccountRepository_GetAccount_Call) RunAndReturn(run func(context.Conte
 	return _c
 }
 
-// UpdateBalance provides a mock function with given fields: ctx, tx, accountID, newBalance
-func (_m *AccountRepository) UpdateBalance(ctx context.Context, tx *sql.Tx, accountID int64, newBalance float64) error {
-	ret := _m.Called(ctx, tx, accountID, newBalance)
+// GetAccountForUpdate provides a mock function with given fields: ctx, accountID
+func (_m *AccountRepository) GetAccountForUpdate(ctx context.Context, accountID int64) (*model.Account, error) {
+	ret := _m.Called(ctx, accountID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAccountForUpdate")
+	}
+
+	var r0 *model.Account
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*model.Account, error)); ok {
+		return rf(ctx, accountID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *model.Account); ok {
+		r0 = rf(ctx, accountID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Account)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, accountID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AccountRepository_GetAccountForUpdate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAccountForUpdate'
+type AccountRepository_GetAccountForUpdate_Call struct {
+	*mock.Call
+}
+
+// GetAccountForUpdate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID int64
+func (_e *AccountRepository_Expecter) GetAccountForUpdate(ctx interface{}, accountID interface{}) *AccountRepository_GetAccountForUpdate_Call {
+	return &AccountRepository_GetAccountForUpdate_Call{Call: _e.mock.On("GetAccountForUpdate", ctx, accountID)}
+}
+
+func (_c *AccountRepository_GetAccountForUpdate_Call) Run(run func(ctx context.Context, accountID int64)) *AccountRepository_GetAccountForUpdate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *AccountRepository_GetAccountForUpdate_Call) Return(_a0 *model.Account, _a1 error) *AccountRepository_GetAccountForUpdate_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AccountRepository_GetAccountForUpdate_Call) RunAndReturn(run func(context.Context, int64) (*model.Account, error)) *AccountRepository_GetAccountForUpdate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListAccounts provides a mock function with given fields: ctx, filter
+func (_m *AccountRepository) ListAccounts(ctx context.Context, filter repository.AccountFilter) ([]*model.Account, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAccounts")
+	}
+
+	var r0 []*model.Account
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, repository.AccountFilter) ([]*model.Account, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, repository.AccountFilter) []*model.Account); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Account)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, repository.AccountFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AccountRepository_ListAccounts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAccounts'
+type AccountRepository_ListAccounts_Call struct {
+	*mock.Call
+}
+
+// ListAccounts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter repository.AccountFilter
+func (_e *AccountRepository_Expecter) ListAccounts(ctx interface{}, filter interface{}) *AccountRepository_ListAccounts_Call {
+	return &AccountRepository_ListAccounts_Call{Call: _e.mock.On("ListAccounts", ctx, filter)}
+}
+
+func (_c *AccountRepository_ListAccounts_Call) Run(run func(ctx context.Context, filter repository.AccountFilter)) *AccountRepository_ListAccounts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(repository.AccountFilter))
+	})
+	return _c
+}
+
+func (_c *AccountRepository_ListAccounts_Call) Return(_a0 []*model.Account, _a1 error) *AccountRepository_ListAccounts_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AccountRepository_ListAccounts_Call) RunAndReturn(run func(context.Context, repository.AccountFilter) ([]*model.Account, error)) *AccountRepository_ListAccounts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NextAccountID provides a mock function with given fields: ctx
+func (_m *AccountRepository) NextAccountID(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NextAccountID")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int64, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AccountRepository_NextAccountID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'NextAccountID'
+type AccountRepository_NextAccountID_Call struct {
+	*mock.Call
+}
+
+// NextAccountID is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *AccountRepository_Expecter) NextAccountID(ctx interface{}) *AccountRepository_NextAccountID_Call {
+	return &AccountRepository_NextAccountID_Call{Call: _e.mock.On("NextAccountID", ctx)}
+}
+
+func (_c *AccountRepository_NextAccountID_Call) Run(run func(ctx context.Context)) *AccountRepository_NextAccountID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *AccountRepository_NextAccountID_Call) Return(_a0 int64, _a1 error) *AccountRepository_NextAccountID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AccountRepository_NextAccountID_Call) RunAndReturn(run func(context.Context) (int64, error)) *AccountRepository_NextAccountID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateBalance provides a mock function with given fields: ctx, accountID, newBalance
+func (_m *AccountRepository) UpdateBalance(ctx context.Context, accountID int64, newBalance float64) error {
+	ret := _m.Called(ctx, accountID, newBalance)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateBalance")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, int64, float64) error); ok {
-		r0 = rf(ctx, tx, accountID, newBalance)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, float64) error); ok {
+		r0 = rf(ctx, accountID, newBalance)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -155,16 +388,15 @@ type AccountRepository_UpdateBalance_Call struct {
 
 // UpdateBalance is a helper method to define mock.On call
 //   - ctx context.Context
-//   - tx *sql.Tx
 //   - accountID int64
 //   - newBalance float64
-func (_e *AccountRepository_Expecter) UpdateBalance(ctx interface{}, tx interface{}, accountID interface{}, newBalance interface{}) *AccountRepository_UpdateBalance_Call {
-	return &AccountRepository_UpdateBalance_Call{Call: _e.mock.On("UpdateBalance", ctx, tx, accountID, newBalance)}
+func (_e *AccountRepository_Expecter) UpdateBalance(ctx interface{}, accountID interface{}, newBalance interface{}) *AccountRepository_UpdateBalance_Call {
+	return &AccountRepository_UpdateBalance_Call{Call: _e.mock.On("UpdateBalance", ctx, accountID, newBalance)}
 }
 
-func (_c *AccountRepository_UpdateBalance_Call) Run(run func(ctx context.Context, tx *sql.Tx, accountID int64, newBalance float64)) *AccountRepository_UpdateBalance_Call {
+func (_c *AccountRepository_UpdateBalance_Call) Run(run func(ctx context.Context, accountID int64, newBalance float64)) *AccountRepository_UpdateBalance_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(*sql.Tx), args[2].(int64), args[3].(float64))
+		run(args[0].(context.Context), args[1].(int64), args[2].(float64))
 	})
 	return _c
 }
@@ -174,7 +406,102 @@ func (_c *AccountRepository_UpdateBalance_Call) Return(_a0 error) *AccountReposi
 	return _c
 }
 
-func (_c *AccountRepository_UpdateBalance_Call) RunAndReturn(run func(context.Context, *sql.Tx, int64, float64) error) *AccountRepository_UpdateBalance_Call {
+func (_c *AccountRepository_UpdateBalance_Call) RunAndReturn(run func(context.Context, int64, float64) error) *AccountRepository_UpdateBalance_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateDetails provides a mock function with given fields: ctx, account
+func (_m *AccountRepository) UpdateDetails(ctx context.Context, account *model.Account) error {
+	ret := _m.Called(ctx, account)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateDetails")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Account) error); ok {
+		r0 = rf(ctx, account)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AccountRepository_UpdateDetails_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateDetails'
+type AccountRepository_UpdateDetails_Call struct {
+	*mock.Call
+}
+
+// UpdateDetails is a helper method to define mock.On call
+//   - ctx context.Context
+//   - account *model.Account
+func (_e *AccountRepository_Expecter) UpdateDetails(ctx interface{}, account interface{}) *AccountRepository_UpdateDetails_Call {
+	return &AccountRepository_UpdateDetails_Call{Call: _e.mock.On("UpdateDetails", ctx, account)}
+}
+
+func (_c *AccountRepository_UpdateDetails_Call) Run(run func(ctx context.Context, account *model.Account)) *AccountRepository_UpdateDetails_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.Account))
+	})
+	return _c
+}
+
+func (_c *AccountRepository_UpdateDetails_Call) Return(_a0 error) *AccountRepository_UpdateDetails_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *AccountRepository_UpdateDetails_Call) RunAndReturn(run func(context.Context, *model.Account) error) *AccountRepository_UpdateDetails_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateType provides a mock function with given fields: ctx, accountID, accountType
+func (_m *AccountRepository) UpdateType(ctx context.Context, accountID int64, accountType model.AccountType) error {
+	ret := _m.Called(ctx, accountID, accountType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateType")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, model.AccountType) error); ok {
+		r0 = rf(ctx, accountID, accountType)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AccountRepository_UpdateType_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateType'
+type AccountRepository_UpdateType_Call struct {
+	*mock.Call
+}
+
+// UpdateType is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID int64
+//   - accountType model.AccountType
+func (_e *AccountRepository_Expecter) UpdateType(ctx interface{}, accountID interface{}, accountType interface{}) *AccountRepository_UpdateType_Call {
+	return &AccountRepository_UpdateType_Call{Call: _e.mock.On("UpdateType", ctx, accountID, accountType)}
+}
+
+func (_c *AccountRepository_UpdateType_Call) Run(run func(ctx context.Context, accountID int64, accountType model.AccountType)) *AccountRepository_UpdateType_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(model.AccountType))
+	})
+	return _c
+}
+
+func (_c *AccountRepository_UpdateType_Call) Return(_a0 error) *AccountRepository_UpdateType_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *AccountRepository_UpdateType_Call) RunAndReturn(run func(context.Context, int64, model.AccountType) error) *AccountRepository_UpdateType_Call {
 	_c.Call.Return(run)
 	return _c
 }
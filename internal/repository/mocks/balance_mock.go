@@ -0,0 +1,201 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// BalanceRepository is an autogenerated mock type for the BalanceRepository type
+type BalanceRepository struct {
+	mock.Mock
+}
+
+type BalanceRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *BalanceRepository) EXPECT() *BalanceRepository_Expecter {
+	return &BalanceRepository_Expecter{mock: &_m.Mock}
+}
+
+// BalanceAt provides a mock function with given fields: ctx, accountID, at
+func (_m *BalanceRepository) BalanceAt(ctx context.Context, accountID int64, at time.Time) (float64, error) {
+	ret := _m.Called(ctx, accountID, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BalanceAt")
+	}
+
+	var r0 float64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) (float64, error)); ok {
+		return rf(ctx, accountID, at)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) float64); ok {
+		r0 = rf(ctx, accountID, at)
+	} else {
+		r0 = ret.Get(0).(float64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, time.Time) error); ok {
+		r1 = rf(ctx, accountID, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BalanceRepository_BalanceAt_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BalanceAt'
+type BalanceRepository_BalanceAt_Call struct {
+	*mock.Call
+}
+
+// BalanceAt is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID int64
+//   - at time.Time
+func (_e *BalanceRepository_Expecter) BalanceAt(ctx interface{}, accountID interface{}, at interface{}) *BalanceRepository_BalanceAt_Call {
+	return &BalanceRepository_BalanceAt_Call{Call: _e.mock.On("BalanceAt", ctx, accountID, at)}
+}
+
+func (_c *BalanceRepository_BalanceAt_Call) Run(run func(ctx context.Context, accountID int64, at time.Time)) *BalanceRepository_BalanceAt_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *BalanceRepository_BalanceAt_Call) Return(_a0 float64, _a1 error) *BalanceRepository_BalanceAt_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *BalanceRepository_BalanceAt_Call) RunAndReturn(run func(context.Context, int64, time.Time) (float64, error)) *BalanceRepository_BalanceAt_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateSnapshots provides a mock function with given fields: ctx, at
+func (_m *BalanceRepository) CreateSnapshots(ctx context.Context, at time.Time) (int64, error) {
+	ret := _m.Called(ctx, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateSnapshots")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, at)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, at)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BalanceRepository_CreateSnapshots_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateSnapshots'
+type BalanceRepository_CreateSnapshots_Call struct {
+	*mock.Call
+}
+
+// CreateSnapshots is a helper method to define mock.On call
+//   - ctx context.Context
+//   - at time.Time
+func (_e *BalanceRepository_Expecter) CreateSnapshots(ctx interface{}, at interface{}) *BalanceRepository_CreateSnapshots_Call {
+	return &BalanceRepository_CreateSnapshots_Call{Call: _e.mock.On("CreateSnapshots", ctx, at)}
+}
+
+func (_c *BalanceRepository_CreateSnapshots_Call) Run(run func(ctx context.Context, at time.Time)) *BalanceRepository_CreateSnapshots_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *BalanceRepository_CreateSnapshots_Call) Return(_a0 int64, _a1 error) *BalanceRepository_CreateSnapshots_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *BalanceRepository_CreateSnapshots_Call) RunAndReturn(run func(context.Context, time.Time) (int64, error)) *BalanceRepository_CreateSnapshots_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteSnapshotsAfter provides a mock function with given fields: ctx, accountIDs, at
+func (_m *BalanceRepository) DeleteSnapshotsAfter(ctx context.Context, accountIDs []int64, at time.Time) error {
+	ret := _m.Called(ctx, accountIDs, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteSnapshotsAfter")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int64, time.Time) error); ok {
+		r0 = rf(ctx, accountIDs, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// BalanceRepository_DeleteSnapshotsAfter_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteSnapshotsAfter'
+type BalanceRepository_DeleteSnapshotsAfter_Call struct {
+	*mock.Call
+}
+
+// DeleteSnapshotsAfter is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountIDs []int64
+//   - at time.Time
+func (_e *BalanceRepository_Expecter) DeleteSnapshotsAfter(ctx interface{}, accountIDs interface{}, at interface{}) *BalanceRepository_DeleteSnapshotsAfter_Call {
+	return &BalanceRepository_DeleteSnapshotsAfter_Call{Call: _e.mock.On("DeleteSnapshotsAfter", ctx, accountIDs, at)}
+}
+
+func (_c *BalanceRepository_DeleteSnapshotsAfter_Call) Run(run func(ctx context.Context, accountIDs []int64, at time.Time)) *BalanceRepository_DeleteSnapshotsAfter_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]int64), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *BalanceRepository_DeleteSnapshotsAfter_Call) Return(_a0 error) *BalanceRepository_DeleteSnapshotsAfter_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *BalanceRepository_DeleteSnapshotsAfter_Call) RunAndReturn(run func(context.Context, []int64, time.Time) error) *BalanceRepository_DeleteSnapshotsAfter_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewBalanceRepository creates a new instance of BalanceRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBalanceRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BalanceRepository {
+	mock := &BalanceRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,417 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "internal-transfers/internal/model"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// InterestRepository is an autogenerated mock type for the InterestRepository type
+type InterestRepository struct {
+	mock.Mock
+}
+
+type InterestRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *InterestRepository) EXPECT() *InterestRepository_Expecter {
+	return &InterestRepository_Expecter{mock: &_m.Mock}
+}
+
+// AccruedInterest provides a mock function with given fields: ctx, accountID, from, to
+func (_m *InterestRepository) AccruedInterest(ctx context.Context, accountID int64, from time.Time, to time.Time) (float64, error) {
+	ret := _m.Called(ctx, accountID, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AccruedInterest")
+	}
+
+	var r0 float64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time, time.Time) (float64, error)); ok {
+		return rf(ctx, accountID, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time, time.Time) float64); ok {
+		r0 = rf(ctx, accountID, from, to)
+	} else {
+		r0 = ret.Get(0).(float64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, accountID, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InterestRepository_AccruedInterest_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AccruedInterest'
+type InterestRepository_AccruedInterest_Call struct {
+	*mock.Call
+}
+
+// AccruedInterest is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID int64
+//   - from time.Time
+//   - to time.Time
+func (_e *InterestRepository_Expecter) AccruedInterest(ctx interface{}, accountID interface{}, from interface{}, to interface{}) *InterestRepository_AccruedInterest_Call {
+	return &InterestRepository_AccruedInterest_Call{Call: _e.mock.On("AccruedInterest", ctx, accountID, from, to)}
+}
+
+func (_c *InterestRepository_AccruedInterest_Call) Run(run func(ctx context.Context, accountID int64, from time.Time, to time.Time)) *InterestRepository_AccruedInterest_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(time.Time), args[3].(time.Time))
+	})
+	return _c
+}
+
+func (_c *InterestRepository_AccruedInterest_Call) Return(_a0 float64, _a1 error) *InterestRepository_AccruedInterest_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *InterestRepository_AccruedInterest_Call) RunAndReturn(run func(context.Context, int64, time.Time, time.Time) (float64, error)) *InterestRepository_AccruedInterest_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ClaimPosting provides a mock function with given fields: ctx, posting, now, staleBefore
+func (_m *InterestRepository) ClaimPosting(ctx context.Context, posting model.InterestPosting, now time.Time, staleBefore time.Time) (bool, error) {
+	ret := _m.Called(ctx, posting, now, staleBefore)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimPosting")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.InterestPosting, time.Time, time.Time) (bool, error)); ok {
+		return rf(ctx, posting, now, staleBefore)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.InterestPosting, time.Time, time.Time) bool); ok {
+		r0 = rf(ctx, posting, now, staleBefore)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.InterestPosting, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, posting, now, staleBefore)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InterestRepository_ClaimPosting_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ClaimPosting'
+type InterestRepository_ClaimPosting_Call struct {
+	*mock.Call
+}
+
+// ClaimPosting is a helper method to define mock.On call
+//   - ctx context.Context
+//   - posting model.InterestPosting
+//   - now time.Time
+//   - staleBefore time.Time
+func (_e *InterestRepository_Expecter) ClaimPosting(ctx interface{}, posting interface{}, now interface{}, staleBefore interface{}) *InterestRepository_ClaimPosting_Call {
+	return &InterestRepository_ClaimPosting_Call{Call: _e.mock.On("ClaimPosting", ctx, posting, now, staleBefore)}
+}
+
+func (_c *InterestRepository_ClaimPosting_Call) Run(run func(ctx context.Context, posting model.InterestPosting, now time.Time, staleBefore time.Time)) *InterestRepository_ClaimPosting_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(model.InterestPosting), args[2].(time.Time), args[3].(time.Time))
+	})
+	return _c
+}
+
+func (_c *InterestRepository_ClaimPosting_Call) Return(_a0 bool, _a1 error) *InterestRepository_ClaimPosting_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *InterestRepository_ClaimPosting_Call) RunAndReturn(run func(context.Context, model.InterestPosting, time.Time, time.Time) (bool, error)) *InterestRepository_ClaimPosting_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CompletePosting provides a mock function with given fields: ctx, accountID, periodStart, postedAt
+func (_m *InterestRepository) CompletePosting(ctx context.Context, accountID int64, periodStart time.Time, postedAt time.Time) error {
+	ret := _m.Called(ctx, accountID, periodStart, postedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompletePosting")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time, time.Time) error); ok {
+		r0 = rf(ctx, accountID, periodStart, postedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InterestRepository_CompletePosting_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CompletePosting'
+type InterestRepository_CompletePosting_Call struct {
+	*mock.Call
+}
+
+// CompletePosting is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID int64
+//   - periodStart time.Time
+//   - postedAt time.Time
+func (_e *InterestRepository_Expecter) CompletePosting(ctx interface{}, accountID interface{}, periodStart interface{}, postedAt interface{}) *InterestRepository_CompletePosting_Call {
+	return &InterestRepository_CompletePosting_Call{Call: _e.mock.On("CompletePosting", ctx, accountID, periodStart, postedAt)}
+}
+
+func (_c *InterestRepository_CompletePosting_Call) Run(run func(ctx context.Context, accountID int64, periodStart time.Time, postedAt time.Time)) *InterestRepository_CompletePosting_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(time.Time), args[3].(time.Time))
+	})
+	return _c
+}
+
+func (_c *InterestRepository_CompletePosting_Call) Return(_a0 error) *InterestRepository_CompletePosting_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *InterestRepository_CompletePosting_Call) RunAndReturn(run func(context.Context, int64, time.Time, time.Time) error) *InterestRepository_CompletePosting_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LastAccrualDate provides a mock function with given fields: ctx, accountID
+func (_m *InterestRepository) LastAccrualDate(ctx context.Context, accountID int64) (time.Time, error) {
+	ret := _m.Called(ctx, accountID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LastAccrualDate")
+	}
+
+	var r0 time.Time
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (time.Time, error)); ok {
+		return rf(ctx, accountID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) time.Time); ok {
+		r0 = rf(ctx, accountID)
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, accountID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InterestRepository_LastAccrualDate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LastAccrualDate'
+type InterestRepository_LastAccrualDate_Call struct {
+	*mock.Call
+}
+
+// LastAccrualDate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID int64
+func (_e *InterestRepository_Expecter) LastAccrualDate(ctx interface{}, accountID interface{}) *InterestRepository_LastAccrualDate_Call {
+	return &InterestRepository_LastAccrualDate_Call{Call: _e.mock.On("LastAccrualDate", ctx, accountID)}
+}
+
+func (_c *InterestRepository_LastAccrualDate_Call) Run(run func(ctx context.Context, accountID int64)) *InterestRepository_LastAccrualDate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *InterestRepository_LastAccrualDate_Call) Return(_a0 time.Time, _a1 error) *InterestRepository_LastAccrualDate_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *InterestRepository_LastAccrualDate_Call) RunAndReturn(run func(context.Context, int64) (time.Time, error)) *InterestRepository_LastAccrualDate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PendingPostings provides a mock function with given fields: ctx, end
+func (_m *InterestRepository) PendingPostings(ctx context.Context, end time.Time) ([]model.InterestPosting, error) {
+	ret := _m.Called(ctx, end)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PendingPostings")
+	}
+
+	var r0 []model.InterestPosting
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) ([]model.InterestPosting, error)); ok {
+		return rf(ctx, end)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) []model.InterestPosting); ok {
+		r0 = rf(ctx, end)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.InterestPosting)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, end)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InterestRepository_PendingPostings_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PendingPostings'
+type InterestRepository_PendingPostings_Call struct {
+	*mock.Call
+}
+
+// PendingPostings is a helper method to define mock.On call
+//   - ctx context.Context
+//   - end time.Time
+func (_e *InterestRepository_Expecter) PendingPostings(ctx interface{}, end interface{}) *InterestRepository_PendingPostings_Call {
+	return &InterestRepository_PendingPostings_Call{Call: _e.mock.On("PendingPostings", ctx, end)}
+}
+
+func (_c *InterestRepository_PendingPostings_Call) Run(run func(ctx context.Context, end time.Time)) *InterestRepository_PendingPostings_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *InterestRepository_PendingPostings_Call) Return(_a0 []model.InterestPosting, _a1 error) *InterestRepository_PendingPostings_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *InterestRepository_PendingPostings_Call) RunAndReturn(run func(context.Context, time.Time) ([]model.InterestPosting, error)) *InterestRepository_PendingPostings_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecordAccrual provides a mock function with given fields: ctx, accrual
+func (_m *InterestRepository) RecordAccrual(ctx context.Context, accrual *model.InterestAccrual) error {
+	ret := _m.Called(ctx, accrual)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordAccrual")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.InterestAccrual) error); ok {
+		r0 = rf(ctx, accrual)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InterestRepository_RecordAccrual_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordAccrual'
+type InterestRepository_RecordAccrual_Call struct {
+	*mock.Call
+}
+
+// RecordAccrual is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accrual *model.InterestAccrual
+func (_e *InterestRepository_Expecter) RecordAccrual(ctx interface{}, accrual interface{}) *InterestRepository_RecordAccrual_Call {
+	return &InterestRepository_RecordAccrual_Call{Call: _e.mock.On("RecordAccrual", ctx, accrual)}
+}
+
+func (_c *InterestRepository_RecordAccrual_Call) Run(run func(ctx context.Context, accrual *model.InterestAccrual)) *InterestRepository_RecordAccrual_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.InterestAccrual))
+	})
+	return _c
+}
+
+func (_c *InterestRepository_RecordAccrual_Call) Return(_a0 error) *InterestRepository_RecordAccrual_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *InterestRepository_RecordAccrual_Call) RunAndReturn(run func(context.Context, *model.InterestAccrual) error) *InterestRepository_RecordAccrual_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReopenAccruals provides a mock function with given fields: ctx, accountIDs, from
+func (_m *InterestRepository) ReopenAccruals(ctx context.Context, accountIDs []int64, from time.Time) error {
+	ret := _m.Called(ctx, accountIDs, from)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReopenAccruals")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int64, time.Time) error); ok {
+		r0 = rf(ctx, accountIDs, from)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InterestRepository_ReopenAccruals_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReopenAccruals'
+type InterestRepository_ReopenAccruals_Call struct {
+	*mock.Call
+}
+
+// ReopenAccruals is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountIDs []int64
+//   - from time.Time
+func (_e *InterestRepository_Expecter) ReopenAccruals(ctx interface{}, accountIDs interface{}, from interface{}) *InterestRepository_ReopenAccruals_Call {
+	return &InterestRepository_ReopenAccruals_Call{Call: _e.mock.On("ReopenAccruals", ctx, accountIDs, from)}
+}
+
+func (_c *InterestRepository_ReopenAccruals_Call) Run(run func(ctx context.Context, accountIDs []int64, from time.Time)) *InterestRepository_ReopenAccruals_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]int64), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *InterestRepository_ReopenAccruals_Call) Return(_a0 error) *InterestRepository_ReopenAccruals_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *InterestRepository_ReopenAccruals_Call) RunAndReturn(run func(context.Context, []int64, time.Time) error) *InterestRepository_ReopenAccruals_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewInterestRepository creates a new instance of InterestRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewInterestRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *InterestRepository {
+	mock := &InterestRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,353 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "internal-transfers/internal/model"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// PeriodRepository is an autogenerated mock type for the PeriodRepository type
+type PeriodRepository struct {
+	mock.Mock
+}
+
+type PeriodRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *PeriodRepository) EXPECT() *PeriodRepository_Expecter {
+	return &PeriodRepository_Expecter{mock: &_m.Mock}
+}
+
+// ClosePeriod provides a mock function with given fields: ctx, period
+func (_m *PeriodRepository) ClosePeriod(ctx context.Context, period *model.Period) error {
+	ret := _m.Called(ctx, period)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClosePeriod")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Period) error); ok {
+		r0 = rf(ctx, period)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PeriodRepository_ClosePeriod_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ClosePeriod'
+type PeriodRepository_ClosePeriod_Call struct {
+	*mock.Call
+}
+
+// ClosePeriod is a helper method to define mock.On call
+//   - ctx context.Context
+//   - period *model.Period
+func (_e *PeriodRepository_Expecter) ClosePeriod(ctx interface{}, period interface{}) *PeriodRepository_ClosePeriod_Call {
+	return &PeriodRepository_ClosePeriod_Call{Call: _e.mock.On("ClosePeriod", ctx, period)}
+}
+
+func (_c *PeriodRepository_ClosePeriod_Call) Run(run func(ctx context.Context, period *model.Period)) *PeriodRepository_ClosePeriod_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.Period))
+	})
+	return _c
+}
+
+func (_c *PeriodRepository_ClosePeriod_Call) Return(_a0 error) *PeriodRepository_ClosePeriod_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *PeriodRepository_ClosePeriod_Call) RunAndReturn(run func(context.Context, *model.Period) error) *PeriodRepository_ClosePeriod_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LastClosed provides a mock function with given fields: ctx
+func (_m *PeriodRepository) LastClosed(ctx context.Context) (time.Time, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LastClosed")
+	}
+
+	var r0 time.Time
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (time.Time, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) time.Time); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PeriodRepository_LastClosed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LastClosed'
+type PeriodRepository_LastClosed_Call struct {
+	*mock.Call
+}
+
+// LastClosed is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *PeriodRepository_Expecter) LastClosed(ctx interface{}) *PeriodRepository_LastClosed_Call {
+	return &PeriodRepository_LastClosed_Call{Call: _e.mock.On("LastClosed", ctx)}
+}
+
+func (_c *PeriodRepository_LastClosed_Call) Run(run func(ctx context.Context)) *PeriodRepository_LastClosed_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *PeriodRepository_LastClosed_Call) Return(_a0 time.Time, _a1 error) *PeriodRepository_LastClosed_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *PeriodRepository_LastClosed_Call) RunAndReturn(run func(context.Context) (time.Time, error)) *PeriodRepository_LastClosed_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListPeriods provides a mock function with given fields: ctx, from, to
+func (_m *PeriodRepository) ListPeriods(ctx context.Context, from time.Time, to time.Time) ([]*model.Period, error) {
+	ret := _m.Called(ctx, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPeriods")
+	}
+
+	var r0 []*model.Period
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) ([]*model.Period, error)); ok {
+		return rf(ctx, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) []*model.Period); ok {
+		r0 = rf(ctx, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Period)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PeriodRepository_ListPeriods_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListPeriods'
+type PeriodRepository_ListPeriods_Call struct {
+	*mock.Call
+}
+
+// ListPeriods is a helper method to define mock.On call
+//   - ctx context.Context
+//   - from time.Time
+//   - to time.Time
+func (_e *PeriodRepository_Expecter) ListPeriods(ctx interface{}, from interface{}, to interface{}) *PeriodRepository_ListPeriods_Call {
+	return &PeriodRepository_ListPeriods_Call{Call: _e.mock.On("ListPeriods", ctx, from, to)}
+}
+
+func (_c *PeriodRepository_ListPeriods_Call) Run(run func(ctx context.Context, from time.Time, to time.Time)) *PeriodRepository_ListPeriods_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *PeriodRepository_ListPeriods_Call) Return(_a0 []*model.Period, _a1 error) *PeriodRepository_ListPeriods_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *PeriodRepository_ListPeriods_Call) RunAndReturn(run func(context.Context, time.Time, time.Time) ([]*model.Period, error)) *PeriodRepository_ListPeriods_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LockExclusive provides a mock function with given fields: ctx
+func (_m *PeriodRepository) LockExclusive(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LockExclusive")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PeriodRepository_LockExclusive_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LockExclusive'
+type PeriodRepository_LockExclusive_Call struct {
+	*mock.Call
+}
+
+// LockExclusive is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *PeriodRepository_Expecter) LockExclusive(ctx interface{}) *PeriodRepository_LockExclusive_Call {
+	return &PeriodRepository_LockExclusive_Call{Call: _e.mock.On("LockExclusive", ctx)}
+}
+
+func (_c *PeriodRepository_LockExclusive_Call) Run(run func(ctx context.Context)) *PeriodRepository_LockExclusive_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *PeriodRepository_LockExclusive_Call) Return(_a0 error) *PeriodRepository_LockExclusive_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *PeriodRepository_LockExclusive_Call) RunAndReturn(run func(context.Context) error) *PeriodRepository_LockExclusive_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LockShared provides a mock function with given fields: ctx
+func (_m *PeriodRepository) LockShared(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LockShared")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PeriodRepository_LockShared_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LockShared'
+type PeriodRepository_LockShared_Call struct {
+	*mock.Call
+}
+
+// LockShared is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *PeriodRepository_Expecter) LockShared(ctx interface{}) *PeriodRepository_LockShared_Call {
+	return &PeriodRepository_LockShared_Call{Call: _e.mock.On("LockShared", ctx)}
+}
+
+func (_c *PeriodRepository_LockShared_Call) Run(run func(ctx context.Context)) *PeriodRepository_LockShared_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *PeriodRepository_LockShared_Call) Return(_a0 error) *PeriodRepository_LockShared_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *PeriodRepository_LockShared_Call) RunAndReturn(run func(context.Context) error) *PeriodRepository_LockShared_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// TrialBalance provides a mock function with given fields: ctx, at
+func (_m *PeriodRepository) TrialBalance(ctx context.Context, at time.Time) (*model.TrialBalance, error) {
+	ret := _m.Called(ctx, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TrialBalance")
+	}
+
+	var r0 *model.TrialBalance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (*model.TrialBalance, error)); ok {
+		return rf(ctx, at)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) *model.TrialBalance); ok {
+		r0 = rf(ctx, at)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.TrialBalance)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PeriodRepository_TrialBalance_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TrialBalance'
+type PeriodRepository_TrialBalance_Call struct {
+	*mock.Call
+}
+
+// TrialBalance is a helper method to define mock.On call
+//   - ctx context.Context
+//   - at time.Time
+func (_e *PeriodRepository_Expecter) TrialBalance(ctx interface{}, at interface{}) *PeriodRepository_TrialBalance_Call {
+	return &PeriodRepository_TrialBalance_Call{Call: _e.mock.On("TrialBalance", ctx, at)}
+}
+
+func (_c *PeriodRepository_TrialBalance_Call) Run(run func(ctx context.Context, at time.Time)) *PeriodRepository_TrialBalance_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *PeriodRepository_TrialBalance_Call) Return(_a0 *model.TrialBalance, _a1 error) *PeriodRepository_TrialBalance_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *PeriodRepository_TrialBalance_Call) RunAndReturn(run func(context.Context, time.Time) (*model.TrialBalance, error)) *PeriodRepository_TrialBalance_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewPeriodRepository creates a new instance of PeriodRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPeriodRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PeriodRepository {
+	mock := &PeriodRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
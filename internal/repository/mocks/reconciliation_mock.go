@@ -0,0 +1,155 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "internal-transfers/internal/model"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ReconciliationRepository is an autogenerated mock type for the ReconciliationRepository type
+type ReconciliationRepository struct {
+	mock.Mock
+}
+
+type ReconciliationRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *ReconciliationRepository) EXPECT() *ReconciliationRepository_Expecter {
+	return &ReconciliationRepository_Expecter{mock: &_m.Mock}
+}
+
+// FindDiscrepancies provides a mock function with given fields: ctx
+func (_m *ReconciliationRepository) FindDiscrepancies(ctx context.Context) (*model.BalanceCheck, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindDiscrepancies")
+	}
+
+	var r0 *model.BalanceCheck
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*model.BalanceCheck, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *model.BalanceCheck); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.BalanceCheck)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReconciliationRepository_FindDiscrepancies_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindDiscrepancies'
+type ReconciliationRepository_FindDiscrepancies_Call struct {
+	*mock.Call
+}
+
+// FindDiscrepancies is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *ReconciliationRepository_Expecter) FindDiscrepancies(ctx interface{}) *ReconciliationRepository_FindDiscrepancies_Call {
+	return &ReconciliationRepository_FindDiscrepancies_Call{Call: _e.mock.On("FindDiscrepancies", ctx)}
+}
+
+func (_c *ReconciliationRepository_FindDiscrepancies_Call) Run(run func(ctx context.Context)) *ReconciliationRepository_FindDiscrepancies_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *ReconciliationRepository_FindDiscrepancies_Call) Return(_a0 *model.BalanceCheck, _a1 error) *ReconciliationRepository_FindDiscrepancies_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ReconciliationRepository_FindDiscrepancies_Call) RunAndReturn(run func(context.Context) (*model.BalanceCheck, error)) *ReconciliationRepository_FindDiscrepancies_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Supply provides a mock function with given fields: ctx, mintAccountID, settlementAccountIDs
+func (_m *ReconciliationRepository) Supply(ctx context.Context, mintAccountID int64, settlementAccountIDs []int64) (*model.Supply, error) {
+	ret := _m.Called(ctx, mintAccountID, settlementAccountIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Supply")
+	}
+
+	var r0 *model.Supply
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []int64) (*model.Supply, error)); ok {
+		return rf(ctx, mintAccountID, settlementAccountIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []int64) *model.Supply); ok {
+		r0 = rf(ctx, mintAccountID, settlementAccountIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Supply)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, []int64) error); ok {
+		r1 = rf(ctx, mintAccountID, settlementAccountIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReconciliationRepository_Supply_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Supply'
+type ReconciliationRepository_Supply_Call struct {
+	*mock.Call
+}
+
+// Supply is a helper method to define mock.On call
+//   - ctx context.Context
+//   - mintAccountID int64
+//   - settlementAccountIDs []int64
+func (_e *ReconciliationRepository_Expecter) Supply(ctx interface{}, mintAccountID interface{}, settlementAccountIDs interface{}) *ReconciliationRepository_Supply_Call {
+	return &ReconciliationRepository_Supply_Call{Call: _e.mock.On("Supply", ctx, mintAccountID, settlementAccountIDs)}
+}
+
+func (_c *ReconciliationRepository_Supply_Call) Run(run func(ctx context.Context, mintAccountID int64, settlementAccountIDs []int64)) *ReconciliationRepository_Supply_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].([]int64))
+	})
+	return _c
+}
+
+func (_c *ReconciliationRepository_Supply_Call) Return(_a0 *model.Supply, _a1 error) *ReconciliationRepository_Supply_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ReconciliationRepository_Supply_Call) RunAndReturn(run func(context.Context, int64, []int64) (*model.Supply, error)) *ReconciliationRepository_Supply_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewReconciliationRepository creates a new instance of ReconciliationRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewReconciliationRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ReconciliationRepository {
+	mock := &ReconciliationRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -8,7 +8,9 @@ import (
 
 	mock "github.com/stretchr/testify/mock"
 
-	sql "database/sql"
+	repository "internal-transfers/internal/repository"
+
+	time "time"
 )
 
 // TransactionRepository is an autogenerated mock type for the TransactionRepository type
@@ -24,17 +26,17 @@ func (_m *TransactionRepository) EXPECT() *TransactionRepository_Expecter {
 	return &TransactionRepository_Expecter{mock: &_m.Mock}
 }
 
-// CreateTransaction provides a mock function with given fields: ctx, tx, transaction
-func (_m *TransactionRepository) CreateTransaction(ctx context.Context, tx *sql.Tx, transaction *model.Transaction) error {
-	ret := _m.Called(ctx, tx, transaction)
+// CreateTransaction provides a mock function with given fields: ctx, transaction
+func (_m *TransactionRepository) CreateTransaction(ctx context.Context, transaction *model.Transaction) error {
+	ret := _m.Called(ctx, transaction)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateTransaction")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *model.Transaction) error); ok {
-		r0 = rf(ctx, tx, transaction)
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Transaction) error); ok {
+		r0 = rf(ctx, transaction)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -49,15 +51,14 @@ type TransactionRepository_CreateTransaction_Call struct {
 
 // CreateTransaction is a helper method to define mock.On call
 //   - ctx context.Context
-//   - tx *sql.Tx
 //   - transaction *model.Transaction
-func (_e *TransactionRepository_Expecter) CreateTransaction(ctx interface{}, tx interface{}, transaction interface{}) *TransactionRepository_CreateTransaction_Call {
-	return &TransactionRepository_CreateTransaction_Call{Call: _e.mock.On("CreateTransaction", ctx, tx, transaction)}
+func (_e *TransactionRepository_Expecter) CreateTransaction(ctx interface{}, transaction interface{}) *TransactionRepository_CreateTransaction_Call {
+	return &TransactionRepository_CreateTransaction_Call{Call: _e.mock.On("CreateTransaction", ctx, transaction)}
 }
 
-func (_c *TransactionRepository_CreateTransaction_Call) Run(run func(ctx context.Context, tx *sql.Tx, transaction *model.Transaction)) *TransactionRepository_CreateTransaction_Call {
+func (_c *TransactionRepository_CreateTransaction_Call) Run(run func(ctx context.Context, transaction *model.Transaction)) *TransactionRepository_CreateTransaction_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(*sql.Tx), args[2].(*model.Transaction))
+		run(args[0].(context.Context), args[1].(*model.Transaction))
 	})
 	return _c
 }
@@ -67,7 +68,64 @@ func (_c *TransactionRepository_CreateTransaction_Call) Return(_a0 error) *Trans
 	return _c
 }
 
-func (_c *TransactionRepository_CreateTransaction_Call) RunAndReturn(run func(context.Context, *sql.Tx, *model.Transaction) error) *TransactionRepository_CreateTransaction_Call {
+func (_c *TransactionRepository_CreateTransaction_Call) RunAndReturn(run func(context.Context, *model.Transaction) error) *TransactionRepository_CreateTransaction_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateTransferGroup provides a mock function with given fields: ctx, createdAt
+func (_m *TransactionRepository) CreateTransferGroup(ctx context.Context, createdAt time.Time) (int64, error) {
+	ret := _m.Called(ctx, createdAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateTransferGroup")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, createdAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, createdAt)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, createdAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TransactionRepository_CreateTransferGroup_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateTransferGroup'
+type TransactionRepository_CreateTransferGroup_Call struct {
+	*mock.Call
+}
+
+// CreateTransferGroup is a helper method to define mock.On call
+//   - ctx context.Context
+//   - createdAt time.Time
+func (_e *TransactionRepository_Expecter) CreateTransferGroup(ctx interface{}, createdAt interface{}) *TransactionRepository_CreateTransferGroup_Call {
+	return &TransactionRepository_CreateTransferGroup_Call{Call: _e.mock.On("CreateTransferGroup", ctx, createdAt)}
+}
+
+func (_c *TransactionRepository_CreateTransferGroup_Call) Run(run func(ctx context.Context, createdAt time.Time)) *TransactionRepository_CreateTransferGroup_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *TransactionRepository_CreateTransferGroup_Call) Return(_a0 int64, _a1 error) *TransactionRepository_CreateTransferGroup_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *TransactionRepository_CreateTransferGroup_Call) RunAndReturn(run func(context.Context, time.Time) (int64, error)) *TransactionRepository_CreateTransferGroup_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -131,9 +189,9 @@ func (_c *TransactionRepository_GetTransaction_Call) RunAndReturn(run func(conte
 	return _c
 }
 
-// ListTransactions provides a mock function with given fields: ctx
-func (_m *TransactionRepository) ListTransactions(ctx context.Context) ([]*model.Transaction, error) {
-	ret := _m.Called(ctx)
+// ListTransactions provides a mock function with given fields: ctx, filter
+func (_m *TransactionRepository) ListTransactions(ctx context.Context, filter repository.TransactionFilter) ([]*model.Transaction, error) {
+	ret := _m.Called(ctx, filter)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ListTransactions")
@@ -141,19 +199,19 @@ func (_m *TransactionRepository) ListTransactions(ctx context.Context) ([]*model
 
 	var r0 []*model.Transaction
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]*model.Transaction, error)); ok {
-		return rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, repository.TransactionFilter) ([]*model.Transaction, error)); ok {
+		return rf(ctx, filter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) []*model.Transaction); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, repository.TransactionFilter) []*model.Transaction); ok {
+		r0 = rf(ctx, filter)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*model.Transaction)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, repository.TransactionFilter) error); ok {
+		r1 = rf(ctx, filter)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -168,13 +226,14 @@ type TransactionRepository_ListTransactions_Call struct {
 
 // ListTransactions is a helper method to define mock.On call
 //   - ctx context.Context
-func (_e *TransactionRepository_Expecter) ListTransactions(ctx interface{}) *TransactionRepository_ListTransactions_Call {
-	return &TransactionRepository_ListTransactions_Call{Call: _e.mock.On("ListTransactions", ctx)}
+//   - filter repository.TransactionFilter
+func (_e *TransactionRepository_Expecter) ListTransactions(ctx interface{}, filter interface{}) *TransactionRepository_ListTransactions_Call {
+	return &TransactionRepository_ListTransactions_Call{Call: _e.mock.On("ListTransactions", ctx, filter)}
 }
 
-func (_c *TransactionRepository_ListTransactions_Call) Run(run func(ctx context.Context)) *TransactionRepository_ListTransactions_Call {
+func (_c *TransactionRepository_ListTransactions_Call) Run(run func(ctx context.Context, filter repository.TransactionFilter)) *TransactionRepository_ListTransactions_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context))
+		run(args[0].(context.Context), args[1].(repository.TransactionFilter))
 	})
 	return _c
 }
@@ -184,7 +243,58 @@ func (_c *TransactionRepository_ListTransactions_Call) Return(_a0 []*model.Trans
 	return _c
 }
 
-func (_c *TransactionRepository_ListTransactions_Call) RunAndReturn(run func(context.Context) ([]*model.Transaction, error)) *TransactionRepository_ListTransactions_Call {
+func (_c *TransactionRepository_ListTransactions_Call) RunAndReturn(run func(context.Context, repository.TransactionFilter) ([]*model.Transaction, error)) *TransactionRepository_ListTransactions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StreamStatement provides a mock function with given fields: ctx, accountID, from, to, opening, entry
+func (_m *TransactionRepository) StreamStatement(ctx context.Context, accountID int64, from time.Time, to time.Time, opening func(float64) error, entry func(*model.Transaction) error) error {
+	ret := _m.Called(ctx, accountID, from, to, opening, entry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StreamStatement")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time, time.Time, func(float64) error, func(*model.Transaction) error) error); ok {
+		r0 = rf(ctx, accountID, from, to, opening, entry)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TransactionRepository_StreamStatement_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StreamStatement'
+type TransactionRepository_StreamStatement_Call struct {
+	*mock.Call
+}
+
+// StreamStatement is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID int64
+//   - from time.Time
+//   - to time.Time
+//   - opening func(float64) error
+//   - entry func(*model.Transaction) error
+func (_e *TransactionRepository_Expecter) StreamStatement(ctx interface{}, accountID interface{}, from interface{}, to interface{}, opening interface{}, entry interface{}) *TransactionRepository_StreamStatement_Call {
+	return &TransactionRepository_StreamStatement_Call{Call: _e.mock.On("StreamStatement", ctx, accountID, from, to, opening, entry)}
+}
+
+func (_c *TransactionRepository_StreamStatement_Call) Run(run func(ctx context.Context, accountID int64, from time.Time, to time.Time, opening func(float64) error, entry func(*model.Transaction) error)) *TransactionRepository_StreamStatement_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(time.Time), args[3].(time.Time), args[4].(func(float64) error), args[5].(func(*model.Transaction) error))
+	})
+	return _c
+}
+
+func (_c *TransactionRepository_StreamStatement_Call) Return(_a0 error) *TransactionRepository_StreamStatement_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *TransactionRepository_StreamStatement_Call) RunAndReturn(run func(context.Context, int64, time.Time, time.Time, func(float64) error, func(*model.Transaction) error) error) *TransactionRepository_StreamStatement_Call {
 	_c.Call.Return(run)
 	return _c
 }
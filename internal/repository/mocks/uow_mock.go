@@ -0,0 +1,84 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	repository "internal-transfers/internal/repository"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// UnitOfWork is an autogenerated mock type for the UnitOfWork type
+type UnitOfWork struct {
+	mock.Mock
+}
+
+type UnitOfWork_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *UnitOfWork) EXPECT() *UnitOfWork_Expecter {
+	return &UnitOfWork_Expecter{mock: &_m.Mock}
+}
+
+// WithinTx provides a mock function with given fields: ctx, fn
+func (_m *UnitOfWork) WithinTx(ctx context.Context, fn func(context.Context, repository.Repositories) error) error {
+	ret := _m.Called(ctx, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithinTx")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, func(context.Context, repository.Repositories) error) error); ok {
+		r0 = rf(ctx, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UnitOfWork_WithinTx_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WithinTx'
+type UnitOfWork_WithinTx_Call struct {
+	*mock.Call
+}
+
+// WithinTx is a helper method to define mock.On call
+//   - ctx context.Context
+//   - fn func(context.Context , repository.Repositories) error
+func (_e *UnitOfWork_Expecter) WithinTx(ctx interface{}, fn interface{}) *UnitOfWork_WithinTx_Call {
+	return &UnitOfWork_WithinTx_Call{Call: _e.mock.On("WithinTx", ctx, fn)}
+}
+
+func (_c *UnitOfWork_WithinTx_Call) Run(run func(ctx context.Context, fn func(context.Context, repository.Repositories) error)) *UnitOfWork_WithinTx_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(func(context.Context, repository.Repositories) error))
+	})
+	return _c
+}
+
+func (_c *UnitOfWork_WithinTx_Call) Return(_a0 error) *UnitOfWork_WithinTx_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *UnitOfWork_WithinTx_Call) RunAndReturn(run func(context.Context, func(context.Context, repository.Repositories) error) error) *UnitOfWork_WithinTx_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewUnitOfWork creates a new instance of UnitOfWork. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewUnitOfWork(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *UnitOfWork {
+	mock := &UnitOfWork{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
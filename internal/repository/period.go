@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel/attribute"
+
+	"internal-transfers/internal/domain"
+	"internal-transfers/internal/model"
+)
+
+// PeriodRepository records closed accounting periods. Units of work recording backdated entries hold a shared
+// period lock, and those closing periods or writing snapshots an exclusive one, so a period cannot close while
+// an entry into it is in flight.
+//
+//go:generate mockery --name=PeriodRepository --filename=period_mock.go --output=./mocks --with-expecter
+type PeriodRepository interface {
+	// LockShared takes the period lock in shared mode until the enclosing unit of work ends
+	LockShared(ctx context.Context) error
+	// LockExclusive takes the period lock in exclusive mode until the enclosing unit of work ends
+	LockExclusive(ctx context.Context) error
+	// LastClosed returns the day of the latest closed period, or the zero time if none was closed
+	LastClosed(ctx context.Context) (time.Time, error)
+	// ClosePeriod records a closed period; it returns domain.ErrPeriodClosed if the period was closed already
+	ClosePeriod(ctx context.Context, period *model.Period) error
+	// ListPeriods returns the periods closed in [from, to], oldest first
+	ListPeriods(ctx context.Context, from, to time.Time) ([]*model.Period, error)
+	// TrialBalance totals the balance snapshots taken at by account type, and the initial balances of the
+	// accounts snapshotted as the opening balances line
+	TrialBalance(ctx context.Context, at time.Time) (*model.TrialBalance, error)
+}
+
+// periodLockKey identifies the period lock among the advisory locks of the database
+const periodLockKey int64 = 0x7065_7269_6f64 // "period"
+
+type periodRepository struct {
+	db querier
+}
+
+func NewPeriodRepository(db *sql.DB) PeriodRepository {
+	return &periodRepository{db: db}
+}
+
+func (r *periodRepository) LockShared(ctx context.Context) (err error) {
+	ctx, span := startSpan(ctx, "LockShared")
+	defer func() { endSpan(span, err) }()
+
+	if _, err := r.db.ExecContext(ctx, `SELECT pg_advisory_xact_lock_shared($1)`, periodLockKey); err != nil {
+		return fmt.Errorf("lock periods failed: %w", err)
+	}
+	return nil
+}
+
+func (r *periodRepository) LockExclusive(ctx context.Context) (err error) {
+	ctx, span := startSpan(ctx, "LockExclusive")
+	defer func() { endSpan(span, err) }()
+
+	if _, err := r.db.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, periodLockKey); err != nil {
+		return fmt.Errorf("lock periods failed: %w", err)
+	}
+	return nil
+}
+
+func (r *periodRepository) LastClosed(ctx context.Context) (_ time.Time, err error) {
+	ctx, span := startSpan(ctx, "LastClosed")
+	defer func() { endSpan(span, err) }()
+
+	var last sql.NullTime
+	if err := r.db.QueryRowContext(ctx, `SELECT MAX(period_date) FROM accounting_periods`).Scan(&last); err != nil {
+		return time.Time{}, fmt.Errorf("get last closed period failed: %w", err)
+	}
+	if !last.Valid {
+		return time.Time{}, nil
+	}
+	return utcDate(last.Time), nil
+}
+
+func (r *periodRepository) ClosePeriod(ctx context.Context, period *model.Period) (err error) {
+	ctx, span := startSpan(ctx, "ClosePeriod", attribute.String("period.date", sqlDate(period.Date)))
+	defer func() { endSpan(span, err) }()
+
+	trialBalance, err := json.Marshal(period.TrialBalance)
+	if err != nil {
+		return fmt.Errorf("marshal trial balance failed: %w", err)
+	}
+	query := `
+        INSERT INTO accounting_periods (period_date, closed_at, closed_by, trial_balance)
+        VALUES ($1, $2, $3, $4)`
+	_, err = r.db.ExecContext(ctx, query, sqlDate(period.Date), period.ClosedAt, period.ClosedBy, trialBalance)
+	if err != nil {
+		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == pgerrcode.UniqueViolation {
+			return domain.ErrPeriodClosed
+		}
+		return fmt.Errorf("close period failed: %w", err)
+	}
+	return nil
+}
+
+func (r *periodRepository) ListPeriods(ctx context.Context, from, to time.Time) (_ []*model.Period, err error) {
+	ctx, span := startSpan(ctx, "ListPeriods")
+	defer func() { endSpan(span, err) }()
+
+	query := `
+        SELECT period_date, closed_at, closed_by, trial_balance FROM accounting_periods
+        WHERE period_date >= $1 AND period_date <= $2
+        ORDER BY period_date`
+	rows, err := r.db.QueryContext(ctx, query, sqlDate(from), sqlDate(to))
+	if err != nil {
+		return nil, fmt.Errorf("list periods failed: %w", err)
+	}
+	defer rows.Close()
+
+	var periods []*model.Period
+	for rows.Next() {
+		p := model.Period{Closed: true}
+		var trialBalance []byte
+		if err := rows.Scan(&p.Date, &p.ClosedAt, &p.ClosedBy, &trialBalance); err != nil {
+			return nil, fmt.Errorf("row scan failed: %w", err)
+		}
+		if err := json.Unmarshal(trialBalance, &p.TrialBalance); err != nil {
+			return nil, fmt.Errorf("unmarshal trial balance failed: %w", err)
+		}
+		p.Date = utcDate(p.Date)
+		periods = append(periods, &p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return periods, nil
+}
+
+func (r *periodRepository) TrialBalance(ctx context.Context, at time.Time) (_ *model.TrialBalance, err error) {
+	ctx, span := startSpan(ctx, "TrialBalance", attribute.String("snapshot.as_of", at.Format(time.RFC3339)))
+	defer func() { endSpan(span, err) }()
+
+	query := `
+        SELECT a.type, COUNT(*),
+            COALESCE(SUM(CASE WHEN s.balance < 0 THEN -s.balance ELSE 0 END), 0),
+            COALESCE(SUM(CASE WHEN s.balance > 0 THEN s.balance ELSE 0 END), 0)
+        FROM balance_snapshots s
+        JOIN accounts a ON a.account_id = s.account_id
+        WHERE s.as_of = $1
+        GROUP BY a.type
+        ORDER BY a.type`
+	rows, err := r.db.QueryContext(ctx, query, at)
+	if err != nil {
+		return nil, fmt.Errorf("trial balance failed: %w", err)
+	}
+	defer rows.Close()
+
+	tb := &model.TrialBalance{}
+	for rows.Next() {
+		var line model.TrialBalanceLine
+		if err := rows.Scan(&line.AccountType, &line.Accounts, &line.Debit, &line.Credit); err != nil {
+			return nil, fmt.Errorf("row scan failed: %w", err)
+		}
+		tb.Lines = append(tb.Lines, line)
+		tb.Debits += line.Debit
+		tb.Credits += line.Credit
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	// accounts that predate recorded initial balances are taken to have opened with what their history does
+	// not explain, as reconciliation cannot tell it from drift for them either
+	query = `
+        SELECT COUNT(*) FILTER (WHERE o.opening <> 0),
+            COALESCE(SUM(CASE WHEN o.opening > 0 THEN o.opening ELSE 0 END), 0),
+            COALESCE(SUM(CASE WHEN o.opening < 0 THEN -o.opening ELSE 0 END), 0)
+        FROM (
+            SELECT COALESCE(a.initial_balance, a.balance - COALESCE((
+                SELECT SUM(CASE WHEN t.destination_account_id = a.account_id THEN t.amount ELSE -t.amount END)
+                FROM transactions t
+                WHERE t.source_account_id = a.account_id OR t.destination_account_id = a.account_id
+            ), 0)) AS opening
+            FROM balance_snapshots s
+            JOIN accounts a ON a.account_id = s.account_id
+            WHERE s.as_of = $1
+        ) o`
+	opening := &tb.OpeningBalances
+	if err := r.db.QueryRowContext(ctx, query, at).Scan(&opening.Accounts, &opening.Debit, &opening.Credit); err != nil {
+		return nil, fmt.Errorf("opening balances failed: %w", err)
+	}
+	opening.Debit, opening.Credit = math.Round(opening.Debit*100)/100, math.Round(opening.Credit*100)/100
+	tb.Debits += opening.Debit
+	tb.Credits += opening.Credit
+	tb.Debits, tb.Credits = math.Round(tb.Debits*100)/100, math.Round(tb.Credits*100)/100
+	return tb, nil
+}
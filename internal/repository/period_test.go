@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"internal-transfers/internal/domain"
+	"internal-transfers/internal/model"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgerrcode"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeriodRepository_ClosePeriod(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := &periodRepository{db: db}
+	ctx := context.Background()
+	closedAt := time.Date(2025, 2, 1, 0, 5, 0, 0, time.UTC)
+	period := &model.Period{
+		Date:         time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+		ClosedAt:     closedAt,
+		ClosedBy:     "finance",
+		TrialBalance: &model.TrialBalance{Credits: 150},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		// given
+		mock.ExpectExec(`INSERT INTO accounting_periods`).
+			WithArgs("2025-01-31", closedAt, "finance", sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		// when
+		err := repo.ClosePeriod(ctx, period)
+
+		// then
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("already closed", func(t *testing.T) {
+		// given
+		mock.ExpectExec(`INSERT INTO accounting_periods`).
+			WillReturnError(&pq.Error{Code: pgerrcode.UniqueViolation})
+
+		// when
+		err := repo.ClosePeriod(ctx, period)
+
+		// then
+		assert.ErrorIs(t, err, domain.ErrPeriodClosed)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPeriodRepository_ListPeriods(t *testing.T) {
+	// given
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := &periodRepository{db: db}
+	day := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+	closedAt := day.Add(24*time.Hour + 5*time.Minute)
+	mock.ExpectQuery(`SELECT period_date, closed_at, closed_by, trial_balance FROM accounting_periods`).
+		WithArgs("2025-01-01", "2025-01-31").
+		WillReturnRows(sqlmock.NewRows([]string{"period_date", "closed_at", "closed_by", "trial_balance"}).
+			AddRow(day, closedAt, "eod-close", []byte(`{"Lines":[{"AccountType":"customer","Accounts":2,"Debit":0,"Credit":150}],"Debits":0,"Credits":150}`)))
+
+	// when
+	periods, err := repo.ListPeriods(context.Background(), day.AddDate(0, 0, -30), day)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, []*model.Period{{
+		Date:     day,
+		Closed:   true,
+		ClosedAt: closedAt,
+		ClosedBy: "eod-close",
+		TrialBalance: &model.TrialBalance{
+			Lines:   []model.TrialBalanceLine{{AccountType: model.AccountCustomer, Accounts: 2, Credit: 150}},
+			Credits: 150,
+		},
+	}}, periods)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPeriodRepository_TrialBalance(t *testing.T) {
+	// given
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := &periodRepository{db: db}
+	at := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`FROM balance_snapshots s\s+JOIN accounts a`).
+		WithArgs(at).
+		WillReturnRows(sqlmock.NewRows([]string{"type", "count", "debit", "credit"}).
+			AddRow("customer", int64(2), 0.0, 150.1).
+			AddRow("system", int64(1), 100.0, 0.0))
+	mock.ExpectQuery(`SELECT COALESCE\(a.initial_balance, a.balance - COALESCE`).
+		WithArgs(at).
+		WillReturnRows(sqlmock.NewRows([]string{"count", "debit", "credit"}).AddRow(int64(1), 50.1, 0.0))
+
+	// when
+	tb, err := repo.TrialBalance(context.Background(), at)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, &model.TrialBalance{
+		Lines: []model.TrialBalanceLine{
+			{AccountType: model.AccountCustomer, Accounts: 2, Credit: 150.1},
+			{AccountType: model.AccountSystem, Accounts: 1, Debit: 100},
+		},
+		OpeningBalances: model.TrialBalanceLine{Accounts: 1, Debit: 50.1},
+		Debits:          150.1,
+		Credits:         150.1,
+	}, tb)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel/attribute"
+
+	"internal-transfers/internal/model"
+)
+
+// ReconciliationRepository checks stored balances against the transaction history
+//
+//go:generate mockery --name=ReconciliationRepository --filename=reconciliation_mock.go --output=./mocks --with-expecter
+type ReconciliationRepository interface {
+	// FindDiscrepancies returns how many accounts were checked and those whose balance is not their initial
+	// balance plus everything received less everything sent, ordered by account id
+	FindDiscrepancies(ctx context.Context) (*model.BalanceCheck, error)
+	// Supply totals the balances of every account other than the system accounts; mintAccountID is 0 when there
+	// is no mint
+	Supply(ctx context.Context, mintAccountID int64, settlementAccountIDs []int64) (*model.Supply, error)
+}
+
+type reconciliationRepository struct {
+	db querier
+}
+
+func NewReconciliationRepository(db *sql.DB) ReconciliationRepository {
+	return &reconciliationRepository{db: db}
+}
+
+func (r *reconciliationRepository) FindDiscrepancies(ctx context.Context) (_ *model.BalanceCheck, err error) {
+	ctx, span := startSpan(ctx, "FindDiscrepancies")
+	defer func() { endSpan(span, err) }()
+
+	// the counts are joined to the discrepancies so all come from one snapshot, and a row is returned even
+	// when every account reconciles; accounts without an initial balance have no expected balance to differ from
+	query := `
+        WITH expected AS (
+            SELECT a.account_id, a.balance, a.initial_balance + COALESCE(f.net, 0) AS expected_balance
+            FROM accounts a
+            LEFT JOIN (
+                SELECT account_id, SUM(delta) AS net FROM (
+                    SELECT destination_account_id AS account_id, amount AS delta FROM transactions
+                    UNION ALL
+                    SELECT source_account_id, -amount FROM transactions
+                ) d
+                GROUP BY account_id
+            ) f ON f.account_id = a.account_id
+        )
+        SELECT c.checked, c.unverifiable, e.account_id, e.balance, e.expected_balance
+        FROM (
+            SELECT COUNT(*) AS checked, COUNT(*) FILTER (WHERE expected_balance IS NULL) AS unverifiable
+            FROM expected
+        ) c
+        LEFT JOIN expected e ON e.balance <> e.expected_balance
+        ORDER BY e.account_id`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("find discrepancies failed: %w", err)
+	}
+	defer rows.Close()
+
+	check := &model.BalanceCheck{}
+	for rows.Next() {
+		var (
+			accountID         sql.NullInt64
+			balance, expected sql.NullFloat64
+		)
+		if err := rows.Scan(&check.Checked, &check.Unverifiable, &accountID, &balance, &expected); err != nil {
+			return nil, fmt.Errorf("row scan failed: %w", err)
+		}
+		if accountID.Valid {
+			check.Discrepancies = append(check.Discrepancies, model.BalanceDiscrepancy{
+				AccountID:       accountID.Int64,
+				Balance:         balance.Float64,
+				ExpectedBalance: expected.Float64,
+			})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return check, nil
+}
+
+func (r *reconciliationRepository) Supply(ctx context.Context, mintAccountID int64, settlementAccountIDs []int64) (_ *model.Supply, err error) {
+	ctx, span := startSpan(ctx, "Supply", attribute.Int64("account.mint_id", mintAccountID))
+	defer func() { endSpan(span, err) }()
+
+	query := `
+        SELECT COUNT(*) FILTER (WHERE account_id <> $1 AND account_id <> ALL($2)),
+               COALESCE(SUM(balance) FILTER (WHERE account_id <> $1 AND account_id <> ALL($2)), 0),
+               COALESCE(-SUM(balance) FILTER (WHERE account_id = $1), 0),
+               COALESCE(-SUM(balance) FILTER (WHERE account_id = ANY($2)), 0)
+        FROM accounts`
+	var supply model.Supply
+	if err := r.db.QueryRowContext(ctx, query, mintAccountID, pq.Array(settlementAccountIDs)).
+		Scan(&supply.Accounts, &supply.Circulating, &supply.Minted, &supply.Settled); err != nil {
+		return nil, fmt.Errorf("get supply failed: %w", err)
+	}
+	return &supply, nil
+}
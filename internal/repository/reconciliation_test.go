@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"internal-transfers/internal/model"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconciliationRepository_FindDiscrepancies(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &reconciliationRepository{db: db}
+	ctx := context.Background()
+	columns := []string{"checked", "unverifiable", "account_id", "balance", "expected_balance"}
+
+	t.Run("discrepancies found", func(t *testing.T) {
+		// given
+		mock.ExpectQuery(`WITH expected AS`).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(5, 1, 2, 110.0, 100.0).
+				AddRow(5, 1, 4, 0.0, 20.0))
+
+		// when
+		check, err := repo.FindDiscrepancies(ctx)
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, &model.BalanceCheck{
+			Checked:      5,
+			Unverifiable: 1,
+			Discrepancies: []model.BalanceDiscrepancy{
+				{AccountID: 2, Balance: 110, ExpectedBalance: 100},
+				{AccountID: 4, Balance: 0, ExpectedBalance: 20},
+			},
+		}, check)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("all balances match", func(t *testing.T) {
+		// given
+		mock.ExpectQuery(`WITH expected AS`).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(5, 0, nil, nil, nil))
+
+		// when
+		check, err := repo.FindDiscrepancies(ctx)
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), check.Checked)
+		assert.Empty(t, check.Discrepancies)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		// given
+		mock.ExpectQuery(`WITH expected AS`).WillReturnError(assert.AnError)
+
+		// when
+		_, err := repo.FindDiscrepancies(ctx)
+
+		// then
+		assert.ErrorContains(t, err, "find discrepancies failed")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestReconciliationRepository_Supply(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &reconciliationRepository{db: db}
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		// given
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FILTER`).
+			WithArgs(int64(1000), pq.Array([]int64{9001})).
+			WillReturnRows(sqlmock.NewRows([]string{"accounts", "circulating", "minted", "settled"}).AddRow(3, 250.0, 200.0, 30.0))
+
+		// when
+		supply, err := repo.Supply(ctx, 1000, []int64{9001})
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, &model.Supply{Accounts: 3, Circulating: 250, Minted: 200, Settled: 30}, supply)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		// given
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FILTER`).WillReturnError(assert.AnError)
+
+		// when
+		_, err := repo.Supply(ctx, 1000, nil)
+
+		// then
+		assert.ErrorContains(t, err, "get supply failed")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
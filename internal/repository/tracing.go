@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"internal-transfers/internal/domain"
+)
+
+var tracer = otel.Tracer("internal-transfers/internal/repository")
+
+// startSpan starts a client span for a single query
+func startSpan(ctx context.Context, operation string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs, semconv.DBSystemPostgreSQL, semconv.DBOperationName(operation))
+	return tracer.Start(ctx, "repository."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+// endSpan records err on span, if it is an unexpected failure, and ends it
+func endSpan(span trace.Span, err error) {
+	if err != nil && !errors.Is(err, domain.ErrAccountNotFound) && !errors.Is(err, domain.ErrAccountDuplicate) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -3,69 +3,183 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
+	"internal-transfers/internal/domain"
 	"internal-transfers/internal/model"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // TransactionRepository defines db operations for transactions
 //
 //go:generate mockery --name=TransactionRepository --filename=transaction_mock.go --output=./mocks --with-expecter
 type TransactionRepository interface {
-	CreateTransaction(ctx context.Context, tx *sql.Tx, transaction *model.Transaction) error
+	CreateTransaction(ctx context.Context, transaction *model.Transaction) error
+	// CreateTransferGroup allocates the id of a multi-leg transfer, to be set on each of its transactions
+	CreateTransferGroup(ctx context.Context, createdAt time.Time) (int64, error)
 	GetTransaction(ctx context.Context, transactionID int64) (*model.Transaction, error)
-	ListTransactions(ctx context.Context) ([]*model.Transaction, error)
+	// ListTransactions returns the transactions matching filter in ascending id order
+	ListTransactions(ctx context.Context, filter TransactionFilter) ([]*model.Transaction, error)
+	// StreamStatement reads, from a single snapshot, the balance of an account just before from and then every
+	// transaction touching it in [from, to) oldest first, passing them to opening and entry as rows arrive
+	StreamStatement(ctx context.Context, accountID int64, from, to time.Time,
+		opening func(balance float64) error, entry func(tx *model.Transaction) error) error
+}
+
+// TransactionFilter narrows ListTransactions; zero fields match every transaction
+type TransactionFilter struct {
+	// AccountID matches transactions to or from the account
+	AccountID         int64
+	GroupID           int64
+	Types             []model.TransactionType
+	ExternalReference string
+	// Metadata matches transactions holding every one of its pairs
+	Metadata map[string]string
+	// From and To bound created_at to [From, To)
+	From, To time.Time
+	// AfterID resumes a listing after the last transaction of the previous page
+	AfterID int64
+	// Limit caps the number of transactions returned; 0 means no limit
+	Limit int
 }
 
+// externalReferenceConstraint keeps each external system from posting the same reference twice
+const externalReferenceConstraint = "transactions_external_reference_key"
+
+// transactionColumns are scanned by scanTransaction
+const transactionColumns = `transaction_id, source_account_id, destination_account_id, amount, type, description,
+        metadata, COALESCE(external_source, ''), COALESCE(external_reference, ''), created_at, COALESCE(group_id, 0)`
+
 type transactionRepository struct {
-	db *sql.DB
+	db querier
 }
 
 func NewTransactionRepository(db *sql.DB) TransactionRepository {
 	return &transactionRepository{db: db}
 }
 
-func (r *transactionRepository) CreateTransaction(ctx context.Context, tx *sql.Tx, transaction *model.Transaction) error {
+func (r *transactionRepository) CreateTransaction(ctx context.Context, transaction *model.Transaction) (err error) {
+	ctx, span := startSpan(ctx, "CreateTransaction",
+		attribute.Int64("account.source_id", transaction.SourceAccountID),
+		attribute.Int64("account.destination_id", transaction.DestinationAccountID),
+	)
+	defer func() { endSpan(span, err) }()
+
+	metadata, err := marshalMetadata(transaction.Metadata)
+	if err != nil {
+		return err
+	}
 	query := `
-        INSERT INTO transactions (source_account_id, destination_account_id, amount, created_at)
-        VALUES ($1, $2, $3, $4)
+        INSERT INTO transactions (source_account_id, destination_account_id, amount, type, description, metadata,
+            external_source, external_reference, created_at, group_id)
+        VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), NULLIF($8, ''), $9, NULLIF($10, 0))
         RETURNING transaction_id`
-	err := tx.QueryRowContext(ctx, query,
-		transaction.SourceAccountID, transaction.DestinationAccountID, transaction.Amount, transaction.CreatedAt).
+	err = r.db.QueryRowContext(ctx, query,
+		transaction.SourceAccountID, transaction.DestinationAccountID, transaction.Amount, transaction.Type,
+		transaction.Description, metadata, transaction.ExternalSource, transaction.ExternalReference,
+		transaction.CreatedAt, transaction.GroupID).
 		Scan(&transaction.TransactionID)
 	if err != nil {
+		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == pgerrcode.UniqueViolation &&
+			pgErr.Constraint == externalReferenceConstraint {
+			return domain.ErrDuplicateReference
+		}
 		return fmt.Errorf("create transaction failed: %w", err)
 	}
 	return nil
 }
 
-func (r *transactionRepository) GetTransaction(ctx context.Context, transactionID int64) (*model.Transaction, error) {
-	query := `
-        SELECT transaction_id, source_account_id, destination_account_id, amount, created_at
+func (r *transactionRepository) CreateTransferGroup(ctx context.Context, createdAt time.Time) (_ int64, err error) {
+	ctx, span := startSpan(ctx, "CreateTransferGroup")
+	defer func() { endSpan(span, err) }()
+
+	var groupID int64
+	query := `INSERT INTO transfer_groups (created_at) VALUES ($1) RETURNING group_id`
+	if err := r.db.QueryRowContext(ctx, query, createdAt).Scan(&groupID); err != nil {
+		return 0, fmt.Errorf("create transfer group failed: %w", err)
+	}
+	return groupID, nil
+}
+
+func (r *transactionRepository) GetTransaction(ctx context.Context, transactionID int64) (_ *model.Transaction, err error) {
+	ctx, span := startSpan(ctx, "GetTransaction", attribute.Int64("transaction.id", transactionID))
+	defer func() { endSpan(span, err) }()
+
+	query := `SELECT ` + transactionColumns + `
         FROM transactions
         WHERE transaction_id = $1`
-	row := r.db.QueryRowContext(ctx, query, transactionID)
-
-	var tx model.Transaction
-	if err := row.Scan(
-		&tx.TransactionID,
-		&tx.SourceAccountID,
-		&tx.DestinationAccountID,
-		&tx.Amount,
-		&tx.CreatedAt,
-	); err != nil {
+	tx, err := scanTransaction(r.db.QueryRowContext(ctx, query, transactionID))
+	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
 		return nil, fmt.Errorf("get transaction failed: %w", err)
 	}
-	return &tx, nil
+	return tx, nil
 }
 
-func (r *transactionRepository) ListTransactions(ctx context.Context) ([]*model.Transaction, error) {
-	query := `SELECT transaction_id, source_account_id, destination_account_id, amount, created_at
-			  FROM transactions`
-	rows, err := r.db.QueryContext(ctx, query)
+func (r *transactionRepository) ListTransactions(ctx context.Context, filter TransactionFilter) (_ []*model.Transaction, err error) {
+	ctx, span := startSpan(ctx, "ListTransactions", attribute.Int64("account.id", filter.AccountID))
+	defer func() { endSpan(span, err) }()
+
+	var (
+		conds []string
+		args  []any
+	)
+	where := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+	if filter.AccountID != 0 {
+		where("(source_account_id = $%[1]d OR destination_account_id = $%[1]d)", filter.AccountID)
+	}
+	if filter.GroupID != 0 {
+		where("group_id = $%d", filter.GroupID)
+	}
+	if len(filter.Types) > 0 {
+		types := make([]string, len(filter.Types))
+		for i, t := range filter.Types {
+			types[i] = string(t)
+		}
+		where("type = ANY($%d)", pq.Array(types))
+	}
+	if filter.ExternalReference != "" {
+		where("external_reference = $%d", filter.ExternalReference)
+	}
+	if len(filter.Metadata) > 0 {
+		metadata, err := marshalMetadata(filter.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		where("metadata @> $%d", metadata)
+	}
+	if !filter.From.IsZero() {
+		where("created_at >= $%d", filter.From)
+	}
+	if !filter.To.IsZero() {
+		where("created_at < $%d", filter.To)
+	}
+	if filter.AfterID != 0 {
+		where("transaction_id > $%d", filter.AfterID)
+	}
+
+	var query strings.Builder
+	query.WriteString(`SELECT ` + transactionColumns + ` FROM transactions`)
+	if len(conds) > 0 {
+		query.WriteString(" WHERE " + strings.Join(conds, " AND "))
+	}
+	query.WriteString(" ORDER BY transaction_id")
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		fmt.Fprintf(&query, " LIMIT $%d", len(args))
+	}
+	rows, err := r.db.QueryContext(ctx, query.String(), args...)
 	if err != nil {
 		return nil, fmt.Errorf("list transactions failed: %w", err)
 	}
@@ -73,17 +187,11 @@ func (r *transactionRepository) ListTransactions(ctx context.Context) ([]*model.
 
 	var transactions []*model.Transaction
 	for rows.Next() {
-		var tx model.Transaction
-		if err := rows.Scan(
-			&tx.TransactionID,
-			&tx.SourceAccountID,
-			&tx.DestinationAccountID,
-			&tx.Amount,
-			&tx.CreatedAt,
-		); err != nil {
+		tx, err := scanTransaction(rows)
+		if err != nil {
 			return nil, fmt.Errorf("row scan failed: %w", err)
 		}
-		transactions = append(transactions, &tx)
+		transactions = append(transactions, tx)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -91,3 +199,112 @@ func (r *transactionRepository) ListTransactions(ctx context.Context) ([]*model.
 	}
 	return transactions, nil
 }
+
+func (r *transactionRepository) StreamStatement(ctx context.Context, accountID int64, from, to time.Time,
+	opening func(balance float64) error, entry func(tx *model.Transaction) error) (err error) {
+	ctx, span := startSpan(ctx, "StreamStatement", attribute.Int64("account.id", accountID))
+	defer func() { endSpan(span, err) }()
+
+	// the opening balance is the current balance less everything that moved since from; computing it in the
+	// same statement as the entries keeps both consistent with concurrent transfers
+	query := `
+        WITH opening AS (
+            SELECT a.balance - COALESCE((
+                SELECT SUM(CASE WHEN t.destination_account_id = $1 THEN t.amount ELSE -t.amount END)
+                FROM transactions t
+                WHERE (t.source_account_id = $1 OR t.destination_account_id = $1) AND t.created_at >= $2
+            ), 0) AS balance
+            FROM accounts a
+            WHERE a.account_id = $1
+        )
+        SELECT o.balance, t.transaction_id, t.source_account_id, t.destination_account_id, t.amount, t.created_at
+        FROM opening o
+        LEFT JOIN transactions t
+            ON (t.source_account_id = $1 OR t.destination_account_id = $1) AND t.created_at >= $2 AND t.created_at < $3
+        ORDER BY t.created_at, t.transaction_id`
+	rows, err := r.db.QueryContext(ctx, query, accountID, from, to)
+	if err != nil {
+		return fmt.Errorf("stream statement failed: %w", err)
+	}
+	defer rows.Close()
+
+	first := true
+	for rows.Next() {
+		var (
+			balance                float64
+			txID, sourceID, destID sql.NullInt64
+			amount                 sql.NullFloat64
+			createdAt              sql.NullTime
+		)
+		if err := rows.Scan(&balance, &txID, &sourceID, &destID, &amount, &createdAt); err != nil {
+			return fmt.Errorf("row scan failed: %w", err)
+		}
+		if first {
+			if err := opening(balance); err != nil {
+				return err
+			}
+			first = false
+		}
+		if !txID.Valid {
+			continue // no transactions in the period
+		}
+		if err := entry(&model.Transaction{
+			TransactionID:        txID.Int64,
+			SourceAccountID:      sourceID.Int64,
+			DestinationAccountID: destID.Int64,
+			Amount:               amount.Float64,
+			CreatedAt:            createdAt.Time,
+		}); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("rows iteration error: %w", err)
+	}
+	if first {
+		return domain.ErrAccountNotFound
+	}
+	return nil
+}
+
+// scanTransaction reads a row selecting transactionColumns
+func scanTransaction(row interface{ Scan(dest ...any) error }) (*model.Transaction, error) {
+	var (
+		tx       model.Transaction
+		metadata []byte
+	)
+	if err := row.Scan(
+		&tx.TransactionID,
+		&tx.SourceAccountID,
+		&tx.DestinationAccountID,
+		&tx.Amount,
+		&tx.Type,
+		&tx.Description,
+		&metadata,
+		&tx.ExternalSource,
+		&tx.ExternalReference,
+		&tx.CreatedAt,
+		&tx.GroupID,
+	); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(metadata, &tx.Metadata); err != nil {
+		return nil, fmt.Errorf("invalid metadata: %w", err)
+	}
+	if len(tx.Metadata) == 0 {
+		tx.Metadata = nil
+	}
+	return &tx, nil
+}
+
+// marshalMetadata encodes metadata for a JSONB column, storing an empty object when there is none
+func marshalMetadata(metadata map[string]string) ([]byte, error) {
+	if metadata == nil {
+		return []byte("{}"), nil
+	}
+	b, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("encode metadata: %w", err)
+	}
+	return b, nil
+}
@@ -3,12 +3,16 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"testing"
 	"time"
 
+	"internal-transfers/internal/domain"
 	"internal-transfers/internal/model"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgerrcode"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -26,35 +30,48 @@ func TestTransactionRepository_CreateTransaction(t *testing.T) {
 		SourceAccountID:      1,
 		DestinationAccountID: 2,
 		Amount:               50.0,
+		Type:                 model.TransactionDeposit,
+		Description:          "top up",
+		Metadata:             map[string]string{"channel": "app"},
+		ExternalSource:       "bank",
+		ExternalReference:    "ref-1",
 		CreatedAt:            time.Now(),
 	}
+	args := []driver.Value{transaction.SourceAccountID, transaction.DestinationAccountID, transaction.Amount,
+		transaction.Type, transaction.Description, []byte(`{"channel":"app"}`), transaction.ExternalSource,
+		transaction.ExternalReference, transaction.CreatedAt, transaction.GroupID}
 
 	t.Run("success", func(t *testing.T) {
-		mock.ExpectBegin()
-		txObj, err := db.Begin()
-		require.NoError(t, err)
-
 		mock.ExpectQuery(`INSERT INTO transactions`).
-			WithArgs(transaction.SourceAccountID, transaction.DestinationAccountID, transaction.Amount, transaction.CreatedAt).
+			WithArgs(args...).
 			WillReturnRows(sqlmock.NewRows([]string{"transaction_id"}).AddRow(123))
 
-		err = repo.CreateTransaction(ctx, txObj, transaction)
+		err := repo.CreateTransaction(ctx, transaction)
 		assert.NoError(t, err)
 		assert.Equal(t, int64(123), transaction.TransactionID)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
-	t.Run("db error", func(t *testing.T) {
-		mock.ExpectBegin()
-		txObj, err := db.Begin()
-		require.NoError(t, err)
+	t.Run("duplicate external reference", func(t *testing.T) {
+		mock.ExpectQuery(`INSERT INTO transactions`).
+			WithArgs(args...).
+			WillReturnError(&pq.Error{Code: pgerrcode.UniqueViolation, Constraint: externalReferenceConstraint})
+
+		// when
+		err := repo.CreateTransaction(ctx, transaction)
 
+		// then
+		assert.ErrorIs(t, err, domain.ErrDuplicateReference)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("db error", func(t *testing.T) {
 		mock.ExpectQuery(`INSERT INTO transactions`).
-			WithArgs(transaction.SourceAccountID, transaction.DestinationAccountID, transaction.Amount, transaction.CreatedAt).
+			WithArgs(args...).
 			WillReturnError(assert.AnError)
 
 		// when
-		err = repo.CreateTransaction(ctx, txObj, transaction)
+		err := repo.CreateTransaction(ctx, transaction)
 
 		// then
 		assert.Error(t, err)
@@ -63,6 +80,27 @@ func TestTransactionRepository_CreateTransaction(t *testing.T) {
 	})
 }
 
+func TestTransactionRepository_CreateTransferGroup(t *testing.T) {
+	// given
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &transactionRepository{db: db}
+	at := time.Now()
+	mock.ExpectQuery(`INSERT INTO transfer_groups \(created_at\) VALUES \(\$1\) RETURNING group_id`).
+		WithArgs(at).
+		WillReturnRows(sqlmock.NewRows([]string{"group_id"}).AddRow(9))
+
+	// when
+	groupID, err := repo.CreateTransferGroup(context.Background(), at)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, int64(9), groupID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestTransactionRepository_GetTransaction(t *testing.T) {
 	// given
 	db, mock, err := sqlmock.New()
@@ -80,10 +118,16 @@ func TestTransactionRepository_GetTransaction(t *testing.T) {
 			"source_account_id",
 			"destination_account_id",
 			"amount",
+			"type",
+			"description",
+			"metadata",
+			"external_source",
+			"external_reference",
 			"created_at",
-		}).AddRow(transactionID, 1, 2, 100.0, now)
+			"group_id",
+		}).AddRow(transactionID, 1, 2, 100.0, "deposit", "top up", []byte(`{"channel":"app"}`), "bank", "ref-1", now, 0)
 
-		mock.ExpectQuery(`SELECT transaction_id, source_account_id, destination_account_id, amount, created_at FROM transactions WHERE transaction_id = \$1`).
+		mock.ExpectQuery(`SELECT transaction_id, source_account_id, destination_account_id, amount, type,(.|\n)+FROM transactions WHERE transaction_id = \$1`).
 			WithArgs(transactionID).
 			WillReturnRows(rows)
 
@@ -94,13 +138,18 @@ func TestTransactionRepository_GetTransaction(t *testing.T) {
 		assert.Equal(t, int64(1), tx.SourceAccountID)
 		assert.Equal(t, int64(2), tx.DestinationAccountID)
 		assert.Equal(t, 100.0, tx.Amount)
+		assert.Equal(t, model.TransactionDeposit, tx.Type)
+		assert.Equal(t, "bank", tx.ExternalSource)
+		assert.Equal(t, "ref-1", tx.ExternalReference)
+		assert.Equal(t, "top up", tx.Description)
+		assert.Equal(t, map[string]string{"channel": "app"}, tx.Metadata)
 		assert.WithinDuration(t, now, tx.CreatedAt, time.Second)
 
 		require.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("not found", func(t *testing.T) {
-		mock.ExpectQuery(`SELECT transaction_id, source_account_id, destination_account_id, amount, created_at FROM transactions WHERE transaction_id = \$1`).
+		mock.ExpectQuery(`SELECT transaction_id, source_account_id, destination_account_id, amount, type,(.|\n)+FROM transactions WHERE transaction_id = \$1`).
 			WithArgs(transactionID).
 			WillReturnError(sql.ErrNoRows)
 
@@ -112,7 +161,7 @@ func TestTransactionRepository_GetTransaction(t *testing.T) {
 	})
 
 	t.Run("db error", func(t *testing.T) {
-		mock.ExpectQuery(`SELECT transaction_id, source_account_id, destination_account_id, amount, created_at FROM transactions WHERE transaction_id = \$1`).
+		mock.ExpectQuery(`SELECT transaction_id, source_account_id, destination_account_id, amount, type,(.|\n)+FROM transactions WHERE transaction_id = \$1`).
 			WithArgs(transactionID).
 			WillReturnError(assert.AnError)
 
@@ -141,28 +190,57 @@ func TestTransactionRepository_ListTransactions(t *testing.T) {
 			"source_account_id",
 			"destination_account_id",
 			"amount",
+			"type",
+			"description",
+			"metadata",
+			"external_source",
+			"external_reference",
 			"created_at",
+			"group_id",
 		}).
-			AddRow(1, 1, 2, 100.0, now).
-			AddRow(2, 3, 4, 200.0, now.Add(time.Minute))
+			AddRow(1, 1, 2, 100.0, "transfer", "", []byte(`{}`), "", "", now, 0).
+			AddRow(2, 3, 4, 200.0, "transfer", "", []byte(`{}`), "", "", now.Add(time.Minute), 3)
 
-		mock.ExpectQuery(`SELECT transaction_id, source_account_id, destination_account_id, amount, created_at FROM transactions`).
+		mock.ExpectQuery(`SELECT transaction_id, source_account_id, destination_account_id, amount, type,(.|\n)+FROM transactions`).
 			WillReturnRows(rows)
 
-		txs, err := repo.ListTransactions(ctx)
+		txs, err := repo.ListTransactions(ctx, TransactionFilter{})
 		assert.NoError(t, err)
 		assert.Len(t, txs, 2)
 		assert.Equal(t, int64(1), txs[0].TransactionID)
 		assert.Equal(t, int64(2), txs[1].TransactionID)
+		assert.Equal(t, int64(3), txs[1].GroupID)
+
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		mock.ExpectQuery(`FROM transactions WHERE \(source_account_id = \$1 OR destination_account_id = \$1\) AND group_id = \$2 AND type = ANY\(\$3\) AND external_reference = \$4 AND metadata @> \$5 AND created_at >= \$6 AND transaction_id > \$7 ORDER BY transaction_id LIMIT \$8`).
+			WithArgs(int64(1), int64(4), pq.Array([]string{"payroll"}), "inv-7", []byte(`{"run":"2025-01"}`), from, int64(10), 50).
+			WillReturnRows(sqlmock.NewRows(nil))
+
+		txs, err := repo.ListTransactions(ctx, TransactionFilter{
+			AccountID:         1,
+			GroupID:           4,
+			Types:             []model.TransactionType{model.TransactionPayroll},
+			ExternalReference: "inv-7",
+			Metadata:          map[string]string{"run": "2025-01"},
+			From:              from,
+			AfterID:           10,
+			Limit:             50,
+		})
+		assert.NoError(t, err)
+		assert.Empty(t, txs)
 
 		require.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("db query error", func(t *testing.T) {
-		mock.ExpectQuery(`SELECT transaction_id, source_account_id, destination_account_id, amount, created_at FROM transactions`).
+		mock.ExpectQuery(`SELECT transaction_id, source_account_id, destination_account_id, amount, type,(.|\n)+FROM transactions`).
 			WillReturnError(assert.AnError)
 
-		txs, err := repo.ListTransactions(ctx)
+		txs, err := repo.ListTransactions(ctx, TransactionFilter{})
 		assert.Error(t, err)
 		assert.Nil(t, txs)
 		assert.Contains(t, err.Error(), "list transactions failed")
@@ -170,3 +248,73 @@ func TestTransactionRepository_ListTransactions(t *testing.T) {
 		require.NoError(t, mock.ExpectationsWereMet())
 	})
 }
+
+func TestTransactionRepository_StreamStatement(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &transactionRepository{db: db}
+	ctx := context.Background()
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	columns := []string{"balance", "transaction_id", "source_account_id", "destination_account_id", "amount", "created_at"}
+
+	collect := func() (*float64, []*model.Transaction, error) {
+		var opening *float64
+		var entries []*model.Transaction
+		err := repo.StreamStatement(ctx, 1, from, to,
+			func(balance float64) error { opening = &balance; return nil },
+			func(tx *model.Transaction) error { entries = append(entries, tx); return nil },
+		)
+		return opening, entries, err
+	}
+
+	t.Run("opening balance and entries", func(t *testing.T) {
+		// given
+		mock.ExpectQuery(`WITH opening AS`).
+			WithArgs(int64(1), from, to).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(100.0, 7, 1, 2, 30.0, from.Add(time.Hour)).
+				AddRow(100.0, 8, 3, 1, 5.0, from.Add(2*time.Hour)))
+
+		// when
+		opening, entries, err := collect()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 100.0, *opening)
+		require.Len(t, entries, 2)
+		assert.Equal(t, int64(7), entries[0].TransactionID)
+		assert.Equal(t, int64(3), entries[1].SourceAccountID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("no transactions in period", func(t *testing.T) {
+		// given
+		mock.ExpectQuery(`WITH opening AS`).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(42.0, nil, nil, nil, nil, nil))
+
+		// when
+		opening, entries, err := collect()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 42.0, *opening)
+		assert.Empty(t, entries)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("account not found", func(t *testing.T) {
+		// given
+		mock.ExpectQuery(`WITH opening AS`).WillReturnRows(sqlmock.NewRows(columns))
+
+		// when
+		opening, _, err := collect()
+
+		// then
+		assert.Nil(t, opening)
+		assert.ErrorIs(t, err, domain.ErrAccountNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
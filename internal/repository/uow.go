@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+
+	"internal-transfers/internal/domain"
+	"internal-transfers/internal/metrics"
+)
+
+// IsolationLevel is the transaction isolation level used by a UnitOfWork
+type IsolationLevel string
+
+const (
+	IsolationReadCommitted  IsolationLevel = "read_committed"
+	IsolationRepeatableRead IsolationLevel = "repeatable_read"
+	IsolationSerializable   IsolationLevel = "serializable"
+)
+
+// TxOptions configures every unit of work started by a UnitOfWork
+type TxOptions struct {
+	Isolation IsolationLevel
+	// MaxRetries is how many times a unit of work aborted by a serialization failure or deadlock is re-run
+	MaxRetries int
+	// RetryBackoff caps the random wait before the first retry; the cap doubles on every retry up to MaxRetryBackoff
+	RetryBackoff    time.Duration
+	MaxRetryBackoff time.Duration
+}
+
+// Repositories are the repositories bound to a single unit of work
+type Repositories struct {
+	Accounts     AccountRepository
+	Transactions TransactionRepository
+	Balances     BalanceRepository
+	Periods      PeriodRepository
+	Interest     InterestRepository
+}
+
+// UnitOfWork runs a group of repository operations atomically
+//
+//go:generate mockery --name=UnitOfWork --filename=uow_mock.go --output=./mocks --with-expecter
+type UnitOfWork interface {
+	// WithinTx calls fn with repositories bound to a new unit of work, committing if fn returns nil and
+	// rolling back if it returns an error or panics. fn may be called more than once if the unit of work
+	// is retried, so it must not have side effects outside of repos.
+	WithinTx(ctx context.Context, fn func(ctx context.Context, repos Repositories) error) error
+}
+
+// querier is satisfied by both *sql.DB and *sql.Tx so repositories can run inside or outside a transaction
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// sqlUnitOfWork is the Postgres implementation, backed by database transactions
+type sqlUnitOfWork struct {
+	db   *sql.DB
+	opts TxOptions
+}
+
+func NewUnitOfWork(db *sql.DB, opts TxOptions) UnitOfWork {
+	return &sqlUnitOfWork{db: db, opts: opts}
+}
+
+// WithinTx returns an error wrapping domain.ErrConcurrentUpdate if fn is still aborted by concurrent updates
+// after MaxRetries retries
+func (u *sqlUnitOfWork) WithinTx(ctx context.Context, fn func(ctx context.Context, repos Repositories) error) error {
+	backoff := u.opts.RetryBackoff
+	for attempt := 1; ; attempt++ {
+		err := u.runTx(ctx, fn)
+		reason, ok := retryReason(err)
+		if !ok {
+			return err
+		}
+		if attempt > u.opts.MaxRetries {
+			metrics.ObserveDBTxRetriesExhausted()
+			log.Ctx(ctx).Warn().Err(err).Int("attempts", attempt).Msg("unit of work retries exhausted")
+			return fmt.Errorf("%w: gave up after %d attempts: %w", domain.ErrConcurrentUpdate, attempt, err)
+		}
+
+		wait := jitter(backoff)
+		metrics.ObserveDBTxRetry(reason)
+		log.Ctx(ctx).Warn().Err(err).Int("attempt", attempt).Str("reason", reason).Dur("retry_in", wait).
+			Msg("unit of work aborted by concurrent update, retrying")
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("gave up retrying unit of work: %w", ctx.Err())
+		case <-time.After(wait):
+		}
+		backoff = min(backoff*2, u.opts.MaxRetryBackoff)
+	}
+}
+
+func (u *sqlUnitOfWork) runTx(ctx context.Context, fn func(ctx context.Context, repos Repositories) error) error {
+	tx, err := u.db.BeginTx(ctx, &sql.TxOptions{Isolation: u.opts.Isolation.sqlLevel()})
+	if err != nil {
+		return fmt.Errorf("begin transaction failed: %w", err)
+	}
+	start := time.Now()
+
+	// rollback aborts tx and records it; errors are ignored as the original failure is what matters
+	rollback := func() {
+		_ = tx.Rollback()
+		metrics.ObserveDBTx(metrics.TxRollback, time.Since(start))
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			rollback()
+			panic(p)
+		}
+	}()
+
+	repos := Repositories{
+		Accounts:     &accountRepository{db: tx, serializable: u.opts.Isolation == IsolationSerializable},
+		Transactions: &transactionRepository{db: tx},
+		Balances:     &balanceRepository{db: tx},
+		Periods:      &periodRepository{db: tx},
+		Interest:     &interestRepository{db: tx},
+	}
+	if err := fn(ctx, repos); err != nil {
+		rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		metrics.ObserveDBTx(metrics.TxRollback, time.Since(start))
+		return fmt.Errorf("transaction commit failed: %w", err)
+	}
+	metrics.ObserveDBTx(metrics.TxCommit, time.Since(start))
+	return nil
+}
+
+func (l IsolationLevel) sqlLevel() sql.IsolationLevel {
+	switch l {
+	case IsolationRepeatableRead:
+		return sql.LevelRepeatableRead
+	case IsolationSerializable:
+		return sql.LevelSerializable
+	default:
+		return sql.LevelReadCommitted
+	}
+}
+
+// retryReason reports whether err aborted a transaction that may succeed if run again, and why
+func retryReason(err error) (string, bool) {
+	var pgErr *pq.Error
+	if !errors.As(err, &pgErr) {
+		return "", false
+	}
+	switch pgErr.Code {
+	case pgerrcode.SerializationFailure:
+		return metrics.RetrySerializationFailure, true
+	case pgerrcode.DeadlockDetected:
+		return metrics.RetryDeadlock, true
+	default:
+		return "", false
+	}
+}
+
+// jitter returns a random wait below d so transactions aborted by the same conflict do not retry in lockstep
+func jitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	return rand.N(d)
+}
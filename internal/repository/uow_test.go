@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"internal-transfers/internal/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgerrcode"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnitOfWork_WithinTx(t *testing.T) {
+	ctx := context.Background()
+
+	newUnitOfWork := func(t *testing.T, opts TxOptions) (UnitOfWork, sqlmock.Sqlmock) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+		return NewUnitOfWork(db, opts), mock
+	}
+
+	t.Run("commits when fn succeeds", func(t *testing.T) {
+		// given
+		uow, mock := newUnitOfWork(t, TxOptions{})
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE accounts SET balance =`).
+			WithArgs(50.0, int64(1)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		// when
+		err := uow.WithinTx(ctx, func(ctx context.Context, repos Repositories) error {
+			return repos.Accounts.UpdateBalance(ctx, 1, 50)
+		})
+
+		// then
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rolls back when fn fails", func(t *testing.T) {
+		// given
+		uow, mock := newUnitOfWork(t, TxOptions{MaxRetries: 3})
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		// when
+		err := uow.WithinTx(ctx, func(ctx context.Context, repos Repositories) error {
+			return assert.AnError
+		})
+
+		// then
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rolls back and re-panics when fn panics", func(t *testing.T) {
+		// given
+		uow, mock := newUnitOfWork(t, TxOptions{})
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		// when
+		fn := func() {
+			_ = uow.WithinTx(ctx, func(ctx context.Context, repos Repositories) error {
+				panic("boom")
+			})
+		}
+
+		// then
+		assert.PanicsWithValue(t, "boom", fn)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("retries serialization failures", func(t *testing.T) {
+		// given
+		uow, mock := newUnitOfWork(t, TxOptions{Isolation: IsolationSerializable, MaxRetries: 2, RetryBackoff: time.Millisecond, MaxRetryBackoff: time.Millisecond})
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		// when
+		calls := 0
+		err := uow.WithinTx(ctx, func(ctx context.Context, repos Repositories) error {
+			calls++
+			if calls == 1 {
+				return &pq.Error{Code: pgerrcode.SerializationFailure}
+			}
+			return nil
+		})
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, 2, calls)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("gives up after max retries", func(t *testing.T) {
+		// given
+		uow, mock := newUnitOfWork(t, TxOptions{MaxRetries: 1})
+		for range 2 {
+			mock.ExpectBegin()
+			mock.ExpectRollback()
+		}
+
+		// when
+		calls := 0
+		err := uow.WithinTx(ctx, func(ctx context.Context, repos Repositories) error {
+			calls++
+			return &pq.Error{Code: pgerrcode.DeadlockDetected}
+		})
+
+		// then
+		var pgErr *pq.Error
+		assert.ErrorIs(t, err, domain.ErrConcurrentUpdate)
+		assert.ErrorAs(t, err, &pgErr)
+		assert.Equal(t, 2, calls)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestUnitOfWork_WithinTx_Serializable(t *testing.T) {
+	// given
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	uow := NewUnitOfWork(db, TxOptions{Isolation: IsolationSerializable})
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT account_id, balance, type, status, name, owner, labels, version, created_at, updated_at FROM accounts WHERE account_id = \$1$`).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows(accountRowColumns).AddRow(1, 100.0, "customer", "open", "", "", []byte("{}"), 1, time.Now(), time.Now()))
+	mock.ExpectCommit()
+
+	// when
+	err = uow.WithinTx(context.Background(), func(ctx context.Context, repos Repositories) error {
+		_, err := repos.Accounts.GetAccountForUpdate(ctx, 1)
+		return err
+	})
+
+	// then
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestJitter(t *testing.T) {
+	assert.Zero(t, jitter(0))
+	for range 100 {
+		d := jitter(10 * time.Millisecond)
+		assert.GreaterOrEqual(t, d, time.Duration(0))
+		assert.Less(t, d, 10*time.Millisecond)
+	}
+}
+
+func TestIsolationLevel_sqlLevel(t *testing.T) {
+	assert.Equal(t, sql.LevelReadCommitted, IsolationReadCommitted.sqlLevel())
+	assert.Equal(t, sql.LevelRepeatableRead, IsolationRepeatableRead.sqlLevel())
+	assert.Equal(t, sql.LevelSerializable, IsolationSerializable.sqlLevel())
+}
@@ -2,44 +2,376 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"internal-transfers/internal/domain"
+	"internal-transfers/internal/importer"
+	"maps"
+	"slices"
+	"time"
+	"unicode/utf8"
 
 	"internal-transfers/internal/model"
 	"internal-transfers/internal/repository"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 //go:generate mockery --name=AccountService --filename=account_mock.go --output=./mocks --with-expecter
 type AccountService interface {
-	CreateAccount(ctx context.Context, accountID int64, balance float64) error
+	// CreateAccount opens an account and returns it. A zero accountID has the server allocate the id.
+	CreateAccount(ctx context.Context, accountID int64, balance float64, accountType model.AccountType, details AccountDetails) (*model.Account, error)
 	GetAccount(ctx context.Context, accountID int64) (*model.Account, error)
+	// UpdateAccount applies update to the details of an account and returns the account at its new version.
+	// A non-zero version makes the update conditional on the account still being at that version.
+	UpdateAccount(ctx context.Context, accountID int64, update AccountUpdate, version int64) (*model.Account, error)
+	ListAccounts(ctx context.Context, filter repository.AccountFilter) ([]*model.Account, error)
+	ImportAccounts(ctx context.Context, records []importer.Record, dryRun bool) (*ImportReport, error)
+}
+
+// Limits on the details callers attach to an account
+const (
+	maxAccountNameLength  = 200
+	maxAccountOwnerLength = 128
+	maxAccountLabelsBytes = 4096
+)
+
+// AccountDetails describes an account beyond its balance and type; all fields are optional
+type AccountDetails struct {
+	Name   string
+	Owner  string
+	Labels map[string]string
+}
+
+func (d AccountDetails) validate() error {
+	if utf8.RuneCountInString(d.Name) > maxAccountNameLength {
+		return fmt.Errorf("%w: name is longer than %d characters", domain.ErrInvalidAccount, maxAccountNameLength)
+	}
+	if len(d.Owner) > maxAccountOwnerLength {
+		return fmt.Errorf("%w: owner is longer than %d bytes", domain.ErrInvalidAccount, maxAccountOwnerLength)
+	}
+	if _, ok := d.Labels[""]; ok {
+		return fmt.Errorf("%w: label keys must not be empty", domain.ErrInvalidAccount)
+	}
+	if len(d.Labels) > 0 {
+		b, err := json.Marshal(d.Labels)
+		if err != nil || len(b) > maxAccountLabelsBytes {
+			return fmt.Errorf("%w: labels are larger than %d bytes", domain.ErrInvalidAccount, maxAccountLabelsBytes)
+		}
+	}
+	return nil
+}
+
+// AccountUpdate changes the details of an account; nil fields are left as they are
+type AccountUpdate struct {
+	Name  *string
+	Owner *string
+	// Labels are merged into the account's labels, removing those whose value is nil
+	Labels map[string]*string
+	// Status closes or reopens the account; only accounts without a balance may be closed
+	Status *model.AccountStatus
+}
+
+// apply returns the details of acc once updated
+func (u AccountUpdate) apply(acc *model.Account) AccountDetails {
+	details := AccountDetails{Name: acc.Name, Owner: acc.Owner, Labels: maps.Clone(acc.Labels)}
+	if u.Name != nil {
+		details.Name = *u.Name
+	}
+	if u.Owner != nil {
+		details.Owner = *u.Owner
+	}
+	for key, value := range u.Labels {
+		if value == nil {
+			delete(details.Labels, key)
+			continue
+		}
+		if details.Labels == nil {
+			details.Labels = make(map[string]string)
+		}
+		details.Labels[key] = *value
+	}
+	return details
+}
+
+// status returns the status of acc once updated, or why it may not change to it
+func (u AccountUpdate) status(acc *model.Account) (model.AccountStatus, error) {
+	if u.Status == nil || *u.Status == acc.Status {
+		return acc.Status, nil
+	}
+	switch status := *u.Status; {
+	case !slices.Contains(model.AccountStatuses, status):
+		return "", fmt.Errorf("%w: unknown status %q", domain.ErrInvalidAccount, status)
+	case status != model.AccountClosed:
+		return status, nil
+	case acc.LedgerOwned():
+		return "", fmt.Errorf("%w: the ledger's own accounts cannot be closed", domain.ErrInvalidAccount)
+	case cents(acc.Balance) != 0:
+		return "", fmt.Errorf("%w: only accounts with a zero balance can be closed", domain.ErrInvalidAccount)
+	default:
+		return status, nil
+	}
+}
+
+// MintPolicy selects the system account that funds new accounts and the callers allowed to draw on it.
+// The zero value disables minting, so new accounts start with an unfunded balance.
+type MintPolicy struct {
+	AccountID      int64
+	AllowedCallers []string
+}
+
+func (p MintPolicy) enabled() bool {
+	return p.AccountID != 0
+}
+
+// authorize checks that the caller in ctx may fund accounts from the mint
+func (p MintPolicy) authorize(ctx context.Context) error {
+	if !slices.Contains(p.AllowedCallers, domain.CallerFromContext(ctx)) {
+		return domain.ErrMintNotAllowed
+	}
+	return nil
+}
+
+// AccountIDPolicy decides who picks the ids of new accounts. The server allocates one whenever the caller
+// leaves it out; callers may only choose their own, up to model.MaxCallerAccountID, while CallerChosen is set.
+type AccountIDPolicy struct {
+	CallerChosen bool
+}
+
+// check reports why a caller may not choose id, if they may not
+func (p AccountIDPolicy) check(id int64) error {
+	if !p.CallerChosen {
+		return fmt.Errorf("%w: account ids are allocated by the server", domain.ErrInvalidAccount)
+	}
+	if id < 1 || id > model.MaxCallerAccountID {
+		return fmt.Errorf("%w: account_id must be between 1 and %d", domain.ErrInvalidAccount, model.MaxCallerAccountID)
+	}
+	return nil
 }
 
+// maxAllocationAttempts bounds how many ids CreateAccount allocates for one account. Another attempt is only
+// needed when an allocated id is already taken, as it can be by an account opened before ids were allocated.
+const maxAllocationAttempts = 3
+
 type accountService struct {
 	repo repository.AccountRepository
+	uow  repository.UnitOfWork
+	mint MintPolicy
+	ids  AccountIDPolicy
 }
 
-func NewAccountService(repo repository.AccountRepository) AccountService {
-	return &accountService{repo: repo}
+func NewAccountService(repo repository.AccountRepository, uow repository.UnitOfWork, mint MintPolicy, ids AccountIDPolicy) AccountService {
+	return &accountService{repo: repo, uow: uow, mint: mint, ids: ids}
 }
 
-// CreateAccount creates a new account with initial balance; assumes negative balance is not allowed
-func (s *accountService) CreateAccount(ctx context.Context, accountID int64, initialBalance float64) error {
-	if initialBalance <= 0 {
-		return domain.ErrInsufficientFunds
+// CreateAccount creates a new account of the given type, customer when empty, with initial balance. Customer
+// accounts must open with a positive balance, other types may open empty, and none may open negative. While
+// minting is enabled the account opens empty and the balance is transferred to it from the mint account.
+func (s *accountService) CreateAccount(ctx context.Context, accountID int64, initialBalance float64, accountType model.AccountType,
+	details AccountDetails) (*model.Account, error) {
+	if err := details.validate(); err != nil {
+		return nil, err
+	}
+	if accountID != 0 {
+		if err := s.ids.check(accountID); err != nil {
+			return nil, err
+		}
+	}
+	if accountType == "" {
+		accountType = model.AccountCustomer
+	}
+	if rule, ok := accountType.Rule(); !ok || rule.Reserved {
+		return nil, fmt.Errorf("%w: %q", domain.ErrInvalidAccountType, accountType)
 	}
+	if initialBalance < 0 || initialBalance == 0 && accountType == model.AccountCustomer {
+		return nil, domain.ErrInsufficientFunds
+	}
+
+	ctx, span := tracer.Start(ctx, "AccountService.CreateAccount", trace.WithAttributes(
+		attribute.String("account.type", string(accountType)),
+		attribute.Bool("account.minted", s.mint.enabled()),
+		attribute.Bool("account.id_allocated", accountID == 0),
+	))
 
-	acc := &model.Account{
+	account := &model.Account{
 		AccountID: accountID,
-		Balance:   initialBalance,
+		Type:      accountType,
+		Name:      details.Name,
+		Owner:     details.Owner,
+		Labels:    details.Labels,
+	}
+	var err error
+	if s.mint.enabled() && initialBalance > 0 {
+		err = s.mint.authorize(ctx)
+	}
+	if err == nil {
+		err = s.open(ctx, account, initialBalance)
+	}
+	span.SetAttributes(attribute.Int64("account.id", account.AccountID))
+	outcome := accountOutcome(err)
+	endSpan(span, outcome, err, outcome == "error")
+	if err != nil {
+		return nil, err
+	}
+	account.Balance = initialBalance
+	return account, nil
+}
+
+// open creates account, allocating its id first when it has none
+func (s *accountService) open(ctx context.Context, account *model.Account, initialBalance float64) error {
+	if account.AccountID != 0 {
+		return s.insert(ctx, account, initialBalance)
+	}
+	for attempt := 1; ; attempt++ {
+		id, err := s.repo.NextAccountID(ctx)
+		if err != nil {
+			return err
+		}
+		account.AccountID = id
+		err = s.insert(ctx, account, initialBalance)
+		if !errors.Is(err, domain.ErrAccountDuplicate) || attempt == maxAllocationAttempts {
+			return err
+		}
 	}
-	return s.repo.CreateAccount(ctx, acc)
+}
+
+// insert creates account with initial balance, funding it from the mint while minting is enabled
+func (s *accountService) insert(ctx context.Context, account *model.Account, initialBalance float64) error {
+	if !s.mint.enabled() || initialBalance == 0 {
+		account.Balance = initialBalance
+		return s.repo.CreateAccount(ctx, account)
+	}
+	return s.uow.WithinTx(ctx, func(ctx context.Context, repos repository.Repositories) error {
+		if err := repos.Accounts.CreateAccount(ctx, account); err != nil {
+			return err
+		}
+		return mint(ctx, repos, s.mint.AccountID, []*model.Account{{AccountID: account.AccountID, Balance: initialBalance}})
+	})
 }
 
 // GetAccount retrieves account details by ID
 func (s *accountService) GetAccount(ctx context.Context, accountID int64) (*model.Account, error) {
+	ctx, span := tracer.Start(ctx, "AccountService.GetAccount", trace.WithAttributes(
+		attribute.Int64("account.id", accountID),
+	))
+
 	acc, err := s.repo.GetAccount(ctx, accountID)
+	outcome := accountOutcome(err)
+	endSpan(span, outcome, err, outcome == "error")
 	if err != nil {
 		return nil, err
 	}
 	return acc, nil
 }
+
+// UpdateAccount holds the account's row lock while it merges the update, so concurrent unconditional updates
+// apply one after the other instead of overwriting each other
+func (s *accountService) UpdateAccount(ctx context.Context, accountID int64, update AccountUpdate, version int64) (*model.Account, error) {
+	ctx, span := tracer.Start(ctx, "AccountService.UpdateAccount", trace.WithAttributes(
+		attribute.Int64("account.id", accountID),
+		attribute.Int64("account.version", version),
+	))
+
+	var acc *model.Account
+	err := s.uow.WithinTx(ctx, func(ctx context.Context, repos repository.Repositories) error {
+		var err error
+		acc, err = repos.Accounts.GetAccountForUpdate(ctx, accountID)
+		if err != nil {
+			return err
+		}
+		if version != 0 && acc.Version != version {
+			return fmt.Errorf("%w: account is at version %d", domain.ErrAccountVersionMismatch, acc.Version)
+		}
+		details := update.apply(acc)
+		if err := details.validate(); err != nil {
+			return err
+		}
+		status, err := update.status(acc)
+		if err != nil {
+			return err
+		}
+		acc.Name, acc.Owner, acc.Labels, acc.Status = details.Name, details.Owner, details.Labels, status
+		return repos.Accounts.UpdateDetails(ctx, acc)
+	})
+	outcome := accountOutcome(err)
+	endSpan(span, outcome, err, outcome == "error")
+	if err != nil {
+		return nil, err
+	}
+	return acc, nil
+}
+
+// mint funds each of accounts, which must have just been created with a zero balance, with its Balance from
+// the mint account. The mint account is the only one allowed to go negative: its balance is less the total
+// ever minted.
+func mint(ctx context.Context, repos repository.Repositories, mintAccountID int64, accounts []*model.Account) error {
+	mintAcc, err := repos.Accounts.GetAccountForUpdate(ctx, mintAccountID)
+	if err != nil {
+		return fmt.Errorf("failed to lock mint account: %w", err)
+	}
+	if mintAcc == nil {
+		return fmt.Errorf("mint account %d does not exist", mintAccountID)
+	}
+
+	now := time.Now()
+	total := 0.0
+	for _, acc := range accounts {
+		if err := repos.Accounts.UpdateBalance(ctx, acc.AccountID, acc.Balance); err != nil {
+			return fmt.Errorf("failed to update account balance: %w", err)
+		}
+		transaction := &model.Transaction{
+			SourceAccountID:      mintAccountID,
+			DestinationAccountID: acc.AccountID,
+			Amount:               acc.Balance,
+			Type:                 model.TransactionFunding,
+			CreatedAt:            now,
+		}
+		if err := repos.Transactions.CreateTransaction(ctx, transaction); err != nil {
+			return fmt.Errorf("failed to insert funding transaction: %w", err)
+		}
+		total += acc.Balance
+	}
+	if err := repos.Accounts.UpdateBalance(ctx, mintAccountID, mintAcc.Balance-total); err != nil {
+		return fmt.Errorf("failed to update mint balance: %w", err)
+	}
+	return nil
+}
+
+// ListAccounts returns the accounts matching filter in the order it asks for
+func (s *accountService) ListAccounts(ctx context.Context, filter repository.AccountFilter) ([]*model.Account, error) {
+	ctx, span := tracer.Start(ctx, "AccountService.ListAccounts", trace.WithAttributes(
+		attribute.Int("list.limit", filter.Limit),
+		attribute.String("list.sort", string(filter.Sort)),
+	))
+
+	accounts, err := s.repo.ListAccounts(ctx, filter)
+	if err != nil {
+		endSpan(span, "error", err, true)
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int("list.count", len(accounts)))
+	endSpan(span, "success", nil, false)
+	return accounts, nil
+}
+
+// accountOutcome maps the result of an account operation to its span outcome
+func accountOutcome(err error) string {
+	switch {
+	case err == nil:
+		return "success"
+	case errors.Is(err, domain.ErrAccountNotFound):
+		return "not_found"
+	case errors.Is(err, domain.ErrAccountDuplicate):
+		return "duplicate"
+	case errors.Is(err, domain.ErrMintNotAllowed):
+		return "forbidden"
+	case errors.Is(err, domain.ErrInvalidAccount):
+		return "invalid"
+	case errors.Is(err, domain.ErrAccountVersionMismatch):
+		return "version_mismatch"
+	default:
+		return "error"
+	}
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"internal-transfers/internal/importer"
+	"internal-transfers/internal/model"
+	"internal-transfers/internal/repository"
+)
+
+// ImportStatus is the result of importing a single row
+type ImportStatus string
+
+const (
+	// ImportCreated means the account was created, or would be in a dry run
+	ImportCreated   ImportStatus = "created"
+	ImportDuplicate ImportStatus = "duplicate"
+	ImportInvalid   ImportStatus = "invalid"
+)
+
+// ImportRow reports what happened to one row of an import file
+type ImportRow struct {
+	Line      int
+	AccountID int64
+	Status    ImportStatus
+	// Error explains why a row was not created
+	Error string
+}
+
+// ImportReport summarises an import, with one entry in Rows per row of the file
+type ImportReport struct {
+	DryRun    bool
+	Created   int
+	Duplicate int
+	Invalid   int
+	Rows      []ImportRow
+}
+
+// errDryRun rolls back the unit of work of a dry-run import once its report is complete
+var errDryRun = errors.New("dry run")
+
+// ImportAccounts creates an account for every valid record in a single unit of work. Rows that fail validation,
+// including ids callers may not choose, or whose account already exists are skipped and reported; a dry run
+// reports the same outcome without creating anything. While minting is enabled the created accounts are funded
+// from the mint account.
+func (s *accountService) ImportAccounts(ctx context.Context, records []importer.Record, dryRun bool) (*ImportReport, error) {
+	if s.mint.enabled() {
+		if err := s.mint.authorize(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx, span := tracer.Start(ctx, "AccountService.ImportAccounts", trace.WithAttributes(
+		attribute.Int("import.rows", len(records)),
+		attribute.Bool("import.dry_run", dryRun),
+	))
+
+	report := &ImportReport{DryRun: dryRun, Rows: make([]ImportRow, len(records))}
+	firstSeen := make(map[int64]int, len(records))
+	var accounts []*model.Account
+	var pending []int // index into report.Rows of each entry in accounts
+	for i, rec := range records {
+		row := ImportRow{Line: rec.Line, AccountID: rec.AccountID}
+		if rec.Err == nil {
+			rec.Err = s.ids.check(rec.AccountID)
+		}
+		switch {
+		case rec.Err != nil:
+			row.Status, row.Error = ImportInvalid, rec.Err.Error()
+		case rec.InitialBalance <= 0:
+			// same rule as CreateAccount
+			row.Status, row.Error = ImportInvalid, "initial_balance must be positive"
+		case firstSeen[rec.AccountID] != 0:
+			row.Status, row.Error = ImportDuplicate, fmt.Sprintf("account_id repeats line %d", firstSeen[rec.AccountID])
+		default:
+			firstSeen[rec.AccountID] = rec.Line
+			accounts = append(accounts, &model.Account{AccountID: rec.AccountID, Balance: rec.InitialBalance})
+			pending = append(pending, i)
+		}
+		report.Rows[i] = row
+	}
+
+	err := s.uow.WithinTx(ctx, func(ctx context.Context, repos repository.Repositories) error {
+		if len(accounts) == 0 {
+			return nil
+		}
+		toInsert := accounts
+		if s.mint.enabled() {
+			// funded accounts open empty and receive their balance from the mint
+			toInsert = make([]*model.Account, len(accounts))
+			for i, acc := range accounts {
+				toInsert[i] = &model.Account{AccountID: acc.AccountID}
+			}
+		}
+		created, err := repos.Accounts.CreateAccounts(ctx, toInsert)
+		if err != nil {
+			return err
+		}
+		isCreated := make(map[int64]bool, len(created))
+		for _, id := range created {
+			isCreated[id] = true
+		}
+		if s.mint.enabled() && len(created) > 0 {
+			var funded []*model.Account
+			for _, acc := range accounts {
+				if isCreated[acc.AccountID] {
+					funded = append(funded, acc)
+				}
+			}
+			if err := mint(ctx, repos, s.mint.AccountID, funded); err != nil {
+				return err
+			}
+		}
+		for _, i := range pending {
+			if isCreated[report.Rows[i].AccountID] {
+				report.Rows[i].Status, report.Rows[i].Error = ImportCreated, ""
+			} else {
+				report.Rows[i].Status, report.Rows[i].Error = ImportDuplicate, "account already exists"
+			}
+		}
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if errors.Is(err, errDryRun) {
+		err = nil
+	}
+	if err != nil {
+		endSpan(span, "error", err, true)
+		return nil, err
+	}
+
+	for _, row := range report.Rows {
+		switch row.Status {
+		case ImportCreated:
+			report.Created++
+		case ImportDuplicate:
+			report.Duplicate++
+		case ImportInvalid:
+			report.Invalid++
+		}
+	}
+	span.SetAttributes(
+		attribute.Int("import.created", report.Created),
+		attribute.Int("import.duplicate", report.Duplicate),
+		attribute.Int("import.invalid", report.Invalid),
+	)
+	endSpan(span, "success", nil, false)
+	return report, nil
+}
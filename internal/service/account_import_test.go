@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"internal-transfers/internal/importer"
+	"internal-transfers/internal/model"
+	"internal-transfers/internal/repository"
+	"internal-transfers/internal/repository/memory"
+	"internal-transfers/internal/repository/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountService_ImportAccounts(t *testing.T) {
+	ctx := context.Background()
+	records := []importer.Record{
+		{Line: 1, AccountID: 1, InitialBalance: 100},
+		{Line: 2, AccountID: 2, InitialBalance: 50},
+		{Line: 3, AccountID: 1, InitialBalance: 10},
+		{Line: 4, AccountID: 3, InitialBalance: 0},
+		{Line: 5, Err: errors.New("invalid account_id \"x\"")},
+		{Line: 6, AccountID: 100_000_000_008, InitialBalance: 10},
+	}
+
+	newService := func(t *testing.T) (AccountService, repository.AccountRepository) {
+		store := memory.NewStore()
+		repo := memory.NewAccountRepository(store)
+		require.NoError(t, repo.CreateAccount(ctx, &model.Account{AccountID: 2, Balance: 5}))
+		return NewAccountService(repo, memory.NewUnitOfWork(store), MintPolicy{}, AccountIDPolicy{CallerChosen: true}), repo
+	}
+
+	t.Run("creates valid rows and reports the rest", func(t *testing.T) {
+		// given
+		service, repo := newService(t)
+
+		// when
+		report, err := service.ImportAccounts(ctx, records, false)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []ImportRow{
+			{Line: 1, AccountID: 1, Status: ImportCreated},
+			{Line: 2, AccountID: 2, Status: ImportDuplicate, Error: "account already exists"},
+			{Line: 3, AccountID: 1, Status: ImportDuplicate, Error: "account_id repeats line 1"},
+			{Line: 4, AccountID: 3, Status: ImportInvalid, Error: "initial_balance must be positive"},
+			{Line: 5, Status: ImportInvalid, Error: "invalid account_id \"x\""},
+			{Line: 6, AccountID: 100_000_000_008, Status: ImportInvalid,
+				Error: "invalid account: account_id must be between 1 and 99999999999"},
+		}, report.Rows)
+		assert.Equal(t, 1, report.Created)
+		assert.Equal(t, 2, report.Duplicate)
+		assert.Equal(t, 3, report.Invalid)
+
+		acc, err := repo.GetAccount(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 100.0, acc.Balance)
+	})
+
+	t.Run("dry run creates nothing", func(t *testing.T) {
+		// given
+		service, repo := newService(t)
+
+		// when
+		report, err := service.ImportAccounts(ctx, records, true)
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, report.DryRun)
+		assert.Equal(t, 1, report.Created)
+		_, err = repo.GetAccount(ctx, 1)
+		assert.Error(t, err)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		// given
+		accRepo := mocks.NewAccountRepository(t)
+		uow := mocks.NewUnitOfWork(t)
+		uow.EXPECT().
+			WithinTx(mock.Anything, mock.Anything).
+			RunAndReturn(func(ctx context.Context, fn func(context.Context, repository.Repositories) error) error {
+				return fn(ctx, repository.Repositories{Accounts: accRepo})
+			})
+		accRepo.EXPECT().CreateAccounts(mock.Anything, mock.Anything).Return(nil, errors.New("db error"))
+		service := NewAccountService(accRepo, uow, MintPolicy{}, AccountIDPolicy{CallerChosen: true})
+
+		// when
+		report, err := service.ImportAccounts(ctx, records, false)
+
+		// then
+		assert.Nil(t, report)
+		assert.ErrorContains(t, err, "db error")
+	})
+}
@@ -3,40 +3,283 @@ package service
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 
 	"internal-transfers/internal/domain"
 	"internal-transfers/internal/model"
+	"internal-transfers/internal/repository"
+	"internal-transfers/internal/repository/memory"
 	"internal-transfers/internal/repository/mocks"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAccountService_CreateAccount(t *testing.T) {
 	ctx := context.Background()
 	repo := mocks.NewAccountRepository(t)
-	service := NewAccountService(repo)
+	service := NewAccountService(repo, mocks.NewUnitOfWork(t), MintPolicy{}, AccountIDPolicy{CallerChosen: true})
 
 	t.Run("success", func(t *testing.T) {
 		repo.EXPECT().
-			CreateAccount(ctx, &model.Account{AccountID: 1, Balance: 100}).
+			CreateAccount(mock.Anything, &model.Account{AccountID: 1, Balance: 100, Type: model.AccountCustomer}).
 			Return(nil)
 
-		err := service.CreateAccount(ctx, 1, 100)
-		assert.NoError(t, err)
+		acc, err := service.CreateAccount(ctx, 1, 100, "", AccountDetails{})
+		require.NoError(t, err)
+		assert.Equal(t, &model.Account{AccountID: 1, Balance: 100, Type: model.AccountCustomer}, acc)
+	})
+
+	t.Run("account id outside the range callers may choose", func(t *testing.T) {
+		for _, id := range []int64{-1, model.MaxCallerAccountID + 1} {
+			_, err := service.CreateAccount(ctx, id, 100, "", AccountDetails{})
+			assert.ErrorIs(t, err, domain.ErrInvalidAccount, id)
+		}
 	})
 
 	t.Run("invalid balance", func(t *testing.T) {
-		err := service.CreateAccount(ctx, 1, 0)
+		_, err := service.CreateAccount(ctx, 1, 0, "", AccountDetails{})
 		assert.ErrorIs(t, err, domain.ErrInsufficientFunds)
 	})
 
 	t.Run("repo error", func(t *testing.T) {
 		repo.EXPECT().
-			CreateAccount(ctx, &model.Account{AccountID: 2, Balance: 100}).
+			CreateAccount(mock.Anything, &model.Account{AccountID: 2, Balance: 100, Type: model.AccountCustomer}).
 			Return(errors.New("db error"))
 
-		err := service.CreateAccount(ctx, 2, 100)
+		_, err := service.CreateAccount(ctx, 2, 100, "", AccountDetails{})
 		assert.ErrorContains(t, err, "db error")
 	})
+
+	t.Run("ledger account opens empty", func(t *testing.T) {
+		repo.EXPECT().
+			CreateAccount(mock.Anything, &model.Account{AccountID: 3, Type: model.AccountRevenue}).
+			Return(nil)
+
+		_, err := service.CreateAccount(ctx, 3, 0, model.AccountRevenue, AccountDetails{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid type", func(t *testing.T) {
+		for _, accountType := range []model.AccountType{"asset", model.AccountSystem, model.AccountSettlement} {
+			_, err := service.CreateAccount(ctx, 4, 100, accountType, AccountDetails{})
+			assert.ErrorIs(t, err, domain.ErrInvalidAccountType, accountType)
+		}
+	})
+
+	t.Run("details", func(t *testing.T) {
+		repo.EXPECT().
+			CreateAccount(mock.Anything, &model.Account{
+				AccountID: 5,
+				Balance:   100,
+				Type:      model.AccountCustomer,
+				Name:      "Savings",
+				Owner:     "cust-1",
+				Labels:    map[string]string{"tier": "gold"},
+			}).
+			Return(nil)
+
+		_, err := service.CreateAccount(ctx, 5, 100, "", AccountDetails{Name: "Savings", Owner: "cust-1", Labels: map[string]string{"tier": "gold"}})
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid details", func(t *testing.T) {
+		for _, details := range []AccountDetails{
+			{Name: strings.Repeat("n", maxAccountNameLength+1)},
+			{Owner: strings.Repeat("o", maxAccountOwnerLength+1)},
+			{Labels: map[string]string{"": "x"}},
+			{Labels: map[string]string{"k": strings.Repeat("v", maxAccountLabelsBytes)}},
+		} {
+			_, err := service.CreateAccount(ctx, 6, 100, "", details)
+			assert.ErrorIs(t, err, domain.ErrInvalidAccount)
+		}
+	})
+}
+
+func TestAccountService_CreateAccount_AllocatedID(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	accRepo := memory.NewAccountRepository(store)
+	service := NewAccountService(accRepo, memory.NewUnitOfWork(store), MintPolicy{}, AccountIDPolicy{})
+
+	t.Run("allocates ids ending in their check digit", func(t *testing.T) {
+		first, err := service.CreateAccount(ctx, 0, 100, "", AccountDetails{})
+		require.NoError(t, err)
+		second, err := service.CreateAccount(ctx, 0, 50, "", AccountDetails{})
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(100_000_000_008), first.AccountID)
+		assert.Equal(t, int64(100_000_000_016), second.AccountID)
+		assert.True(t, model.ValidAllocatedAccountID(second.AccountID))
+		assert.False(t, model.ValidAllocatedAccountID(100_000_000_061))
+		assert.Equal(t, int64(1), first.Version)
+
+		stored, err := accRepo.GetAccount(ctx, first.AccountID)
+		require.NoError(t, err)
+		assert.Equal(t, 100.0, stored.Balance)
+	})
+
+	t.Run("skips ids already taken", func(t *testing.T) {
+		taken := model.AllocatedAccountID(model.FirstAccountSequence + 2)
+		require.NoError(t, accRepo.CreateAccount(ctx, &model.Account{AccountID: taken}))
+
+		acc, err := service.CreateAccount(ctx, 0, 100, "", AccountDetails{})
+		require.NoError(t, err)
+		assert.Equal(t, model.AllocatedAccountID(model.FirstAccountSequence+3), acc.AccountID)
+	})
+
+	t.Run("callers may not choose ids", func(t *testing.T) {
+		_, err := service.CreateAccount(ctx, 1, 100, "", AccountDetails{})
+		assert.ErrorIs(t, err, domain.ErrInvalidAccount)
+	})
+}
+
+func TestAccountService_UpdateAccount(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	accRepo := memory.NewAccountRepository(store)
+	require.NoError(t, accRepo.CreateAccount(ctx, &model.Account{
+		AccountID: 1,
+		Balance:   100,
+		Owner:     "cust-1",
+		Labels:    map[string]string{"tier": "gold", "region": "eu"},
+	}))
+	service := NewAccountService(accRepo, memory.NewUnitOfWork(store), MintPolicy{}, AccountIDPolicy{CallerChosen: true})
+	name, tier := "Savings", "silver"
+
+	t.Run("merges the update into the details", func(t *testing.T) {
+		// when
+		acc, err := service.UpdateAccount(ctx, 1, AccountUpdate{
+			Name:   &name,
+			Labels: map[string]*string{"tier": &tier, "region": nil, "missing": nil},
+		}, 1)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "Savings", acc.Name)
+		assert.Equal(t, "cust-1", acc.Owner)
+		assert.Equal(t, map[string]string{"tier": "silver"}, acc.Labels)
+		assert.Equal(t, int64(2), acc.Version)
+		assert.Equal(t, 100.0, acc.Balance)
+
+		stored, _ := accRepo.GetAccount(ctx, 1)
+		assert.Equal(t, acc, stored)
+	})
+
+	t.Run("any version without a precondition", func(t *testing.T) {
+		owner := ""
+		acc, err := service.UpdateAccount(ctx, 1, AccountUpdate{Owner: &owner}, 0)
+
+		require.NoError(t, err)
+		assert.Empty(t, acc.Owner)
+		assert.Equal(t, int64(3), acc.Version)
+	})
+
+	t.Run("stale version", func(t *testing.T) {
+		_, err := service.UpdateAccount(ctx, 1, AccountUpdate{Name: &name}, 2)
+		assert.ErrorIs(t, err, domain.ErrAccountVersionMismatch)
+	})
+
+	t.Run("invalid details", func(t *testing.T) {
+		long := strings.Repeat("n", maxAccountNameLength+1)
+		_, err := service.UpdateAccount(ctx, 1, AccountUpdate{Name: &long}, 0)
+		assert.ErrorIs(t, err, domain.ErrInvalidAccount)
+
+		acc, _ := accRepo.GetAccount(ctx, 1)
+		assert.Equal(t, int64(3), acc.Version)
+	})
+
+	t.Run("account not found", func(t *testing.T) {
+		_, err := service.UpdateAccount(ctx, 2, AccountUpdate{Name: &name}, 0)
+		assert.ErrorIs(t, err, domain.ErrAccountNotFound)
+	})
+
+	t.Run("closes and reopens an account without a balance", func(t *testing.T) {
+		// given
+		require.NoError(t, accRepo.CreateAccount(ctx, &model.Account{AccountID: 3}))
+		closed, open := model.AccountClosed, model.AccountOpen
+
+		// when
+		acc, err := service.UpdateAccount(ctx, 3, AccountUpdate{Status: &closed}, 0)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, model.AccountClosed, acc.Status)
+
+		// when
+		acc, err = service.UpdateAccount(ctx, 3, AccountUpdate{Status: &open}, 0)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, model.AccountOpen, acc.Status)
+	})
+
+	t.Run("refuses to close", func(t *testing.T) {
+		require.NoError(t, accRepo.CreateAccount(ctx, &model.Account{AccountID: model.SuspenseAccountID, Type: model.AccountSuspense}))
+		closed, unknown := model.AccountClosed, model.AccountStatus("frozen")
+
+		for _, tc := range []struct {
+			name      string
+			accountID int64
+			status    *model.AccountStatus
+		}{
+			{"an account with a balance", 1, &closed},
+			{"the ledger's own accounts", model.SuspenseAccountID, &closed},
+			{"with an unknown status", 3, &unknown},
+		} {
+			_, err := service.UpdateAccount(ctx, tc.accountID, AccountUpdate{Status: tc.status}, 0)
+			assert.ErrorIs(t, err, domain.ErrInvalidAccount, tc.name)
+
+			acc, _ := accRepo.GetAccount(ctx, tc.accountID)
+			assert.Equal(t, model.AccountOpen, acc.Status, tc.name)
+		}
+	})
+}
+
+func TestAccountService_CreateAccount_Minted(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	accRepo := memory.NewAccountRepository(store)
+	require.NoError(t, accRepo.CreateAccount(ctx, &model.Account{AccountID: 1000}))
+	service := NewAccountService(accRepo, memory.NewUnitOfWork(store), MintPolicy{AccountID: 1000, AllowedCallers: []string{"onboarding"}},
+		AccountIDPolicy{CallerChosen: true})
+
+	t.Run("funds the account from the mint", func(t *testing.T) {
+		_, err := service.CreateAccount(domain.WithCaller(ctx, "onboarding"), 1, 100, "", AccountDetails{})
+		require.NoError(t, err)
+
+		acc, _ := accRepo.GetAccount(ctx, 1)
+		mintAcc, _ := accRepo.GetAccount(ctx, 1000)
+		assert.Equal(t, 100.0, acc.Balance)
+		assert.Equal(t, -100.0, mintAcc.Balance)
+
+		txs, err := memory.NewTransactionRepository(store).ListTransactions(ctx, repository.TransactionFilter{})
+		require.NoError(t, err)
+		require.Len(t, txs, 1)
+		assert.Equal(t, int64(1000), txs[0].SourceAccountID)
+		assert.Equal(t, int64(1), txs[0].DestinationAccountID)
+		assert.Equal(t, 100.0, txs[0].Amount)
+
+		report, err := NewReconciliationService(memory.NewReconciliationRepository(store), 1000, nil).Run(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, report.Discrepancies)
+	})
+
+	t.Run("caller not allowed", func(t *testing.T) {
+		_, err := service.CreateAccount(domain.WithCaller(ctx, "someone"), 2, 100, "", AccountDetails{})
+		assert.ErrorIs(t, err, domain.ErrMintNotAllowed)
+
+		_, err = accRepo.GetAccount(ctx, 2)
+		assert.ErrorIs(t, err, domain.ErrAccountNotFound)
+	})
+
+	t.Run("duplicate leaves the mint untouched", func(t *testing.T) {
+		_, err := service.CreateAccount(domain.WithCaller(ctx, "onboarding"), 1, 50, "", AccountDetails{})
+		assert.ErrorIs(t, err, domain.ErrAccountDuplicate)
+
+		mintAcc, _ := accRepo.GetAccount(ctx, 1000)
+		assert.Equal(t, -100.0, mintAcc.Balance)
+	})
 }
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"internal-transfers/internal/repository"
+)
+
+//go:generate mockery --name=BalanceService --filename=balance_mock.go --output=./mocks --with-expecter
+type BalanceService interface {
+	BalanceAt(ctx context.Context, accountID int64, at time.Time) (float64, error)
+	SnapshotBalances(ctx context.Context, asOf time.Time) error
+}
+
+type balanceService struct {
+	repo repository.BalanceRepository
+	uow  repository.UnitOfWork
+}
+
+func NewBalanceService(repo repository.BalanceRepository, uow repository.UnitOfWork) BalanceService {
+	return &balanceService{repo: repo, uow: uow}
+}
+
+// BalanceAt returns the balance of an account once every transaction created before at is applied. Accounts
+// report their initial balance for any time before they were opened.
+func (s *balanceService) BalanceAt(ctx context.Context, accountID int64, at time.Time) (float64, error) {
+	ctx, span := tracer.Start(ctx, "BalanceService.BalanceAt", trace.WithAttributes(
+		attribute.Int64("account.id", accountID),
+		attribute.String("balance.as_of", at.Format(time.RFC3339)),
+	))
+
+	balance, err := s.repo.BalanceAt(ctx, accountID, at)
+	outcome := accountOutcome(err)
+	endSpan(span, outcome, err, outcome == "error")
+	return balance, err
+}
+
+// SnapshotBalances records the balance of every account as of asOf; it is safe to call repeatedly. It holds the
+// period lock, so a backdated entry cannot commit after its balance was read and leave the snapshot stale.
+func (s *balanceService) SnapshotBalances(ctx context.Context, asOf time.Time) error {
+	ctx, span := tracer.Start(ctx, "BalanceService.SnapshotBalances", trace.WithAttributes(
+		attribute.String("snapshot.as_of", asOf.Format(time.RFC3339)),
+	))
+
+	var n int64
+	err := s.uow.WithinTx(ctx, func(ctx context.Context, repos repository.Repositories) error {
+		if err := repos.Periods.LockExclusive(ctx); err != nil {
+			return err
+		}
+		var err error
+		n, err = repos.Balances.CreateSnapshots(ctx, asOf)
+		return err
+	})
+	if err != nil {
+		endSpan(span, "error", err, true)
+		return err
+	}
+	span.SetAttributes(attribute.Int64("snapshot.accounts", n))
+	endSpan(span, "success", nil, false)
+
+	if n > 0 {
+		log.Ctx(ctx).Info().Time("as_of", asOf).Int64("accounts", n).Msg("balance snapshots written")
+	}
+	return nil
+}
+
+// DailySnapshotTime returns the latest midnight UTC at least delay before now. The delay leaves time for
+// transfers stamped just before midnight to commit before their day is snapshotted.
+func DailySnapshotTime(now time.Time, delay time.Duration) time.Time {
+	return now.Add(-delay).UTC().Truncate(24 * time.Hour)
+}
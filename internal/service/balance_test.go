@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"internal-transfers/internal/domain"
+	"internal-transfers/internal/repository"
+	"internal-transfers/internal/repository/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBalanceService_BalanceAt(t *testing.T) {
+	ctx := context.Background()
+	at := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		repo := mocks.NewBalanceRepository(t)
+		repo.EXPECT().BalanceAt(mock.Anything, int64(1), at).Return(42, nil)
+
+		balance, err := NewBalanceService(repo, nil).BalanceAt(ctx, 1, at)
+		assert.NoError(t, err)
+		assert.Equal(t, 42.0, balance)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		repo := mocks.NewBalanceRepository(t)
+		repo.EXPECT().BalanceAt(mock.Anything, int64(1), at).Return(0, domain.ErrAccountNotFound)
+
+		_, err := NewBalanceService(repo, nil).BalanceAt(ctx, 1, at)
+		assert.ErrorIs(t, err, domain.ErrAccountNotFound)
+	})
+}
+
+func TestBalanceService_SnapshotBalances(t *testing.T) {
+	at := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+	repo := mocks.NewBalanceRepository(t)
+	periods := mocks.NewPeriodRepository(t)
+	uow := mocks.NewUnitOfWork(t)
+	uow.EXPECT().
+		WithinTx(mock.Anything, mock.Anything).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context, repository.Repositories) error) error {
+			return fn(ctx, repository.Repositories{Balances: repo, Periods: periods})
+		})
+	periods.EXPECT().LockExclusive(mock.Anything).Return(nil)
+	repo.EXPECT().CreateSnapshots(mock.Anything, at).Return(3, nil)
+
+	assert.NoError(t, NewBalanceService(repo, uow).SnapshotBalances(context.Background(), at))
+}
+
+func TestDailySnapshotTime(t *testing.T) {
+	midnight := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, midnight, DailySnapshotTime(midnight.Add(10*time.Minute), 5*time.Minute))
+	assert.Equal(t, midnight.AddDate(0, 0, -1), DailySnapshotTime(midnight.Add(time.Minute), 5*time.Minute))
+	assert.Equal(t, midnight, DailySnapshotTime(midnight.Add(time.Hour).In(time.FixedZone("UTC+8", 8*3600)), 0))
+}
@@ -0,0 +1,276 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"internal-transfers/internal/domain"
+	"internal-transfers/internal/model"
+	"internal-transfers/internal/repository"
+)
+
+// postingClaimTimeout is how long a posting claimed by a run that failed or crashed waits before another run
+// may pay it
+const postingClaimTimeout = 10 * time.Minute
+
+//go:generate mockery --name=InterestService --filename=interest_mock.go --output=./mocks --with-expecter
+type InterestService interface {
+	// Accrue accrues the interest of every enrolled account for each day ending at or before through that was
+	// not accrued yet; an account without accruals starts with the day before through
+	Accrue(ctx context.Context, through time.Time) error
+	// Post pays the interest accrued over every period ending at or before through that was not paid yet
+	Post(ctx context.Context, through time.Time) error
+}
+
+type interestService struct {
+	repo             repository.InterestRepository
+	balances         repository.BalanceRepository
+	transfers        TransactionService
+	expenseAccountID int64
+	enrollments      map[int64]model.InterestProduct
+	accountIDs       []int64
+}
+
+// NewInterestService returns a service accruing interest on the enrolled accounts and paying it from
+// expenseAccountID through transfers
+func NewInterestService(repo repository.InterestRepository, balances repository.BalanceRepository, transfers TransactionService,
+	expenseAccountID int64, enrollments map[int64]model.InterestProduct) InterestService {
+	return &interestService{
+		repo:             repo,
+		balances:         balances,
+		transfers:        transfers,
+		expenseAccountID: expenseAccountID,
+		enrollments:      enrollments,
+		accountIDs:       slices.Sorted(maps.Keys(enrollments)),
+	}
+}
+
+func (s *interestService) Accrue(ctx context.Context, through time.Time) error {
+	through = through.UTC().Truncate(24 * time.Hour)
+	ctx, span := tracer.Start(ctx, "InterestService.Accrue", trace.WithAttributes(
+		attribute.String("interest.through", through.Format(time.DateOnly)),
+	))
+
+	var (
+		errs    []error
+		accrued int
+	)
+	for _, accountID := range s.accountIDs {
+		n, err := s.accrueAccount(ctx, accountID, s.enrollments[accountID], through)
+		accrued += n
+		if errors.Is(err, domain.ErrAccountNotFound) {
+			log.Ctx(ctx).Warn().Int64("account_id", accountID).Msg("Interest account does not exist, skipping accrual")
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("accrue interest of account %d: %w", accountID, err))
+		}
+	}
+	err := errors.Join(errs...)
+	span.SetAttributes(attribute.Int("interest.accruals", accrued))
+	endSpan(span, outcomeOf(err), err, true)
+
+	if accrued > 0 {
+		log.Ctx(ctx).Info().Time("through", through).Int("accruals", accrued).Msg("interest accrued")
+	}
+	return err
+}
+
+// accrueAccount accrues the days of one account that were not accrued yet, oldest first, and returns how many
+func (s *interestService) accrueAccount(ctx context.Context, accountID int64, product model.InterestProduct, through time.Time) (int, error) {
+	last, err := s.repo.LastAccrualDate(ctx, accountID)
+	if err != nil {
+		return 0, err
+	}
+	day := through.AddDate(0, 0, -1)
+	if !last.IsZero() {
+		day = last.AddDate(0, 0, 1)
+	}
+
+	n := 0
+	for ; day.Before(through); day = day.AddDate(0, 0, 1) {
+		accrual, err := s.accrueDay(ctx, accountID, product, day)
+		if err != nil {
+			return n, err
+		}
+		if err := s.repo.RecordAccrual(ctx, accrual); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// accrueDay computes the interest earned on the end-of-day balance of day. Daily compounding adds the interest
+// accrued earlier in the period to that balance; otherwise interest compounds once it is posted and so becomes
+// part of the balance.
+func (s *interestService) accrueDay(ctx context.Context, accountID int64, product model.InterestProduct, day time.Time) (*model.InterestAccrual, error) {
+	balance, err := s.balances.BalanceAt(ctx, accountID, day.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+	start, end := interestPeriod(day, product.Compounding)
+	if product.Compounding == model.CompoundingDaily {
+		accrued, err := s.repo.AccruedInterest(ctx, accountID, start, day)
+		if err != nil {
+			return nil, err
+		}
+		balance += accrued
+	}
+
+	accrual := &model.InterestAccrual{
+		AccountID:   accountID,
+		Date:        day,
+		Product:     product.Name,
+		Balance:     balance,
+		Rate:        product.Rate,
+		PeriodStart: start,
+		PeriodEnd:   end,
+	}
+	// overdrawn balances earn nothing rather than being charged
+	if balance > 0 {
+		accrual.Amount = balance * product.Rate * yearFraction(product.DayCount, day)
+	}
+	return accrual, nil
+}
+
+func (s *interestService) Post(ctx context.Context, through time.Time) error {
+	through = through.UTC().Truncate(24 * time.Hour)
+	ctx, span := tracer.Start(ctx, "InterestService.Post", trace.WithAttributes(
+		attribute.String("interest.through", through.Format(time.DateOnly)),
+	))
+
+	pending, err := s.repo.PendingPostings(ctx, through)
+	if err != nil {
+		endSpan(span, "error", err, true)
+		return err
+	}
+
+	var (
+		errs   []error
+		posted int
+	)
+	for _, p := range pending {
+		ok, err := s.post(ctx, p)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("post interest of account %d for %s: %w", p.AccountID, p.PeriodStart.Format(time.DateOnly), err))
+			continue
+		}
+		if ok {
+			posted++
+		}
+	}
+	err = errors.Join(errs...)
+	span.SetAttributes(attribute.Int("interest.postings", posted))
+	endSpan(span, outcomeOf(err), err, true)
+
+	if posted > 0 {
+		log.Ctx(ctx).Info().Time("through", through).Int("postings", posted).Msg("interest posted")
+	}
+	return err
+}
+
+// post pays a period's interest, rounded to cents, unless another run claimed it. The payment is recorded once
+// per interest source and reference, so a claim left by a run that failed after paying is completed without
+// paying again.
+func (s *interestService) post(ctx context.Context, p model.InterestPosting) (bool, error) {
+	p.Amount = float64(cents(p.Amount)) / 100
+	now := time.Now()
+	claimed, err := s.repo.ClaimPosting(ctx, p, now, now.Add(-postingClaimTimeout))
+	if err != nil || !claimed {
+		return false, err
+	}
+
+	if p.Amount > 0 {
+		last := p.PeriodEnd.AddDate(0, 0, -1).Format(time.DateOnly)
+		err = s.transfers.ProcessTransaction(ctx, s.expenseAccountID, p.AccountID, p.Amount, TransferDetails{
+			Type:        model.TransactionInterest,
+			Description: fmt.Sprintf("Interest from %s to %s", p.PeriodStart.Format(time.DateOnly), last),
+			Metadata: map[string]string{
+				"interest_product":      p.Product,
+				"interest_period_start": p.PeriodStart.Format(time.DateOnly),
+				"interest_period_end":   last,
+			},
+			ExternalReference: interestReference(p),
+			system:            true,
+			externalSource:    interestSource,
+		})
+		if errors.Is(err, domain.ErrAccountClosed) {
+			// closed accounts hold no money, so interest accrued before the account was closed is forfeited
+			log.Ctx(ctx).Warn().Int64("account_id", p.AccountID).Float64("amount", p.Amount).
+				Msg("account is closed, interest not paid")
+			err = nil
+		}
+		if err != nil && !errors.Is(err, domain.ErrDuplicateReference) {
+			return false, err
+		}
+	}
+	return true, s.repo.CompletePosting(ctx, p.AccountID, p.PeriodStart, time.Now())
+}
+
+// interestSource is the external source of interest payments, which keeps their references apart from those
+// of settlement sources
+const interestSource = "interest"
+
+// interestReference identifies the payment of a posting, one per account and period
+func interestReference(p model.InterestPosting) string {
+	return fmt.Sprintf("interest:%d:%s", p.AccountID, p.PeriodStart.Format(time.DateOnly))
+}
+
+// interestPeriod returns the posting period containing day, end exclusive. Daily compounding posts monthly.
+func interestPeriod(day time.Time, compounding model.Compounding) (time.Time, time.Time) {
+	year, month := day.Year(), day.Month()
+	switch compounding {
+	case model.CompoundingQuarterly:
+		start := time.Date(year, month-(month-1)%3, 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 3, 0)
+	case model.CompoundingAnnually:
+		start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(1, 0, 0)
+	default:
+		start := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 1, 0)
+	}
+}
+
+// yearFraction returns the fraction of a year day counts for under the day count convention
+func yearFraction(dayCount model.DayCount, day time.Time) float64 {
+	switch dayCount {
+	case model.DayCountActual360:
+		return 1.0 / 360
+	case model.DayCountActualActual:
+		yearStart := time.Date(day.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+		return 24 / yearStart.AddDate(1, 0, 0).Sub(yearStart).Hours()
+	case model.DayCount30360:
+		return float64(days30360(day, day.AddDate(0, 0, 1))) / 360
+	default:
+		return 1.0 / 365
+	}
+}
+
+// days30360 counts the days from start to end with every month taken as 30 days, by the 30/360 bond basis
+func days30360(start, end time.Time) int {
+	d1, d2 := start.Day(), end.Day()
+	if d1 == 31 {
+		d1 = 30
+	}
+	if d2 == 31 && d1 == 30 {
+		d2 = 30
+	}
+	return 360*(end.Year()-start.Year()) + 30*(int(end.Month())-int(start.Month())) + d2 - d1
+}
+
+func outcomeOf(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "success"
+}
@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"internal-transfers/internal/domain"
+	"internal-transfers/internal/model"
+	"internal-transfers/internal/repository"
+	"internal-transfers/internal/repository/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newInterestTestService(t *testing.T, enrollments map[int64]model.InterestProduct) (*memory.Store, repository.InterestRepository, InterestService) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	accRepo := memory.NewAccountRepository(store)
+	opened := time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, accRepo.CreateAccount(ctx, &model.Account{AccountID: model.InterestExpenseAccountID, Type: model.AccountExpense, CreatedAt: opened}))
+	require.NoError(t, accRepo.CreateAccount(ctx, &model.Account{AccountID: 1, Balance: 1000, CreatedAt: opened}))
+	require.NoError(t, accRepo.CreateAccount(ctx, &model.Account{AccountID: 2, Balance: 1000, CreatedAt: opened}))
+
+	repo := memory.NewInterestRepository(store)
+	transfers := NewTransactionService(memory.NewTransactionRepository(store), memory.NewUnitOfWork(store), nil)
+	service := NewInterestService(repo, memory.NewBalanceRepository(store), transfers, model.InterestExpenseAccountID, enrollments)
+	return store, repo, service
+}
+
+func TestInterestService_AccrueAndPost(t *testing.T) {
+	ctx := context.Background()
+	savings := model.InterestProduct{Name: "savings", Rate: 0.0365, DayCount: model.DayCountActual365, Compounding: model.CompoundingMonthly}
+	store, repo, service := newInterestTestService(t, map[int64]model.InterestProduct{1: savings})
+	jan := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	feb := jan.AddDate(0, 1, 0)
+
+	t.Run("first run accrues the day before", func(t *testing.T) {
+		require.NoError(t, service.Accrue(ctx, jan.AddDate(0, 0, 1)))
+
+		accrued, err := repo.AccruedInterest(ctx, 1, jan, feb)
+		require.NoError(t, err)
+		assert.InDelta(t, 0.1, accrued, 1e-9)
+	})
+
+	t.Run("catches up on missed days once", func(t *testing.T) {
+		require.NoError(t, service.Accrue(ctx, feb.Add(3*time.Hour)))
+		require.NoError(t, service.Accrue(ctx, feb))
+
+		last, err := repo.LastAccrualDate(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, jan.AddDate(0, 0, 30), last)
+		accrued, err := repo.AccruedInterest(ctx, 1, jan, feb)
+		require.NoError(t, err)
+		assert.InDelta(t, 3.1, accrued, 1e-9)
+	})
+
+	t.Run("posts only ended periods", func(t *testing.T) {
+		require.NoError(t, service.Post(ctx, feb.AddDate(0, 0, -1)))
+
+		txs, err := memory.NewTransactionRepository(store).ListTransactions(ctx, repository.TransactionFilter{})
+		require.NoError(t, err)
+		assert.Empty(t, txs)
+	})
+
+	t.Run("pays the period once from the interest expense account", func(t *testing.T) {
+		require.NoError(t, service.Post(ctx, feb))
+		require.NoError(t, service.Post(ctx, feb))
+
+		txs, err := memory.NewTransactionRepository(store).ListTransactions(ctx, repository.TransactionFilter{})
+		require.NoError(t, err)
+		require.Len(t, txs, 1)
+		assert.Equal(t, model.InterestExpenseAccountID, txs[0].SourceAccountID)
+		assert.Equal(t, int64(1), txs[0].DestinationAccountID)
+		assert.Equal(t, 3.1, txs[0].Amount)
+		assert.Equal(t, model.TransactionInterest, txs[0].Type)
+		assert.Equal(t, "interest", txs[0].ExternalSource)
+		assert.Equal(t, "interest:1:2025-01-01", txs[0].ExternalReference)
+		assert.Equal(t, "2025-01-31", txs[0].Metadata["interest_period_end"])
+
+		acc, err := memory.NewAccountRepository(store).GetAccount(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 1003.1, acc.Balance)
+	})
+}
+
+func TestInterestService_Post_CompletesStaleClaimWithoutPayingTwice(t *testing.T) {
+	// given
+	ctx := context.Background()
+	savings := model.InterestProduct{Name: "savings", Rate: 0.0365, DayCount: model.DayCountActual365, Compounding: model.CompoundingMonthly}
+	store, repo, service := newInterestTestService(t, map[int64]model.InterestProduct{1: savings})
+	jan := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	feb := jan.AddDate(0, 1, 0)
+	require.NoError(t, service.Accrue(ctx, feb))
+
+	// a run claimed and paid the posting, then failed before completing it
+	posting := model.InterestPosting{AccountID: 1, Product: "savings", PeriodStart: jan, PeriodEnd: feb, Amount: 0.1}
+	claimed, err := repo.ClaimPosting(ctx, posting, time.Now().Add(-time.Hour), time.Now().Add(-2*time.Hour))
+	require.NoError(t, err)
+	require.True(t, claimed)
+	transfers := NewTransactionService(memory.NewTransactionRepository(store), memory.NewUnitOfWork(store), nil)
+	require.NoError(t, transfers.ProcessTransaction(ctx, model.InterestExpenseAccountID, 1, 0.1, TransferDetails{
+		Type: model.TransactionInterest, ExternalReference: interestReference(posting), system: true, externalSource: interestSource,
+	}))
+
+	// when
+	err = service.Post(ctx, feb)
+
+	// then
+	require.NoError(t, err)
+	txs, err := memory.NewTransactionRepository(store).ListTransactions(ctx, repository.TransactionFilter{})
+	require.NoError(t, err)
+	assert.Len(t, txs, 1)
+	pending, err := repo.PendingPostings(ctx, feb)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+}
+
+func TestInterestService_Post_WaitsForTheLastDayOfThePeriod(t *testing.T) {
+	// given
+	ctx := context.Background()
+	savings := model.InterestProduct{Name: "savings", Rate: 0.0365, DayCount: model.DayCountActual365, Compounding: model.CompoundingMonthly}
+	store, repo, service := newInterestTestService(t, map[int64]model.InterestProduct{1: savings})
+	jan := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	feb := jan.AddDate(0, 1, 0)
+	require.NoError(t, service.Accrue(ctx, feb.AddDate(0, 0, -1)))
+
+	// when the period has ended but its last day is not accrued yet
+	require.NoError(t, service.Post(ctx, feb))
+
+	// then
+	txs, err := memory.NewTransactionRepository(store).ListTransactions(ctx, repository.TransactionFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, txs)
+
+	// when the last day is accrued
+	require.NoError(t, service.Accrue(ctx, feb))
+	pending, err := repo.PendingPostings(ctx, feb)
+	require.NoError(t, err)
+	require.NoError(t, service.Post(ctx, feb))
+
+	// then both days are paid
+	require.Len(t, pending, 1)
+	assert.InDelta(t, 0.2, pending[0].Amount, 1e-9)
+	txs, err = memory.NewTransactionRepository(store).ListTransactions(ctx, repository.TransactionFilter{})
+	require.NoError(t, err)
+	require.Len(t, txs, 1)
+	assert.Equal(t, 0.2, txs[0].Amount)
+}
+
+func TestInterestService_Accrue_AfterBackdatedEntry(t *testing.T) {
+	ctx := context.Background()
+	savings := model.InterestProduct{Name: "savings", Rate: 0.0365, DayCount: model.DayCountActual365, Compounding: model.CompoundingMonthly}
+	store, repo, service := newInterestTestService(t, map[int64]model.InterestProduct{1: savings})
+	transfers := NewTransactionService(memory.NewTransactionRepository(store), memory.NewUnitOfWork(store), nil)
+	jan := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	feb := jan.AddDate(0, 1, 0)
+	require.NoError(t, service.Accrue(ctx, jan.AddDate(0, 0, 1)))
+	require.NoError(t, service.Accrue(ctx, feb))
+
+	t.Run("accrues the days from the entry on again", func(t *testing.T) {
+		require.NoError(t, transfers.ProcessTransaction(ctx, 2, 1, 1000, TransferDetails{EffectiveAt: jan.AddDate(0, 0, 16).Add(12 * time.Hour)}))
+
+		pending, err := repo.PendingPostings(ctx, feb)
+		require.NoError(t, err)
+		assert.Empty(t, pending)
+
+		require.NoError(t, service.Accrue(ctx, feb))
+		accrued, err := repo.AccruedInterest(ctx, 1, jan, feb)
+		require.NoError(t, err)
+		assert.InDelta(t, 3.1+1.5, accrued, 1e-9)
+	})
+
+	t.Run("rejects entries into periods already paid", func(t *testing.T) {
+		require.NoError(t, service.Post(ctx, feb))
+
+		err := transfers.ProcessTransaction(ctx, 1, 2, 10, TransferDetails{EffectiveAt: jan.AddDate(0, 0, 20)})
+		assert.ErrorIs(t, err, domain.ErrPeriodClosed)
+
+		err = transfers.ProcessTransaction(ctx, 1, 2, 10, TransferDetails{EffectiveAt: feb.Add(time.Hour)})
+		assert.NoError(t, err)
+	})
+}
+
+func TestInterestService_Accrue_DailyCompounding(t *testing.T) {
+	// given
+	ctx := context.Background()
+	notice := model.InterestProduct{Name: "notice", Rate: 0.36, DayCount: model.DayCountActual360, Compounding: model.CompoundingDaily}
+	_, repo, service := newInterestTestService(t, map[int64]model.InterestProduct{1: notice})
+	jan := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, service.Accrue(ctx, jan.AddDate(0, 0, 1)))
+
+	// when
+	err := service.Accrue(ctx, jan.AddDate(0, 0, 2))
+
+	// then
+	require.NoError(t, err)
+	first, err := repo.AccruedInterest(ctx, 1, jan, jan.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	second, err := repo.AccruedInterest(ctx, 1, jan.AddDate(0, 0, 1), jan.AddDate(0, 0, 2))
+	require.NoError(t, err)
+	assert.InDelta(t, 1.0, first, 1e-9)
+	assert.InDelta(t, 1.001, second, 1e-9)
+}
+
+func TestInterestService_Accrue_SkipsMissingAccount(t *testing.T) {
+	// given
+	ctx := context.Background()
+	savings := model.InterestProduct{Name: "savings", Rate: 0.05, DayCount: model.DayCountActual365, Compounding: model.CompoundingMonthly}
+	_, repo, service := newInterestTestService(t, map[int64]model.InterestProduct{404: savings})
+
+	// when
+	err := service.Accrue(ctx, time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC))
+
+	// then
+	require.NoError(t, err)
+	last, err := repo.LastAccrualDate(ctx, 404)
+	require.NoError(t, err)
+	assert.True(t, last.IsZero())
+}
+
+func TestYearFraction(t *testing.T) {
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	assert.Equal(t, 1.0/365, yearFraction(model.DayCountActual365, day(2024, 2, 29)))
+	assert.Equal(t, 1.0/360, yearFraction(model.DayCountActual360, day(2025, 3, 1)))
+	assert.Equal(t, 1.0/366, yearFraction(model.DayCountActualActual, day(2024, 6, 1)))
+	assert.Equal(t, 1.0/365, yearFraction(model.DayCountActualActual, day(2025, 6, 1)))
+
+	// 30/360 counts every month as 30 days
+	assert.Equal(t, 0.0, yearFraction(model.DayCount30360, day(2025, 1, 30)))
+	assert.Equal(t, 3.0/360, yearFraction(model.DayCount30360, day(2025, 2, 28)))
+	total := 0.0
+	for d := day(2025, 1, 1); d.Before(day(2026, 1, 1)); d = d.AddDate(0, 0, 1) {
+		total += yearFraction(model.DayCount30360, d)
+	}
+	assert.InDelta(t, 1.0, total, 1e-9)
+}
+
+func TestInterestPeriod(t *testing.T) {
+	day := time.Date(2025, 8, 17, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		compounding model.Compounding
+		start, end  time.Time
+	}{
+		{model.CompoundingDaily, time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)},
+		{model.CompoundingMonthly, time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)},
+		{model.CompoundingQuarterly, time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)},
+		{model.CompoundingAnnually, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		start, end := interestPeriod(day, tt.compounding)
+		assert.Equal(t, tt.start, start, tt.compounding)
+		assert.Equal(t, tt.end, end, tt.compounding)
+	}
+}
@@ -4,9 +4,15 @@ package mocks
 
 import (
 	context "context"
-	model "internal-transfers/internal/model"
+	importer "internal-transfers/internal/importer"
 
 	mock "github.com/stretchr/testify/mock"
+
+	model "internal-transfers/internal/model"
+
+	repository "internal-transfers/internal/repository"
+
+	service "internal-transfers/internal/service"
 )
 
 // AccountService is an autogenerated mock type for the AccountService type
@@ -22,22 +28,34 @@ func (_m *AccountService) EXPECT() *AccountService_Expecter {
 	return &AccountService_Expecter{mock: &_m.Mock}
 }
 
-// CreateAccount provides a mock function with given fields: ctx, accountID, balance
-func (_m *AccountService) CreateAccount(ctx context.Context, accountID int64, balance float64) error {
-	ret := _m.Called(ctx, accountID, balance)
+// CreateAccount provides a mock function with given fields: ctx, accountID, balance, accountType, details
+func (_m *AccountService) CreateAccount(ctx context.Context, accountID int64, balance float64, accountType model.AccountType, details service.AccountDetails) (*model.Account, error) {
+	ret := _m.Called(ctx, accountID, balance, accountType, details)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateAccount")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int64, float64) error); ok {
-		r0 = rf(ctx, accountID, balance)
+	var r0 *model.Account
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, float64, model.AccountType, service.AccountDetails) (*model.Account, error)); ok {
+		return rf(ctx, accountID, balance, accountType, details)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, float64, model.AccountType, service.AccountDetails) *model.Account); ok {
+		r0 = rf(ctx, accountID, balance, accountType, details)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Account)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, float64, model.AccountType, service.AccountDetails) error); ok {
+		r1 = rf(ctx, accountID, balance, accountType, details)
+	} else {
+		r1 = ret.Error(1)
 	}
 
-	return r0
+	return r0, r1
 }
 
 // AccountService_CreateAccount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateAccount'
@@ -49,23 +67,25 @@ type AccountService_CreateAccount_Call struct {
 //   - ctx context.Context
 //   - accountID int64
 //   - balance float64
-func (_e *AccountService_Expecter) CreateAccount(ctx interface{}, accountID interface{}, balance interface{}) *AccountService_CreateAccount_Call {
-	return &AccountService_CreateAccount_Call{Call: _e.mock.On("CreateAccount", ctx, accountID, balance)}
+//   - accountType model.AccountType
+//   - details service.AccountDetails
+func (_e *AccountService_Expecter) CreateAccount(ctx interface{}, accountID interface{}, balance interface{}, accountType interface{}, details interface{}) *AccountService_CreateAccount_Call {
+	return &AccountService_CreateAccount_Call{Call: _e.mock.On("CreateAccount", ctx, accountID, balance, accountType, details)}
 }
 
-func (_c *AccountService_CreateAccount_Call) Run(run func(ctx context.Context, accountID int64, balance float64)) *AccountService_CreateAccount_Call {
+func (_c *AccountService_CreateAccount_Call) Run(run func(ctx context.Context, accountID int64, balance float64, accountType model.AccountType, details service.AccountDetails)) *AccountService_CreateAccount_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int64), args[2].(float64))
+		run(args[0].(context.Context), args[1].(int64), args[2].(float64), args[3].(model.AccountType), args[4].(service.AccountDetails))
 	})
 	return _c
 }
 
-func (_c *AccountService_CreateAccount_Call) Return(_a0 error) *AccountService_CreateAccount_Call {
-	_c.Call.Return(_a0)
+func (_c *AccountService_CreateAccount_Call) Return(_a0 *model.Account, _a1 error) *AccountService_CreateAccount_Call {
+	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *AccountService_CreateAccount_Call) RunAndReturn(run func(context.Context, int64, float64) error) *AccountService_CreateAccount_Call {
+func (_c *AccountService_CreateAccount_Call) RunAndReturn(run func(context.Context, int64, float64, model.AccountType, service.AccountDetails) (*model.Account, error)) *AccountService_CreateAccount_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -129,6 +149,186 @@ func (_c *AccountService_GetAccount_Call) RunAndReturn(run func(context.Context,
 	return _c
 }
 
+// ImportAccounts provides a mock function with given fields: ctx, records, dryRun
+func (_m *AccountService) ImportAccounts(ctx context.Context, records []importer.Record, dryRun bool) (*service.ImportReport, error) {
+	ret := _m.Called(ctx, records, dryRun)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ImportAccounts")
+	}
+
+	var r0 *service.ImportReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []importer.Record, bool) (*service.ImportReport, error)); ok {
+		return rf(ctx, records, dryRun)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []importer.Record, bool) *service.ImportReport); ok {
+		r0 = rf(ctx, records, dryRun)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.ImportReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []importer.Record, bool) error); ok {
+		r1 = rf(ctx, records, dryRun)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AccountService_ImportAccounts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ImportAccounts'
+type AccountService_ImportAccounts_Call struct {
+	*mock.Call
+}
+
+// ImportAccounts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - records []importer.Record
+//   - dryRun bool
+func (_e *AccountService_Expecter) ImportAccounts(ctx interface{}, records interface{}, dryRun interface{}) *AccountService_ImportAccounts_Call {
+	return &AccountService_ImportAccounts_Call{Call: _e.mock.On("ImportAccounts", ctx, records, dryRun)}
+}
+
+func (_c *AccountService_ImportAccounts_Call) Run(run func(ctx context.Context, records []importer.Record, dryRun bool)) *AccountService_ImportAccounts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]importer.Record), args[2].(bool))
+	})
+	return _c
+}
+
+func (_c *AccountService_ImportAccounts_Call) Return(_a0 *service.ImportReport, _a1 error) *AccountService_ImportAccounts_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AccountService_ImportAccounts_Call) RunAndReturn(run func(context.Context, []importer.Record, bool) (*service.ImportReport, error)) *AccountService_ImportAccounts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListAccounts provides a mock function with given fields: ctx, filter
+func (_m *AccountService) ListAccounts(ctx context.Context, filter repository.AccountFilter) ([]*model.Account, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAccounts")
+	}
+
+	var r0 []*model.Account
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, repository.AccountFilter) ([]*model.Account, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, repository.AccountFilter) []*model.Account); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Account)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, repository.AccountFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AccountService_ListAccounts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAccounts'
+type AccountService_ListAccounts_Call struct {
+	*mock.Call
+}
+
+// ListAccounts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter repository.AccountFilter
+func (_e *AccountService_Expecter) ListAccounts(ctx interface{}, filter interface{}) *AccountService_ListAccounts_Call {
+	return &AccountService_ListAccounts_Call{Call: _e.mock.On("ListAccounts", ctx, filter)}
+}
+
+func (_c *AccountService_ListAccounts_Call) Run(run func(ctx context.Context, filter repository.AccountFilter)) *AccountService_ListAccounts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(repository.AccountFilter))
+	})
+	return _c
+}
+
+func (_c *AccountService_ListAccounts_Call) Return(_a0 []*model.Account, _a1 error) *AccountService_ListAccounts_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AccountService_ListAccounts_Call) RunAndReturn(run func(context.Context, repository.AccountFilter) ([]*model.Account, error)) *AccountService_ListAccounts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateAccount provides a mock function with given fields: ctx, accountID, update, version
+func (_m *AccountService) UpdateAccount(ctx context.Context, accountID int64, update service.AccountUpdate, version int64) (*model.Account, error) {
+	ret := _m.Called(ctx, accountID, update, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateAccount")
+	}
+
+	var r0 *model.Account
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, service.AccountUpdate, int64) (*model.Account, error)); ok {
+		return rf(ctx, accountID, update, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, service.AccountUpdate, int64) *model.Account); ok {
+		r0 = rf(ctx, accountID, update, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Account)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, service.AccountUpdate, int64) error); ok {
+		r1 = rf(ctx, accountID, update, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AccountService_UpdateAccount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateAccount'
+type AccountService_UpdateAccount_Call struct {
+	*mock.Call
+}
+
+// UpdateAccount is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID int64
+//   - update service.AccountUpdate
+//   - version int64
+func (_e *AccountService_Expecter) UpdateAccount(ctx interface{}, accountID interface{}, update interface{}, version interface{}) *AccountService_UpdateAccount_Call {
+	return &AccountService_UpdateAccount_Call{Call: _e.mock.On("UpdateAccount", ctx, accountID, update, version)}
+}
+
+func (_c *AccountService_UpdateAccount_Call) Run(run func(ctx context.Context, accountID int64, update service.AccountUpdate, version int64)) *AccountService_UpdateAccount_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(service.AccountUpdate), args[3].(int64))
+	})
+	return _c
+}
+
+func (_c *AccountService_UpdateAccount_Call) Return(_a0 *model.Account, _a1 error) *AccountService_UpdateAccount_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AccountService_UpdateAccount_Call) RunAndReturn(run func(context.Context, int64, service.AccountUpdate, int64) (*model.Account, error)) *AccountService_UpdateAccount_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewAccountService creates a new instance of AccountService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewAccountService(t interface {
@@ -0,0 +1,143 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// BalanceService is an autogenerated mock type for the BalanceService type
+type BalanceService struct {
+	mock.Mock
+}
+
+type BalanceService_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *BalanceService) EXPECT() *BalanceService_Expecter {
+	return &BalanceService_Expecter{mock: &_m.Mock}
+}
+
+// BalanceAt provides a mock function with given fields: ctx, accountID, at
+func (_m *BalanceService) BalanceAt(ctx context.Context, accountID int64, at time.Time) (float64, error) {
+	ret := _m.Called(ctx, accountID, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BalanceAt")
+	}
+
+	var r0 float64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) (float64, error)); ok {
+		return rf(ctx, accountID, at)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) float64); ok {
+		r0 = rf(ctx, accountID, at)
+	} else {
+		r0 = ret.Get(0).(float64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, time.Time) error); ok {
+		r1 = rf(ctx, accountID, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BalanceService_BalanceAt_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BalanceAt'
+type BalanceService_BalanceAt_Call struct {
+	*mock.Call
+}
+
+// BalanceAt is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID int64
+//   - at time.Time
+func (_e *BalanceService_Expecter) BalanceAt(ctx interface{}, accountID interface{}, at interface{}) *BalanceService_BalanceAt_Call {
+	return &BalanceService_BalanceAt_Call{Call: _e.mock.On("BalanceAt", ctx, accountID, at)}
+}
+
+func (_c *BalanceService_BalanceAt_Call) Run(run func(ctx context.Context, accountID int64, at time.Time)) *BalanceService_BalanceAt_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *BalanceService_BalanceAt_Call) Return(_a0 float64, _a1 error) *BalanceService_BalanceAt_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *BalanceService_BalanceAt_Call) RunAndReturn(run func(context.Context, int64, time.Time) (float64, error)) *BalanceService_BalanceAt_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SnapshotBalances provides a mock function with given fields: ctx, asOf
+func (_m *BalanceService) SnapshotBalances(ctx context.Context, asOf time.Time) error {
+	ret := _m.Called(ctx, asOf)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SnapshotBalances")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) error); ok {
+		r0 = rf(ctx, asOf)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// BalanceService_SnapshotBalances_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SnapshotBalances'
+type BalanceService_SnapshotBalances_Call struct {
+	*mock.Call
+}
+
+// SnapshotBalances is a helper method to define mock.On call
+//   - ctx context.Context
+//   - asOf time.Time
+func (_e *BalanceService_Expecter) SnapshotBalances(ctx interface{}, asOf interface{}) *BalanceService_SnapshotBalances_Call {
+	return &BalanceService_SnapshotBalances_Call{Call: _e.mock.On("SnapshotBalances", ctx, asOf)}
+}
+
+func (_c *BalanceService_SnapshotBalances_Call) Run(run func(ctx context.Context, asOf time.Time)) *BalanceService_SnapshotBalances_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *BalanceService_SnapshotBalances_Call) Return(_a0 error) *BalanceService_SnapshotBalances_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *BalanceService_SnapshotBalances_Call) RunAndReturn(run func(context.Context, time.Time) error) *BalanceService_SnapshotBalances_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewBalanceService creates a new instance of BalanceService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBalanceService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BalanceService {
+	mock := &BalanceService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
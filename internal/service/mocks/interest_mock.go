@@ -0,0 +1,132 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// InterestService is an autogenerated mock type for the InterestService type
+type InterestService struct {
+	mock.Mock
+}
+
+type InterestService_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *InterestService) EXPECT() *InterestService_Expecter {
+	return &InterestService_Expecter{mock: &_m.Mock}
+}
+
+// Accrue provides a mock function with given fields: ctx, through
+func (_m *InterestService) Accrue(ctx context.Context, through time.Time) error {
+	ret := _m.Called(ctx, through)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Accrue")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) error); ok {
+		r0 = rf(ctx, through)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InterestService_Accrue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Accrue'
+type InterestService_Accrue_Call struct {
+	*mock.Call
+}
+
+// Accrue is a helper method to define mock.On call
+//   - ctx context.Context
+//   - through time.Time
+func (_e *InterestService_Expecter) Accrue(ctx interface{}, through interface{}) *InterestService_Accrue_Call {
+	return &InterestService_Accrue_Call{Call: _e.mock.On("Accrue", ctx, through)}
+}
+
+func (_c *InterestService_Accrue_Call) Run(run func(ctx context.Context, through time.Time)) *InterestService_Accrue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *InterestService_Accrue_Call) Return(_a0 error) *InterestService_Accrue_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *InterestService_Accrue_Call) RunAndReturn(run func(context.Context, time.Time) error) *InterestService_Accrue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Post provides a mock function with given fields: ctx, through
+func (_m *InterestService) Post(ctx context.Context, through time.Time) error {
+	ret := _m.Called(ctx, through)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Post")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) error); ok {
+		r0 = rf(ctx, through)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InterestService_Post_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Post'
+type InterestService_Post_Call struct {
+	*mock.Call
+}
+
+// Post is a helper method to define mock.On call
+//   - ctx context.Context
+//   - through time.Time
+func (_e *InterestService_Expecter) Post(ctx interface{}, through interface{}) *InterestService_Post_Call {
+	return &InterestService_Post_Call{Call: _e.mock.On("Post", ctx, through)}
+}
+
+func (_c *InterestService_Post_Call) Run(run func(ctx context.Context, through time.Time)) *InterestService_Post_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *InterestService_Post_Call) Return(_a0 error) *InterestService_Post_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *InterestService_Post_Call) RunAndReturn(run func(context.Context, time.Time) error) *InterestService_Post_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewInterestService creates a new instance of InterestService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewInterestService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *InterestService {
+	mock := &InterestService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,205 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "internal-transfers/internal/model"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// PeriodService is an autogenerated mock type for the PeriodService type
+type PeriodService struct {
+	mock.Mock
+}
+
+type PeriodService_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *PeriodService) EXPECT() *PeriodService_Expecter {
+	return &PeriodService_Expecter{mock: &_m.Mock}
+}
+
+// CloseDue provides a mock function with given fields: ctx, through
+func (_m *PeriodService) CloseDue(ctx context.Context, through time.Time) error {
+	ret := _m.Called(ctx, through)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CloseDue")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) error); ok {
+		r0 = rf(ctx, through)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PeriodService_CloseDue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CloseDue'
+type PeriodService_CloseDue_Call struct {
+	*mock.Call
+}
+
+// CloseDue is a helper method to define mock.On call
+//   - ctx context.Context
+//   - through time.Time
+func (_e *PeriodService_Expecter) CloseDue(ctx interface{}, through interface{}) *PeriodService_CloseDue_Call {
+	return &PeriodService_CloseDue_Call{Call: _e.mock.On("CloseDue", ctx, through)}
+}
+
+func (_c *PeriodService_CloseDue_Call) Run(run func(ctx context.Context, through time.Time)) *PeriodService_CloseDue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *PeriodService_CloseDue_Call) Return(_a0 error) *PeriodService_CloseDue_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *PeriodService_CloseDue_Call) RunAndReturn(run func(context.Context, time.Time) error) *PeriodService_CloseDue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ClosePeriod provides a mock function with given fields: ctx, day
+func (_m *PeriodService) ClosePeriod(ctx context.Context, day time.Time) (*model.Period, error) {
+	ret := _m.Called(ctx, day)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClosePeriod")
+	}
+
+	var r0 *model.Period
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (*model.Period, error)); ok {
+		return rf(ctx, day)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) *model.Period); ok {
+		r0 = rf(ctx, day)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Period)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, day)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PeriodService_ClosePeriod_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ClosePeriod'
+type PeriodService_ClosePeriod_Call struct {
+	*mock.Call
+}
+
+// ClosePeriod is a helper method to define mock.On call
+//   - ctx context.Context
+//   - day time.Time
+func (_e *PeriodService_Expecter) ClosePeriod(ctx interface{}, day interface{}) *PeriodService_ClosePeriod_Call {
+	return &PeriodService_ClosePeriod_Call{Call: _e.mock.On("ClosePeriod", ctx, day)}
+}
+
+func (_c *PeriodService_ClosePeriod_Call) Run(run func(ctx context.Context, day time.Time)) *PeriodService_ClosePeriod_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *PeriodService_ClosePeriod_Call) Return(_a0 *model.Period, _a1 error) *PeriodService_ClosePeriod_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *PeriodService_ClosePeriod_Call) RunAndReturn(run func(context.Context, time.Time) (*model.Period, error)) *PeriodService_ClosePeriod_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListPeriods provides a mock function with given fields: ctx, from, to
+func (_m *PeriodService) ListPeriods(ctx context.Context, from time.Time, to time.Time) ([]*model.Period, error) {
+	ret := _m.Called(ctx, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPeriods")
+	}
+
+	var r0 []*model.Period
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) ([]*model.Period, error)); ok {
+		return rf(ctx, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) []*model.Period); ok {
+		r0 = rf(ctx, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Period)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PeriodService_ListPeriods_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListPeriods'
+type PeriodService_ListPeriods_Call struct {
+	*mock.Call
+}
+
+// ListPeriods is a helper method to define mock.On call
+//   - ctx context.Context
+//   - from time.Time
+//   - to time.Time
+func (_e *PeriodService_Expecter) ListPeriods(ctx interface{}, from interface{}, to interface{}) *PeriodService_ListPeriods_Call {
+	return &PeriodService_ListPeriods_Call{Call: _e.mock.On("ListPeriods", ctx, from, to)}
+}
+
+func (_c *PeriodService_ListPeriods_Call) Run(run func(ctx context.Context, from time.Time, to time.Time)) *PeriodService_ListPeriods_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *PeriodService_ListPeriods_Call) Return(_a0 []*model.Period, _a1 error) *PeriodService_ListPeriods_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *PeriodService_ListPeriods_Call) RunAndReturn(run func(context.Context, time.Time, time.Time) ([]*model.Period, error)) *PeriodService_ListPeriods_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewPeriodService creates a new instance of PeriodService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPeriodService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PeriodService {
+	mock := &PeriodService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
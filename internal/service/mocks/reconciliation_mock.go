@@ -0,0 +1,200 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	service "internal-transfers/internal/service"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ReconciliationService is an autogenerated mock type for the ReconciliationService type
+type ReconciliationService struct {
+	mock.Mock
+}
+
+type ReconciliationService_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *ReconciliationService) EXPECT() *ReconciliationService_Expecter {
+	return &ReconciliationService_Expecter{mock: &_m.Mock}
+}
+
+// Last provides a mock function with no fields
+func (_m *ReconciliationService) Last() *service.ReconciliationReport {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Last")
+	}
+
+	var r0 *service.ReconciliationReport
+	if rf, ok := ret.Get(0).(func() *service.ReconciliationReport); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.ReconciliationReport)
+		}
+	}
+
+	return r0
+}
+
+// ReconciliationService_Last_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Last'
+type ReconciliationService_Last_Call struct {
+	*mock.Call
+}
+
+// Last is a helper method to define mock.On call
+func (_e *ReconciliationService_Expecter) Last() *ReconciliationService_Last_Call {
+	return &ReconciliationService_Last_Call{Call: _e.mock.On("Last")}
+}
+
+func (_c *ReconciliationService_Last_Call) Run(run func()) *ReconciliationService_Last_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *ReconciliationService_Last_Call) Return(_a0 *service.ReconciliationReport) *ReconciliationService_Last_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ReconciliationService_Last_Call) RunAndReturn(run func() *service.ReconciliationReport) *ReconciliationService_Last_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Run provides a mock function with given fields: ctx
+func (_m *ReconciliationService) Run(ctx context.Context) (*service.ReconciliationReport, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Run")
+	}
+
+	var r0 *service.ReconciliationReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*service.ReconciliationReport, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *service.ReconciliationReport); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.ReconciliationReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReconciliationService_Run_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Run'
+type ReconciliationService_Run_Call struct {
+	*mock.Call
+}
+
+// Run is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *ReconciliationService_Expecter) Run(ctx interface{}) *ReconciliationService_Run_Call {
+	return &ReconciliationService_Run_Call{Call: _e.mock.On("Run", ctx)}
+}
+
+func (_c *ReconciliationService_Run_Call) Run(run func(ctx context.Context)) *ReconciliationService_Run_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *ReconciliationService_Run_Call) Return(_a0 *service.ReconciliationReport, _a1 error) *ReconciliationService_Run_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ReconciliationService_Run_Call) RunAndReturn(run func(context.Context) (*service.ReconciliationReport, error)) *ReconciliationService_Run_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Supply provides a mock function with given fields: ctx
+func (_m *ReconciliationService) Supply(ctx context.Context) (*service.SupplyReport, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Supply")
+	}
+
+	var r0 *service.SupplyReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*service.SupplyReport, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *service.SupplyReport); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.SupplyReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReconciliationService_Supply_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Supply'
+type ReconciliationService_Supply_Call struct {
+	*mock.Call
+}
+
+// Supply is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *ReconciliationService_Expecter) Supply(ctx interface{}) *ReconciliationService_Supply_Call {
+	return &ReconciliationService_Supply_Call{Call: _e.mock.On("Supply", ctx)}
+}
+
+func (_c *ReconciliationService_Supply_Call) Run(run func(ctx context.Context)) *ReconciliationService_Supply_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *ReconciliationService_Supply_Call) Return(_a0 *service.SupplyReport, _a1 error) *ReconciliationService_Supply_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ReconciliationService_Supply_Call) RunAndReturn(run func(context.Context) (*service.SupplyReport, error)) *ReconciliationService_Supply_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewReconciliationService creates a new instance of ReconciliationService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewReconciliationService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ReconciliationService {
+	mock := &ReconciliationService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,89 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	service "internal-transfers/internal/service"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// StatementService is an autogenerated mock type for the StatementService type
+type StatementService struct {
+	mock.Mock
+}
+
+type StatementService_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *StatementService) EXPECT() *StatementService_Expecter {
+	return &StatementService_Expecter{mock: &_m.Mock}
+}
+
+// WriteStatement provides a mock function with given fields: ctx, accountID, from, to, w
+func (_m *StatementService) WriteStatement(ctx context.Context, accountID int64, from time.Time, to time.Time, w service.StatementWriter) error {
+	ret := _m.Called(ctx, accountID, from, to, w)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WriteStatement")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time, time.Time, service.StatementWriter) error); ok {
+		r0 = rf(ctx, accountID, from, to, w)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StatementService_WriteStatement_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WriteStatement'
+type StatementService_WriteStatement_Call struct {
+	*mock.Call
+}
+
+// WriteStatement is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID int64
+//   - from time.Time
+//   - to time.Time
+//   - w service.StatementWriter
+func (_e *StatementService_Expecter) WriteStatement(ctx interface{}, accountID interface{}, from interface{}, to interface{}, w interface{}) *StatementService_WriteStatement_Call {
+	return &StatementService_WriteStatement_Call{Call: _e.mock.On("WriteStatement", ctx, accountID, from, to, w)}
+}
+
+func (_c *StatementService_WriteStatement_Call) Run(run func(ctx context.Context, accountID int64, from time.Time, to time.Time, w service.StatementWriter)) *StatementService_WriteStatement_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(time.Time), args[3].(time.Time), args[4].(service.StatementWriter))
+	})
+	return _c
+}
+
+func (_c *StatementService_WriteStatement_Call) Return(_a0 error) *StatementService_WriteStatement_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *StatementService_WriteStatement_Call) RunAndReturn(run func(context.Context, int64, time.Time, time.Time, service.StatementWriter) error) *StatementService_WriteStatement_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewStatementService creates a new instance of StatementService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStatementService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *StatementService {
+	mock := &StatementService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -4,8 +4,13 @@ package mocks
 
 import (
 	context "context"
+	model "internal-transfers/internal/model"
 
 	mock "github.com/stretchr/testify/mock"
+
+	repository "internal-transfers/internal/repository"
+
+	service "internal-transfers/internal/service"
 )
 
 // TransactionService is an autogenerated mock type for the TransactionService type
@@ -21,17 +26,138 @@ func (_m *TransactionService) EXPECT() *TransactionService_Expecter {
 	return &TransactionService_Expecter{mock: &_m.Mock}
 }
 
-// ProcessTransaction provides a mock function with given fields: ctx, sourceID, destID, amount
-func (_m *TransactionService) ProcessTransaction(ctx context.Context, sourceID int64, destID int64, amount float64) error {
-	ret := _m.Called(ctx, sourceID, destID, amount)
+// Deposit provides a mock function with given fields: ctx, accountID, source, reference, amount
+func (_m *TransactionService) Deposit(ctx context.Context, accountID int64, source string, reference string, amount float64) (*model.Transaction, error) {
+	ret := _m.Called(ctx, accountID, source, reference, amount)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Deposit")
+	}
+
+	var r0 *model.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string, float64) (*model.Transaction, error)); ok {
+		return rf(ctx, accountID, source, reference, amount)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string, float64) *model.Transaction); ok {
+		r0 = rf(ctx, accountID, source, reference, amount)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, string, float64) error); ok {
+		r1 = rf(ctx, accountID, source, reference, amount)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TransactionService_Deposit_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Deposit'
+type TransactionService_Deposit_Call struct {
+	*mock.Call
+}
+
+// Deposit is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID int64
+//   - source string
+//   - reference string
+//   - amount float64
+func (_e *TransactionService_Expecter) Deposit(ctx interface{}, accountID interface{}, source interface{}, reference interface{}, amount interface{}) *TransactionService_Deposit_Call {
+	return &TransactionService_Deposit_Call{Call: _e.mock.On("Deposit", ctx, accountID, source, reference, amount)}
+}
+
+func (_c *TransactionService_Deposit_Call) Run(run func(ctx context.Context, accountID int64, source string, reference string, amount float64)) *TransactionService_Deposit_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(string), args[3].(string), args[4].(float64))
+	})
+	return _c
+}
+
+func (_c *TransactionService_Deposit_Call) Return(_a0 *model.Transaction, _a1 error) *TransactionService_Deposit_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *TransactionService_Deposit_Call) RunAndReturn(run func(context.Context, int64, string, string, float64) (*model.Transaction, error)) *TransactionService_Deposit_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListTransactions provides a mock function with given fields: ctx, filter
+func (_m *TransactionService) ListTransactions(ctx context.Context, filter repository.TransactionFilter) ([]*model.Transaction, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTransactions")
+	}
+
+	var r0 []*model.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, repository.TransactionFilter) ([]*model.Transaction, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, repository.TransactionFilter) []*model.Transaction); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, repository.TransactionFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TransactionService_ListTransactions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListTransactions'
+type TransactionService_ListTransactions_Call struct {
+	*mock.Call
+}
+
+// ListTransactions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter repository.TransactionFilter
+func (_e *TransactionService_Expecter) ListTransactions(ctx interface{}, filter interface{}) *TransactionService_ListTransactions_Call {
+	return &TransactionService_ListTransactions_Call{Call: _e.mock.On("ListTransactions", ctx, filter)}
+}
+
+func (_c *TransactionService_ListTransactions_Call) Run(run func(ctx context.Context, filter repository.TransactionFilter)) *TransactionService_ListTransactions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(repository.TransactionFilter))
+	})
+	return _c
+}
+
+func (_c *TransactionService_ListTransactions_Call) Return(_a0 []*model.Transaction, _a1 error) *TransactionService_ListTransactions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *TransactionService_ListTransactions_Call) RunAndReturn(run func(context.Context, repository.TransactionFilter) ([]*model.Transaction, error)) *TransactionService_ListTransactions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ProcessTransaction provides a mock function with given fields: ctx, sourceID, destID, amount, details
+func (_m *TransactionService) ProcessTransaction(ctx context.Context, sourceID int64, destID int64, amount float64, details service.TransferDetails) error {
+	ret := _m.Called(ctx, sourceID, destID, amount, details)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ProcessTransaction")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, float64) error); ok {
-		r0 = rf(ctx, sourceID, destID, amount)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, float64, service.TransferDetails) error); ok {
+		r0 = rf(ctx, sourceID, destID, amount, details)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -49,13 +175,14 @@ type TransactionService_ProcessTransaction_Call struct {
 //   - sourceID int64
 //   - destID int64
 //   - amount float64
-func (_e *TransactionService_Expecter) ProcessTransaction(ctx interface{}, sourceID interface{}, destID interface{}, amount interface{}) *TransactionService_ProcessTransaction_Call {
-	return &TransactionService_ProcessTransaction_Call{Call: _e.mock.On("ProcessTransaction", ctx, sourceID, destID, amount)}
+//   - details service.TransferDetails
+func (_e *TransactionService_Expecter) ProcessTransaction(ctx interface{}, sourceID interface{}, destID interface{}, amount interface{}, details interface{}) *TransactionService_ProcessTransaction_Call {
+	return &TransactionService_ProcessTransaction_Call{Call: _e.mock.On("ProcessTransaction", ctx, sourceID, destID, amount, details)}
 }
 
-func (_c *TransactionService_ProcessTransaction_Call) Run(run func(ctx context.Context, sourceID int64, destID int64, amount float64)) *TransactionService_ProcessTransaction_Call {
+func (_c *TransactionService_ProcessTransaction_Call) Run(run func(ctx context.Context, sourceID int64, destID int64, amount float64, details service.TransferDetails)) *TransactionService_ProcessTransaction_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int64), args[2].(int64), args[3].(float64))
+		run(args[0].(context.Context), args[1].(int64), args[2].(int64), args[3].(float64), args[4].(service.TransferDetails))
 	})
 	return _c
 }
@@ -65,7 +192,129 @@ func (_c *TransactionService_ProcessTransaction_Call) Return(_a0 error) *Transac
 	return _c
 }
 
-func (_c *TransactionService_ProcessTransaction_Call) RunAndReturn(run func(context.Context, int64, int64, float64) error) *TransactionService_ProcessTransaction_Call {
+func (_c *TransactionService_ProcessTransaction_Call) RunAndReturn(run func(context.Context, int64, int64, float64, service.TransferDetails) error) *TransactionService_ProcessTransaction_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// TransferMulti provides a mock function with given fields: ctx, legs, details
+func (_m *TransactionService) TransferMulti(ctx context.Context, legs []model.TransferLeg, details service.TransferDetails) (*model.TransferGroup, error) {
+	ret := _m.Called(ctx, legs, details)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TransferMulti")
+	}
+
+	var r0 *model.TransferGroup
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []model.TransferLeg, service.TransferDetails) (*model.TransferGroup, error)); ok {
+		return rf(ctx, legs, details)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []model.TransferLeg, service.TransferDetails) *model.TransferGroup); ok {
+		r0 = rf(ctx, legs, details)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.TransferGroup)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []model.TransferLeg, service.TransferDetails) error); ok {
+		r1 = rf(ctx, legs, details)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TransactionService_TransferMulti_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TransferMulti'
+type TransactionService_TransferMulti_Call struct {
+	*mock.Call
+}
+
+// TransferMulti is a helper method to define mock.On call
+//   - ctx context.Context
+//   - legs []model.TransferLeg
+//   - details service.TransferDetails
+func (_e *TransactionService_Expecter) TransferMulti(ctx interface{}, legs interface{}, details interface{}) *TransactionService_TransferMulti_Call {
+	return &TransactionService_TransferMulti_Call{Call: _e.mock.On("TransferMulti", ctx, legs, details)}
+}
+
+func (_c *TransactionService_TransferMulti_Call) Run(run func(ctx context.Context, legs []model.TransferLeg, details service.TransferDetails)) *TransactionService_TransferMulti_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]model.TransferLeg), args[2].(service.TransferDetails))
+	})
+	return _c
+}
+
+func (_c *TransactionService_TransferMulti_Call) Return(_a0 *model.TransferGroup, _a1 error) *TransactionService_TransferMulti_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *TransactionService_TransferMulti_Call) RunAndReturn(run func(context.Context, []model.TransferLeg, service.TransferDetails) (*model.TransferGroup, error)) *TransactionService_TransferMulti_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Withdraw provides a mock function with given fields: ctx, accountID, source, reference, amount
+func (_m *TransactionService) Withdraw(ctx context.Context, accountID int64, source string, reference string, amount float64) (*model.Transaction, error) {
+	ret := _m.Called(ctx, accountID, source, reference, amount)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Withdraw")
+	}
+
+	var r0 *model.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string, float64) (*model.Transaction, error)); ok {
+		return rf(ctx, accountID, source, reference, amount)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string, float64) *model.Transaction); ok {
+		r0 = rf(ctx, accountID, source, reference, amount)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, string, float64) error); ok {
+		r1 = rf(ctx, accountID, source, reference, amount)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TransactionService_Withdraw_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Withdraw'
+type TransactionService_Withdraw_Call struct {
+	*mock.Call
+}
+
+// Withdraw is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID int64
+//   - source string
+//   - reference string
+//   - amount float64
+func (_e *TransactionService_Expecter) Withdraw(ctx interface{}, accountID interface{}, source interface{}, reference interface{}, amount interface{}) *TransactionService_Withdraw_Call {
+	return &TransactionService_Withdraw_Call{Call: _e.mock.On("Withdraw", ctx, accountID, source, reference, amount)}
+}
+
+func (_c *TransactionService_Withdraw_Call) Run(run func(ctx context.Context, accountID int64, source string, reference string, amount float64)) *TransactionService_Withdraw_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(string), args[3].(string), args[4].(float64))
+	})
+	return _c
+}
+
+func (_c *TransactionService_Withdraw_Call) Return(_a0 *model.Transaction, _a1 error) *TransactionService_Withdraw_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *TransactionService_Withdraw_Call) RunAndReturn(run func(context.Context, int64, string, string, float64) (*model.Transaction, error)) *TransactionService_Withdraw_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"internal-transfers/internal/domain"
+	"internal-transfers/internal/model"
+	"internal-transfers/internal/repository"
+)
+
+// CloseJobCaller is recorded as the closer of periods closed by the end-of-day job
+const CloseJobCaller = "eod-close"
+
+//go:generate mockery --name=PeriodService --filename=period_mock.go --output=./mocks --with-expecter
+type PeriodService interface {
+	// ListPeriods returns every period from the day starting at from to the one starting at to, oldest first
+	ListPeriods(ctx context.Context, from, to time.Time) ([]*model.Period, error)
+	// ClosePeriod snapshots every balance at the end of the period starting at day, records the trial balance
+	// and closes the period to backdated entries
+	ClosePeriod(ctx context.Context, day time.Time) (*model.Period, error)
+	// CloseDue closes, oldest first, every open period ending at or before through; before any period was
+	// closed it only closes the one ending at through
+	CloseDue(ctx context.Context, through time.Time) error
+}
+
+type periodService struct {
+	repo repository.PeriodRepository
+	uow  repository.UnitOfWork
+	// closeDelay is how long after its end a period can be closed. Entries that are not backdated do not take
+	// the period lock, so the delay leaves time for those stamped just before the end to commit.
+	closeDelay time.Duration
+}
+
+func NewPeriodService(repo repository.PeriodRepository, uow repository.UnitOfWork, closeDelay time.Duration) PeriodService {
+	return &periodService{repo: repo, uow: uow, closeDelay: closeDelay}
+}
+
+func (s *periodService) ListPeriods(ctx context.Context, from, to time.Time) ([]*model.Period, error) {
+	lastClosed, err := s.repo.LastClosed(ctx)
+	if err != nil {
+		return nil, err
+	}
+	closed, err := s.repo.ListPeriods(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var periods []*model.Period
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		if len(closed) > 0 && closed[0].Date.Equal(day) {
+			periods = append(periods, closed[0])
+			closed = closed[1:]
+			continue
+		}
+		// days before the first close are locked without a close of their own
+		periods = append(periods, &model.Period{Date: day, Closed: !lastClosed.IsZero() && !day.After(lastClosed)})
+	}
+	return periods, nil
+}
+
+// ClosePeriod requires the period to have ended at least closeDelay ago and the previous one to be closed,
+// unless none is. It holds the period lock, so backdated entries into the period are either committed before
+// its balances are snapshotted or rejected.
+func (s *periodService) ClosePeriod(ctx context.Context, day time.Time) (*model.Period, error) {
+	ctx, span := tracer.Start(ctx, "PeriodService.ClosePeriod", trace.WithAttributes(
+		attribute.String("period.date", day.Format(time.DateOnly)),
+	))
+
+	period := &model.Period{Date: day.UTC().Truncate(24 * time.Hour), Closed: true, ClosedBy: domain.CallerFromContext(ctx)}
+	err := s.uow.WithinTx(ctx, func(ctx context.Context, repos repository.Repositories) error {
+		return closePeriod(ctx, repos, period, s.closeDelay)
+	})
+	outcome := periodOutcome(err)
+	endSpan(span, outcome, err, outcome == "error")
+	if err != nil {
+		return nil, err
+	}
+
+	tb := period.TrialBalance
+	event := log.Ctx(ctx).Info()
+	if cents(tb.Debits) != cents(tb.Credits) {
+		event = log.Ctx(ctx).Warn()
+	}
+	event.Str("period", period.Date.Format(time.DateOnly)).Float64("debits", tb.Debits).Float64("credits", tb.Credits).
+		Msg("accounting period closed")
+	return period, nil
+}
+
+func closePeriod(ctx context.Context, repos repository.Repositories, period *model.Period, delay time.Duration) error {
+	if closable := period.End().Add(delay); closable.After(time.Now()) {
+		return fmt.Errorf("%w: %s can be closed from %s", domain.ErrPeriodNotClosable, period.Date.Format(time.DateOnly),
+			closable.UTC().Format(time.RFC3339))
+	}
+	if err := repos.Periods.LockExclusive(ctx); err != nil {
+		return err
+	}
+	lastClosed, err := repos.Periods.LastClosed(ctx)
+	if err != nil {
+		return err
+	}
+	if !lastClosed.IsZero() {
+		if !period.Date.After(lastClosed) {
+			return fmt.Errorf("%w: periods through %s are closed", domain.ErrPeriodClosed, lastClosed.Format(time.DateOnly))
+		}
+		if next := lastClosed.AddDate(0, 0, 1); period.Date.After(next) {
+			return fmt.Errorf("%w: %s must be closed first", domain.ErrPeriodNotClosable, next.Format(time.DateOnly))
+		}
+	}
+
+	if _, err := repos.Balances.CreateSnapshots(ctx, period.End()); err != nil {
+		return err
+	}
+	tb, err := repos.Periods.TrialBalance(ctx, period.End())
+	if err != nil {
+		return err
+	}
+	period.TrialBalance = tb
+	period.ClosedAt = time.Now()
+	return repos.Periods.ClosePeriod(ctx, period)
+}
+
+func (s *periodService) CloseDue(ctx context.Context, through time.Time) error {
+	through = through.UTC().Truncate(24 * time.Hour)
+	lastClosed, err := s.repo.LastClosed(ctx)
+	if err != nil {
+		return err
+	}
+	day := through.AddDate(0, 0, -1)
+	if !lastClosed.IsZero() {
+		day = lastClosed.AddDate(0, 0, 1)
+	}
+
+	ctx = domain.WithCaller(ctx, CloseJobCaller)
+	for ; day.Before(through); day = day.AddDate(0, 0, 1) {
+		_, err := s.ClosePeriod(ctx, day)
+		// another instance closed it first
+		if errors.Is(err, domain.ErrPeriodClosed) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("close period %s: %w", day.Format(time.DateOnly), err)
+		}
+	}
+	return nil
+}
+
+// periodOutcome maps the result of a period operation to its span outcome
+func periodOutcome(err error) string {
+	switch {
+	case err == nil:
+		return "success"
+	case errors.Is(err, domain.ErrPeriodClosed):
+		return "closed"
+	case errors.Is(err, domain.ErrPeriodNotClosable):
+		return "not_closable"
+	default:
+		return "error"
+	}
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"internal-transfers/internal/domain"
+	"internal-transfers/internal/model"
+	"internal-transfers/internal/repository/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPeriodTestSetup(t *testing.T, closeDelay time.Duration) (TransactionService, PeriodService) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	accRepo := memory.NewAccountRepository(store)
+	opened := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, accRepo.CreateAccount(ctx, &model.Account{AccountID: 1, Balance: 100, CreatedAt: opened}))
+	require.NoError(t, accRepo.CreateAccount(ctx, &model.Account{AccountID: 2, Balance: 50, CreatedAt: opened}))
+
+	uow := memory.NewUnitOfWork(store)
+	return NewTransactionService(memory.NewTransactionRepository(store), uow, nil),
+		NewPeriodService(memory.NewPeriodRepository(store), uow, closeDelay)
+}
+
+func TestPeriodService_ClosePeriod(t *testing.T) {
+	ctx := domain.WithCaller(context.Background(), "finance")
+	transfers, periods := newPeriodTestSetup(t, 5*time.Minute)
+	day := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, transfers.ProcessTransaction(ctx, 1, 2, 10, TransferDetails{EffectiveAt: day.Add(10 * time.Hour)}))
+
+	t.Run("records the trial balance at the end of the period", func(t *testing.T) {
+		period, err := periods.ClosePeriod(ctx, day)
+
+		require.NoError(t, err)
+		assert.Equal(t, day, period.Date)
+		assert.True(t, period.Closed)
+		assert.Equal(t, "finance", period.ClosedBy)
+		assert.Equal(t, &model.TrialBalance{
+			Lines:           []model.TrialBalanceLine{{AccountType: model.AccountCustomer, Accounts: 2, Credit: 150}},
+			OpeningBalances: model.TrialBalanceLine{Accounts: 2, Debit: 150},
+			Debits:          150,
+			Credits:         150,
+		}, period.TrialBalance)
+	})
+
+	t.Run("rejects backdated entries into closed periods", func(t *testing.T) {
+		err := transfers.ProcessTransaction(ctx, 1, 2, 10, TransferDetails{EffectiveAt: day.Add(12 * time.Hour)})
+		assert.ErrorIs(t, err, domain.ErrPeriodClosed)
+
+		_, err = transfers.TransferMulti(ctx, []model.TransferLeg{{AccountID: 1, Amount: -5}, {AccountID: 2, Amount: 5}},
+			TransferDetails{EffectiveAt: day.AddDate(0, 0, -3)})
+		assert.ErrorIs(t, err, domain.ErrPeriodClosed)
+	})
+
+	t.Run("accepts backdated entries into open periods", func(t *testing.T) {
+		err := transfers.ProcessTransaction(ctx, 1, 2, 10, TransferDetails{EffectiveAt: day.AddDate(0, 0, 1)})
+		assert.NoError(t, err)
+	})
+
+	t.Run("closes periods in order", func(t *testing.T) {
+		_, err := periods.ClosePeriod(ctx, day)
+		assert.ErrorIs(t, err, domain.ErrPeriodClosed)
+
+		_, err = periods.ClosePeriod(ctx, day.AddDate(0, 0, 2))
+		assert.ErrorIs(t, err, domain.ErrPeriodNotClosable)
+
+		_, err = periods.ClosePeriod(ctx, time.Now().UTC().Truncate(24*time.Hour))
+		assert.ErrorIs(t, err, domain.ErrPeriodNotClosable)
+	})
+
+	t.Run("lists closed and open periods", func(t *testing.T) {
+		list, err := periods.ListPeriods(ctx, day.AddDate(0, 0, -1), day.AddDate(0, 0, 1))
+
+		require.NoError(t, err)
+		require.Len(t, list, 3)
+		assert.True(t, list[0].Closed)
+		assert.Nil(t, list[0].TrialBalance)
+		assert.True(t, list[1].Closed)
+		assert.NotNil(t, list[1].TrialBalance)
+		assert.False(t, list[2].Closed)
+		assert.Equal(t, day.AddDate(0, 0, 1), list[2].Date)
+	})
+}
+
+func TestPeriodService_ClosePeriod_WaitsForTheCloseDelay(t *testing.T) {
+	// given
+	ctx := context.Background()
+	_, periods := newPeriodTestSetup(t, 48*time.Hour)
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	// when the period ended less than the delay ago
+	_, err := periods.ClosePeriod(ctx, today.AddDate(0, 0, -2))
+
+	// then
+	assert.ErrorIs(t, err, domain.ErrPeriodNotClosable)
+
+	// when it ended longer ago
+	_, err = periods.ClosePeriod(ctx, today.AddDate(0, 0, -3))
+
+	// then
+	assert.NoError(t, err)
+}
+
+func TestPeriodService_CloseDue(t *testing.T) {
+	ctx := context.Background()
+	_, periods := newPeriodTestSetup(t, 5*time.Minute)
+	day := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	t.Run("first run closes the previous day", func(t *testing.T) {
+		require.NoError(t, periods.CloseDue(ctx, day.Add(time.Hour)))
+
+		list, err := periods.ListPeriods(ctx, day.AddDate(0, 0, -1), day)
+		require.NoError(t, err)
+		assert.Equal(t, CloseJobCaller, list[0].ClosedBy)
+		assert.False(t, list[1].Closed)
+	})
+
+	t.Run("catches up on missed days", func(t *testing.T) {
+		require.NoError(t, periods.CloseDue(ctx, day.AddDate(0, 0, 3)))
+
+		list, err := periods.ListPeriods(ctx, day, day.AddDate(0, 0, 3))
+		require.NoError(t, err)
+		for _, p := range list[:3] {
+			assert.NotNil(t, p.TrialBalance, p.Date)
+		}
+		assert.False(t, list[3].Closed)
+	})
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+
+	"internal-transfers/internal/model"
+	"internal-transfers/internal/repository"
+)
+
+//go:generate mockery --name=ReconciliationService --filename=reconciliation_mock.go --output=./mocks --with-expecter
+type ReconciliationService interface {
+	Run(ctx context.Context) (*ReconciliationReport, error)
+	// Last returns the report of the latest completed run, or nil if there has been none
+	Last() *ReconciliationReport
+	Supply(ctx context.Context) (*SupplyReport, error)
+}
+
+// ReconciliationReport is the outcome of checking every account balance against its transaction history
+type ReconciliationReport struct {
+	StartedAt       time.Time
+	FinishedAt      time.Time
+	AccountsChecked int64
+	// AccountsUnverifiable counts the checked accounts that predate recorded initial balances and so cannot be
+	// reconciled
+	AccountsUnverifiable int64
+	Discrepancies        []Discrepancy
+}
+
+// Discrepancy is an account whose balance is not its initial balance plus its transactions
+type Discrepancy struct {
+	AccountID       int64
+	Balance         float64
+	ExpectedBalance float64
+	// Difference is Balance less ExpectedBalance
+	Difference float64
+}
+
+// SupplyReport accounts for the money held outside the mint account
+type SupplyReport struct {
+	AsOf        time.Time
+	Accounts    int64
+	Circulating float64
+	// Minted is the part of Circulating funded by the mint account
+	Minted float64
+	// Settled is the part of Circulating deposited through settlement accounts, net of withdrawals
+	Settled float64
+	// Unfunded is the part of Circulating that no transaction accounts for: initial balances of accounts
+	// opened while minting was disabled
+	Unfunded float64
+}
+
+type reconciliationService struct {
+	repo                 repository.ReconciliationRepository
+	mintAccountID        int64
+	settlementAccountIDs []int64
+
+	mu   sync.Mutex
+	last *ReconciliationReport
+}
+
+// NewReconciliationService returns a service auditing balances; mintAccountID is 0 when minting is disabled
+func NewReconciliationService(repo repository.ReconciliationRepository, mintAccountID int64, settlementAccountIDs []int64) ReconciliationService {
+	return &reconciliationService{repo: repo, mintAccountID: mintAccountID, settlementAccountIDs: settlementAccountIDs}
+}
+
+// Run recomputes every balance from the initial balance and all transactions and reports the accounts that
+// disagree with their stored balance; it only reads, so discrepancies are left for an operator to resolve
+func (s *reconciliationService) Run(ctx context.Context) (*ReconciliationReport, error) {
+	ctx, span := tracer.Start(ctx, "ReconciliationService.Run")
+
+	report := &ReconciliationReport{StartedAt: time.Now().UTC()}
+	check, err := s.repo.FindDiscrepancies(ctx)
+	if err != nil {
+		endSpan(span, "error", err, true)
+		return nil, err
+	}
+	report.FinishedAt = time.Now().UTC()
+	report.AccountsChecked = check.Checked
+	report.AccountsUnverifiable = check.Unverifiable
+	report.Discrepancies = discrepancies(check.Discrepancies)
+
+	span.SetAttributes(
+		attribute.Int64("reconciliation.accounts", check.Checked),
+		attribute.Int64("reconciliation.unverifiable", check.Unverifiable),
+		attribute.Int("reconciliation.discrepancies", len(check.Discrepancies)),
+	)
+	endSpan(span, "success", nil, false)
+
+	for _, d := range report.Discrepancies {
+		log.Ctx(ctx).Error().
+			Int64("account_id", d.AccountID).
+			Float64("balance", d.Balance).
+			Float64("expected_balance", d.ExpectedBalance).
+			Msg("balance does not match transaction history")
+	}
+	log.Ctx(ctx).Info().
+		Int64("accounts", check.Checked).
+		Int64("unverifiable", check.Unverifiable).
+		Int("discrepancies", len(check.Discrepancies)).
+		Msg("balance reconciliation finished")
+
+	s.mu.Lock()
+	s.last = report
+	s.mu.Unlock()
+	return report, nil
+}
+
+func (s *reconciliationService) Last() *ReconciliationReport {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.last
+}
+
+// Supply reports the total money in the system and how much of it the mint account funded
+func (s *reconciliationService) Supply(ctx context.Context) (*SupplyReport, error) {
+	ctx, span := tracer.Start(ctx, "ReconciliationService.Supply")
+
+	supply, err := s.repo.Supply(ctx, s.mintAccountID, s.settlementAccountIDs)
+	if err != nil {
+		endSpan(span, "error", err, true)
+		return nil, err
+	}
+	endSpan(span, "success", nil, false)
+
+	return &SupplyReport{
+		AsOf:        time.Now().UTC(),
+		Accounts:    supply.Accounts,
+		Circulating: supply.Circulating,
+		Minted:      supply.Minted,
+		Settled:     supply.Settled,
+		Unfunded:    supply.Circulating - supply.Minted - supply.Settled,
+	}, nil
+}
+
+func discrepancies(found []model.BalanceDiscrepancy) []Discrepancy {
+	out := make([]Discrepancy, 0, len(found))
+	for _, d := range found {
+		out = append(out, Discrepancy{
+			AccountID:       d.AccountID,
+			Balance:         d.Balance,
+			ExpectedBalance: d.ExpectedBalance,
+			Difference:      d.Balance - d.ExpectedBalance,
+		})
+	}
+	return out
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"internal-transfers/internal/model"
+	"internal-transfers/internal/repository/memory"
+	"internal-transfers/internal/repository/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconciliationService_Run(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("reports discrepancies and keeps the last report", func(t *testing.T) {
+		repo := mocks.NewReconciliationRepository(t)
+		repo.EXPECT().FindDiscrepancies(mock.Anything).Return(&model.BalanceCheck{
+			Checked:       3,
+			Unverifiable:  1,
+			Discrepancies: []model.BalanceDiscrepancy{{AccountID: 2, Balance: 110, ExpectedBalance: 100}},
+		}, nil)
+		svc := NewReconciliationService(repo, 0, nil)
+		assert.Nil(t, svc.Last())
+
+		report, err := svc.Run(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), report.AccountsChecked)
+		assert.Equal(t, int64(1), report.AccountsUnverifiable)
+		assert.Equal(t, []Discrepancy{{AccountID: 2, Balance: 110, ExpectedBalance: 100, Difference: 10}}, report.Discrepancies)
+		assert.Same(t, report, svc.Last())
+	})
+
+	t.Run("error keeps the previous report", func(t *testing.T) {
+		repo := mocks.NewReconciliationRepository(t)
+		repo.EXPECT().FindDiscrepancies(mock.Anything).Return(nil, errors.New("db down"))
+		svc := NewReconciliationService(repo, 0, nil)
+
+		_, err := svc.Run(ctx)
+		assert.ErrorContains(t, err, "db down")
+		assert.Nil(t, svc.Last())
+	})
+}
+
+func TestReconciliationService_Run_InMemory(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	accRepo := memory.NewAccountRepository(store)
+	require.NoError(t, accRepo.CreateAccount(ctx, &model.Account{AccountID: 1, Balance: 100}))
+	require.NoError(t, accRepo.CreateAccount(ctx, &model.Account{AccountID: 2, Balance: 50}))
+	require.NoError(t, NewTransactionService(memory.NewTransactionRepository(store), memory.NewUnitOfWork(store), nil).ProcessTransaction(ctx, 1, 2, 30, TransferDetails{}))
+	svc := NewReconciliationService(memory.NewReconciliationRepository(store), 0, nil)
+
+	t.Run("balances match", func(t *testing.T) {
+		report, err := svc.Run(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), report.AccountsChecked)
+		assert.Empty(t, report.Discrepancies)
+	})
+
+	t.Run("sub-cent float error is no discrepancy", func(t *testing.T) {
+		require.NoError(t, accRepo.UpdateBalance(ctx, 2, 80.000000001))
+
+		report, err := svc.Run(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, report.Discrepancies)
+	})
+
+	t.Run("balance written without a transaction", func(t *testing.T) {
+		require.NoError(t, accRepo.UpdateBalance(ctx, 2, 90))
+
+		report, err := svc.Run(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []Discrepancy{{AccountID: 2, Balance: 90, ExpectedBalance: 80, Difference: 10}}, report.Discrepancies)
+	})
+}
+
+func TestReconciliationService_Supply(t *testing.T) {
+	repo := mocks.NewReconciliationRepository(t)
+	repo.EXPECT().Supply(mock.Anything, int64(1000), []int64{9001}).
+		Return(&model.Supply{Accounts: 3, Circulating: 250, Minted: 200, Settled: 30}, nil)
+
+	report, err := NewReconciliationService(repo, 1000, []int64{9001}).Supply(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), report.Accounts)
+	assert.Equal(t, 250.0, report.Circulating)
+	assert.Equal(t, 200.0, report.Minted)
+	assert.Equal(t, 30.0, report.Settled)
+	assert.Equal(t, 20.0, report.Unfunded)
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"internal-transfers/internal/domain"
+	"internal-transfers/internal/model"
+	"internal-transfers/internal/repository"
+)
+
+//go:generate mockery --name=StatementService --filename=statement_mock.go --output=./mocks --with-expecter
+type StatementService interface {
+	WriteStatement(ctx context.Context, accountID int64, from, to time.Time, w StatementWriter) error
+}
+
+// StatementWriter receives a statement as it is read from storage, so a long period is never held in memory.
+// Opening is called first, then Entry for every transaction oldest first, then Closing.
+type StatementWriter interface {
+	Opening(header StatementHeader) error
+	Entry(entry StatementEntry) error
+	Closing(balance float64) error
+}
+
+type StatementHeader struct {
+	AccountID int64
+	// From and To bound the period; From is inclusive and To exclusive
+	From           time.Time
+	To             time.Time
+	OpeningBalance float64
+}
+
+type StatementEntry struct {
+	TransactionID         int64
+	CreatedAt             time.Time
+	CounterpartyAccountID int64
+	// Amount is positive for money received and negative for money sent
+	Amount float64
+	// Balance is the running balance after this entry
+	Balance float64
+}
+
+type statementService struct {
+	txRepo repository.TransactionRepository
+}
+
+func NewStatementService(txRepo repository.TransactionRepository) StatementService {
+	return &statementService{txRepo: txRepo}
+}
+
+// WriteStatement streams the statement of an account for [from, to) to w
+func (s *statementService) WriteStatement(ctx context.Context, accountID int64, from, to time.Time, w StatementWriter) error {
+	if !from.Before(to) {
+		return domain.ErrInvalidPeriod
+	}
+
+	ctx, span := tracer.Start(ctx, "StatementService.WriteStatement", trace.WithAttributes(
+		attribute.Int64("account.id", accountID),
+		attribute.String("statement.from", from.Format(time.RFC3339)),
+		attribute.String("statement.to", to.Format(time.RFC3339)),
+	))
+
+	var balance float64
+	entries := 0
+	err := s.txRepo.StreamStatement(ctx, accountID, from, to,
+		func(opening float64) error {
+			balance = opening
+			return w.Opening(StatementHeader{AccountID: accountID, From: from, To: to, OpeningBalance: opening})
+		},
+		func(tx *model.Transaction) error {
+			entry := StatementEntry{TransactionID: tx.TransactionID, CreatedAt: tx.CreatedAt}
+			if tx.DestinationAccountID == accountID {
+				entry.CounterpartyAccountID, entry.Amount = tx.SourceAccountID, tx.Amount
+			} else {
+				entry.CounterpartyAccountID, entry.Amount = tx.DestinationAccountID, -tx.Amount
+			}
+			balance += entry.Amount
+			entry.Balance = balance
+			entries++
+			return w.Entry(entry)
+		},
+	)
+	if err == nil {
+		err = w.Closing(balance)
+	}
+
+	span.SetAttributes(attribute.Int("statement.entries", entries))
+	outcome := accountOutcome(err)
+	endSpan(span, outcome, err, outcome == "error")
+	return err
+}
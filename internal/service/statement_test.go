@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"internal-transfers/internal/domain"
+	"internal-transfers/internal/model"
+	"internal-transfers/internal/repository/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingWriter keeps everything written to it
+type recordingWriter struct {
+	header  StatementHeader
+	entries []StatementEntry
+	closing float64
+}
+
+func (w *recordingWriter) Opening(header StatementHeader) error { w.header = header; return nil }
+func (w *recordingWriter) Entry(entry StatementEntry) error {
+	w.entries = append(w.entries, entry)
+	return nil
+}
+func (w *recordingWriter) Closing(balance float64) error { w.closing = balance; return nil }
+
+func TestStatementService_WriteStatement(t *testing.T) {
+	ctx := context.Background()
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	t.Run("running balance", func(t *testing.T) {
+		// given
+		repo := mocks.NewTransactionRepository(t)
+		service := NewStatementService(repo)
+		repo.EXPECT().
+			StreamStatement(mock.Anything, int64(1), from, to, mock.Anything, mock.Anything).
+			RunAndReturn(func(ctx context.Context, accountID int64, from, to time.Time,
+				opening func(float64) error, entry func(*model.Transaction) error) error {
+				require.NoError(t, opening(100))
+				require.NoError(t, entry(&model.Transaction{TransactionID: 7, SourceAccountID: 2, DestinationAccountID: 1, Amount: 50}))
+				return entry(&model.Transaction{TransactionID: 8, SourceAccountID: 1, DestinationAccountID: 3, Amount: 30})
+			})
+		w := &recordingWriter{}
+
+		// when
+		err := service.WriteStatement(ctx, 1, from, to, w)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, StatementHeader{AccountID: 1, From: from, To: to, OpeningBalance: 100}, w.header)
+		assert.Equal(t, []StatementEntry{
+			{TransactionID: 7, CounterpartyAccountID: 2, Amount: 50, Balance: 150},
+			{TransactionID: 8, CounterpartyAccountID: 3, Amount: -30, Balance: 120},
+		}, w.entries)
+		assert.Equal(t, 120.0, w.closing)
+	})
+
+	t.Run("invalid period", func(t *testing.T) {
+		// given
+		service := NewStatementService(mocks.NewTransactionRepository(t))
+
+		// when
+		err := service.WriteStatement(ctx, 1, to, from, &recordingWriter{})
+
+		// then
+		assert.ErrorIs(t, err, domain.ErrInvalidPeriod)
+	})
+
+	t.Run("account not found", func(t *testing.T) {
+		// given
+		repo := mocks.NewTransactionRepository(t)
+		service := NewStatementService(repo)
+		repo.EXPECT().
+			StreamStatement(mock.Anything, int64(1), from, to, mock.Anything, mock.Anything).
+			Return(domain.ErrAccountNotFound)
+
+		// when
+		err := service.WriteStatement(ctx, 1, from, to, &recordingWriter{})
+
+		// then
+		assert.ErrorIs(t, err, domain.ErrAccountNotFound)
+	})
+}
@@ -0,0 +1,20 @@
+package service
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("internal-transfers/internal/service")
+
+// endSpan tags span with the operation outcome, marking it failed for unexpected errors, and ends it
+func endSpan(span trace.Span, outcome string, err error, unexpected bool) {
+	span.SetAttributes(attribute.String("outcome", outcome))
+	if err != nil && unexpected {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -2,100 +2,537 @@ package service
 
 import (
 	"context"
-	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"internal-transfers/internal/domain"
+	"maps"
+	"math"
+	"slices"
 	"time"
+	"unicode/utf8"
 
+	"internal-transfers/internal/metrics"
 	"internal-transfers/internal/model"
 	"internal-transfers/internal/repository"
+
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 //go:generate mockery --name=TransactionService --filename=transaction_mock.go --output=./mocks --with-expecter
 type TransactionService interface {
-	ProcessTransaction(ctx context.Context, sourceID, destID int64, amount float64) error
+	ProcessTransaction(ctx context.Context, sourceID, destID int64, amount float64, details TransferDetails) error
+	TransferMulti(ctx context.Context, legs []model.TransferLeg, details TransferDetails) (*model.TransferGroup, error)
+	ListTransactions(ctx context.Context, filter repository.TransactionFilter) ([]*model.Transaction, error)
+	Deposit(ctx context.Context, accountID int64, source, reference string, amount float64) (*model.Transaction, error)
+	Withdraw(ctx context.Context, accountID int64, source, reference string, amount float64) (*model.Transaction, error)
 }
 
-type transactionService struct {
-	txRepo  repository.TransactionRepository
-	accRepo repository.AccountRepository
-	db      *sql.DB // for transaction control
+// Limits on the details callers attach to a transfer
+const (
+	maxDescriptionLength       = 500
+	maxExternalReferenceLength = 128
+	maxMetadataBytes           = 4096
+	// maxTransferLegs bounds how many accounts one multi-leg transfer locks
+	maxTransferLegs = 50
+)
+
+// TransferDetails describes a transfer beyond its accounts and amount; all fields are optional
+type TransferDetails struct {
+	// Type defaults to transfer and must be one of model.TransferTypes
+	Type              model.TransactionType
+	Description       string
+	Metadata          map[string]string
+	ExternalReference string
+	// EffectiveAt backdates the entries to a time after the last closed period; zero records them now
+	EffectiveAt time.Time
+	// system marks a posting the ledger makes itself, such as interest paid from the expense account. It may
+	// use any transaction type and overdraw a source whose account type allows it; transfers requested by
+	// callers always need the funds.
+	system bool
+	// externalSource records the posting once per source and ExternalReference, like a settlement
+	externalSource string
 }
 
-func NewTransactionService(txRepo repository.TransactionRepository, accRepo repository.AccountRepository, db *sql.DB) TransactionService {
-	return &transactionService{
-		txRepo:  txRepo,
-		accRepo: accRepo,
-		db:      db,
+func (d TransferDetails) validate() error {
+	if d.Type != "" && !d.system && !slices.Contains(model.TransferTypes, d.Type) {
+		return fmt.Errorf("%w: type %q cannot be used for a transfer", domain.ErrInvalidTransaction, d.Type)
+	}
+	if utf8.RuneCountInString(d.Description) > maxDescriptionLength {
+		return fmt.Errorf("%w: description is longer than %d characters", domain.ErrInvalidTransaction, maxDescriptionLength)
+	}
+	if len(d.ExternalReference) > maxExternalReferenceLength {
+		return fmt.Errorf("%w: external_reference is longer than %d bytes", domain.ErrInvalidTransaction, maxExternalReferenceLength)
+	}
+	if len(d.Metadata) > 0 {
+		b, err := json.Marshal(d.Metadata)
+		if err != nil || len(b) > maxMetadataBytes {
+			return fmt.Errorf("%w: metadata is larger than %d bytes", domain.ErrInvalidTransaction, maxMetadataBytes)
+		}
 	}
+	if d.EffectiveAt.After(time.Now()) {
+		return fmt.Errorf("%w: effective_at is in the future", domain.ErrInvalidTransaction)
+	}
+	return nil
+}
+
+type transactionService struct {
+	repo repository.TransactionRepository
+	uow  repository.UnitOfWork
+	// settlements maps each external source to the account its deposits and withdrawals clear through
+	settlements map[string]int64
+}
+
+func NewTransactionService(repo repository.TransactionRepository, uow repository.UnitOfWork, settlements map[string]int64) TransactionService {
+	return &transactionService{repo: repo, uow: uow, settlements: settlements}
 }
 
-// ProcessTransaction processes a funds transfer between accounts ensuring atomicity
-func (s *transactionService) ProcessTransaction(ctx context.Context, sourceID, destID int64, amount float64) error {
+// ProcessTransaction processes a funds transfer between accounts ensuring atomicity. The amount is rounded to the
+// cent like the legs of a multi-leg transfer.
+func (s *transactionService) ProcessTransaction(ctx context.Context, sourceID, destID int64, amount float64, details TransferDetails) error {
+	amount = float64(cents(amount)) / 100
 	if amount <= 0 {
 		return fmt.Errorf("amount must be positive")
 	}
+	if sourceID == destID {
+		return domain.ErrSameAccount
+	}
+	if err := details.validate(); err != nil {
+		return err
+	}
+	if details.Type == "" {
+		details.Type = model.TransactionTransfer
+	}
+
+	ctx, span := tracer.Start(ctx, "TransactionService.ProcessTransaction", trace.WithAttributes(
+		attribute.Int64("account.source_id", sourceID),
+		attribute.Int64("account.destination_id", destID),
+		attribute.Float64("amount", amount),
+	))
 
-	tx, err := s.db.BeginTx(ctx, nil)
+	transaction := &model.Transaction{
+		SourceAccountID:      sourceID,
+		DestinationAccountID: destID,
+		Amount:               amount,
+		Type:                 details.Type,
+		Description:          details.Description,
+		Metadata:             details.Metadata,
+		ExternalSource:       details.externalSource,
+		ExternalReference:    details.ExternalReference,
+	}
+	err := s.uow.WithinTx(ctx, func(ctx context.Context, repos repository.Repositories) error {
+		return transfer(ctx, repos, transaction, details.EffectiveAt, details.system)
+	})
+	outcome := transferOutcome(err)
+	metrics.ObserveTransfer(outcome, amount)
+	endSpan(span, outcome, err, outcome == metrics.OutcomeError)
 	if err != nil {
 		return err
 	}
 
-	defer func() {
-		if p := recover(); p != nil {
-			_ = tx.Rollback()
-			panic(p)
+	log.Ctx(ctx).Debug().
+		Int64("transaction_id", transaction.TransactionID).
+		Int64("source_account_id", sourceID).
+		Int64("destination_account_id", destID).
+		Float64("amount", amount).
+		Msg("transaction committed")
+	return nil
+}
+
+// TransferMulti atomically debits the legs with negative amounts and credits those with positive amounts. The
+// legs must net to zero and name each account once. Each debited leg is paired with credited legs in the order
+// given and every pair is recorded as a transaction of the group, so a single debit split across several
+// credits becomes one transaction per credit.
+func (s *transactionService) TransferMulti(ctx context.Context, legs []model.TransferLeg, details TransferDetails) (*model.TransferGroup, error) {
+	if err := validateLegs(legs); err != nil {
+		return nil, err
+	}
+	if err := details.validate(); err != nil {
+		return nil, err
+	}
+	if details.Type == "" {
+		details.Type = model.TransactionTransfer
+	}
+
+	var debited float64
+	for _, leg := range legs {
+		debited += max(-leg.Amount, 0)
+	}
+	ctx, span := tracer.Start(ctx, "TransactionService.TransferMulti", trace.WithAttributes(
+		attribute.Int("transfer.legs", len(legs)),
+		attribute.Float64("amount", debited),
+	))
+
+	group := &model.TransferGroup{Legs: legs}
+	err := s.uow.WithinTx(ctx, func(ctx context.Context, repos repository.Repositories) error {
+		return transferMulti(ctx, repos, group, details)
+	})
+	outcome := transferOutcome(err)
+	metrics.ObserveTransfer(outcome, debited)
+	endSpan(span, outcome, err, outcome == metrics.OutcomeError)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Ctx(ctx).Debug().
+		Int64("group_id", group.GroupID).
+		Int("legs", len(legs)).
+		Int("transactions", len(group.Transactions)).
+		Float64("amount", debited).
+		Msg("multi-leg transfer committed")
+	return group, nil
+}
+
+// validateLegs checks that legs name distinct accounts with non-zero amounts netting to zero, to the cent as
+// balances are stored
+func validateLegs(legs []model.TransferLeg) error {
+	if len(legs) < 2 || len(legs) > maxTransferLegs {
+		return fmt.Errorf("%w: a transfer takes 2 to %d legs", domain.ErrInvalidTransaction, maxTransferLegs)
+	}
+	seen := make(map[int64]bool, len(legs))
+	var net int64
+	for _, leg := range legs {
+		if seen[leg.AccountID] {
+			return fmt.Errorf("%w: account %d appears in more than one leg", domain.ErrInvalidTransaction, leg.AccountID)
+		}
+		seen[leg.AccountID] = true
+		if cents(leg.Amount) == 0 {
+			return fmt.Errorf("%w: leg of account %d has no amount", domain.ErrInvalidTransaction, leg.AccountID)
+		}
+		net += cents(leg.Amount)
+	}
+	if net != 0 {
+		return fmt.Errorf("%w: legs do not net to zero", domain.ErrInvalidTransaction)
+	}
+	return nil
+}
+
+// transferMulti applies the legs of group within a unit of work and records its transactions
+func transferMulti(ctx context.Context, repos repository.Repositories, group *model.TransferGroup, details TransferDetails) error {
+	// lock every account in ascending id order so transfers over overlapping accounts cannot deadlock
+	ids := make([]int64, 0, len(group.Legs))
+	for _, leg := range group.Legs {
+		ids = append(ids, leg.AccountID)
+	}
+	slices.Sort(ids)
+	accs := make(map[int64]*model.Account, len(ids))
+	for _, id := range ids {
+		acc, err := repos.Accounts.GetAccountForUpdate(ctx, id)
+		if err != nil {
+			return err
 		}
-	}()
+		if acc == nil {
+			return domain.ErrAccountNotFound
+		}
+		if acc.Status == model.AccountClosed {
+			return fmt.Errorf("%w: account %d", domain.ErrAccountClosed, id)
+		}
+		accs[id] = acc
+	}
+
+	// apply each leg as rounded for its transactions, so the balance keeps matching the history
+	amounts := make(map[int64]float64, len(group.Legs))
+	debits := make(map[int64]float64, len(group.Legs))
+	for _, leg := range group.Legs {
+		acc := accs[leg.AccountID]
+		amount := float64(cents(leg.Amount)) / 100
+		amounts[leg.AccountID] = amount
+		if amount >= 0 || (details.system && acc.MayOverdraw()) {
+			continue
+		}
+		if acc.Balance < -amount {
+			return domain.ErrInsufficientFunds
+		}
+		debits[leg.AccountID] = -amount
+	}
 
-	sourceAcc, err := s.accRepo.GetAccount(ctx, sourceID)
+	createdAt, err := entryTime(ctx, repos, details.EffectiveAt, accs, debits)
 	if err != nil {
-		_ = tx.Rollback()
 		return err
 	}
-	if sourceAcc == nil {
-		_ = tx.Rollback()
-		return domain.ErrAccountNotFound
+	for _, leg := range group.Legs {
+		balance := accs[leg.AccountID].Balance + amounts[leg.AccountID]
+		if err := repos.Accounts.UpdateBalance(ctx, leg.AccountID, balance); err != nil {
+			return fmt.Errorf("failed to update balance of account %d: %w", leg.AccountID, err)
+		}
+	}
+	group.CreatedAt = createdAt
+	groupID, err := repos.Transactions.CreateTransferGroup(ctx, group.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert transfer group: %w", err)
+	}
+	group.GroupID = groupID
+
+	for _, p := range pairLegs(group.Legs) {
+		transaction := &model.Transaction{
+			SourceAccountID:      p.source,
+			DestinationAccountID: p.destination,
+			Amount:               float64(p.cents) / 100,
+			Type:                 details.Type,
+			Description:          details.Description,
+			Metadata:             details.Metadata,
+			ExternalReference:    details.ExternalReference,
+			CreatedAt:            group.CreatedAt,
+			GroupID:              groupID,
+		}
+		if err := repos.Transactions.CreateTransaction(ctx, transaction); err != nil {
+			return fmt.Errorf("failed to insert transaction record: %w", err)
+		}
+		group.Transactions = append(group.Transactions, transaction)
+	}
+	return nil
+}
+
+type legPair struct {
+	source, destination int64
+	cents               int64
+}
+
+// pairLegs matches debited legs to credited legs in the order given, splitting a leg across as many pairs as
+// it takes to cover it
+func pairLegs(legs []model.TransferLeg) []legPair {
+	type remaining struct {
+		accountID int64
+		cents     int64
+	}
+	var debits, credits []remaining
+	for _, leg := range legs {
+		if c := cents(leg.Amount); c < 0 {
+			debits = append(debits, remaining{leg.AccountID, -c})
+		} else {
+			credits = append(credits, remaining{leg.AccountID, c})
+		}
 	}
-	if sourceAcc.Balance < amount {
-		_ = tx.Rollback()
-		return domain.ErrInsufficientFunds
+
+	var pairs []legPair
+	for d, c := 0, 0; d < len(debits) && c < len(credits); {
+		n := min(debits[d].cents, credits[c].cents)
+		pairs = append(pairs, legPair{source: debits[d].accountID, destination: credits[c].accountID, cents: n})
+		debits[d].cents -= n
+		credits[c].cents -= n
+		if debits[d].cents == 0 {
+			d++
+		}
+		if credits[c].cents == 0 {
+			c++
+		}
 	}
+	return pairs
+}
+
+// cents rounds an amount to the hundredths balances are stored in
+func cents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
 
-	destAcc, err := s.accRepo.GetAccount(ctx, destID)
+// ListTransactions returns the transactions matching filter, oldest first
+func (s *transactionService) ListTransactions(ctx context.Context, filter repository.TransactionFilter) ([]*model.Transaction, error) {
+	ctx, span := tracer.Start(ctx, "TransactionService.ListTransactions", trace.WithAttributes(
+		attribute.Int64("account.id", filter.AccountID),
+		attribute.Int("list.limit", filter.Limit),
+	))
+
+	transactions, err := s.repo.ListTransactions(ctx, filter)
 	if err != nil {
-		_ = tx.Rollback()
-		return err
+		endSpan(span, "error", err, true)
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int("list.count", len(transactions)))
+	endSpan(span, "success", nil, false)
+	return transactions, nil
+}
+
+// Deposit credits an account with money received by an external source, drawing on the source's settlement
+// account. Each reference can be posted once per source.
+func (s *transactionService) Deposit(ctx context.Context, accountID int64, source, reference string, amount float64) (*model.Transaction, error) {
+	settlementID, ok := s.settlements[source]
+	if !ok {
+		return nil, domain.ErrUnknownSource
+	}
+	return s.settle(ctx, &model.Transaction{
+		SourceAccountID:      settlementID,
+		DestinationAccountID: accountID,
+		Amount:               amount,
+		Type:                 model.TransactionDeposit,
+		ExternalSource:       source,
+		ExternalReference:    reference,
+	})
+}
+
+// Withdraw debits an account for money paid out by an external source, crediting the source's settlement
+// account. Each reference can be posted once per source.
+func (s *transactionService) Withdraw(ctx context.Context, accountID int64, source, reference string, amount float64) (*model.Transaction, error) {
+	settlementID, ok := s.settlements[source]
+	if !ok {
+		return nil, domain.ErrUnknownSource
+	}
+	return s.settle(ctx, &model.Transaction{
+		SourceAccountID:      accountID,
+		DestinationAccountID: settlementID,
+		Amount:               amount,
+		Type:                 model.TransactionWithdrawal,
+		ExternalSource:       source,
+		ExternalReference:    reference,
+	})
+}
+
+// settle posts a deposit or withdrawal. Settlement accounts mirror money held by external systems, so a deposit
+// may take them negative; a withdrawal needs the funds in the account it debits, whatever its type. The amount is
+// rounded to the cent.
+func (s *transactionService) settle(ctx context.Context, transaction *model.Transaction) (*model.Transaction, error) {
+	transaction.Amount = float64(cents(transaction.Amount)) / 100
+	if transaction.Amount <= 0 {
+		return nil, fmt.Errorf("amount must be positive")
+	}
+	if transaction.SourceAccountID == transaction.DestinationAccountID {
+		return nil, domain.ErrSameAccount
+	}
+
+	ctx, span := tracer.Start(ctx, "TransactionService.Settle", trace.WithAttributes(
+		attribute.String("transaction.type", string(transaction.Type)),
+		attribute.String("transaction.external_source", transaction.ExternalSource),
+		attribute.Int64("account.source_id", transaction.SourceAccountID),
+		attribute.Int64("account.destination_id", transaction.DestinationAccountID),
+		attribute.Float64("amount", transaction.Amount),
+	))
+
+	err := s.uow.WithinTx(ctx, func(ctx context.Context, repos repository.Repositories) error {
+		return transfer(ctx, repos, transaction, time.Time{}, transaction.Type == model.TransactionDeposit)
+	})
+	outcome := transferOutcome(err)
+	metrics.ObserveSettlement(string(transaction.Type), transaction.ExternalSource, outcome, transaction.Amount)
+	endSpan(span, outcome, err, outcome == metrics.OutcomeError)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Ctx(ctx).Info().
+		Int64("transaction_id", transaction.TransactionID).
+		Str("type", string(transaction.Type)).
+		Str("external_source", transaction.ExternalSource).
+		Str("external_reference", transaction.ExternalReference).
+		Float64("amount", transaction.Amount).
+		Msg("settlement committed")
+	return transaction, nil
+}
+
+// transfer moves the amount of transaction from its source to its destination within a unit of work and
+// records it. The source may only go negative if the posting is the ledger's own and its account type allows it.
+func transfer(ctx context.Context, repos repository.Repositories, transaction *model.Transaction, effectiveAt time.Time,
+	system bool) error {
+	sourceID, destID, amount := transaction.SourceAccountID, transaction.DestinationAccountID, transaction.Amount
+
+	// lock both accounts in ascending id order so concurrent opposing transfers cannot deadlock
+	accs := make(map[int64]*model.Account, 2)
+	for _, id := range []int64{min(sourceID, destID), max(sourceID, destID)} {
+		acc, err := repos.Accounts.GetAccountForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if acc == nil {
+			return domain.ErrAccountNotFound
+		}
+		if acc.Status == model.AccountClosed {
+			return fmt.Errorf("%w: account %d", domain.ErrAccountClosed, id)
+		}
+		accs[id] = acc
 	}
-	if destAcc == nil {
-		_ = tx.Rollback()
-		return domain.ErrAccountNotFound
+	sourceAcc, destAcc := accs[sourceID], accs[destID]
+
+	debits := make(map[int64]float64, 1)
+	if !(system && sourceAcc.MayOverdraw()) {
+		if sourceAcc.Balance < amount {
+			return domain.ErrInsufficientFunds
+		}
+		debits[sourceID] = amount
 	}
 
-	if err := s.accRepo.UpdateBalance(ctx, tx, sourceID, sourceAcc.Balance-amount); err != nil {
-		_ = tx.Rollback()
+	createdAt, err := entryTime(ctx, repos, effectiveAt, accs, debits)
+	if err != nil {
+		return err
+	}
+	if err := repos.Accounts.UpdateBalance(ctx, sourceID, sourceAcc.Balance-amount); err != nil {
 		return fmt.Errorf("failed to update source balance: %w", err)
 	}
-	if err := s.accRepo.UpdateBalance(ctx, tx, destID, destAcc.Balance+amount); err != nil {
-		_ = tx.Rollback()
+	if err := repos.Accounts.UpdateBalance(ctx, destID, destAcc.Balance+amount); err != nil {
 		return fmt.Errorf("failed to update destination balance: %w", err)
 	}
+	transaction.CreatedAt = createdAt
+	if err := repos.Transactions.CreateTransaction(ctx, transaction); err != nil {
+		return fmt.Errorf("failed to insert transaction record: %w", err)
+	}
+	return nil
+}
 
-	transaction := &model.Transaction{
-		SourceAccountID:      sourceID,
-		DestinationAccountID: destID,
-		Amount:               amount,
-		CreatedAt:            time.Now(),
+// entryTime returns the time entries are recorded at: now, or effectiveAt when the caller backdates them.
+// Backdated entries hold the period lock so their period cannot close before they commit. They must fall after
+// every account was opened, and each account in debits must have held the amount it is debited at effectiveAt.
+// They drop the snapshots and reopen the interest accruals of the accounts they would make stale. entryTime
+// runs before the balances are updated, as historical balances are rolled back from the current ones.
+func entryTime(ctx context.Context, repos repository.Repositories, effectiveAt time.Time, accs map[int64]*model.Account,
+	debits map[int64]float64) (time.Time, error) {
+	if effectiveAt.IsZero() {
+		return time.Now(), nil
+	}
+	if err := repos.Periods.LockShared(ctx); err != nil {
+		return time.Time{}, err
+	}
+	lastClosed, err := repos.Periods.LastClosed(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !lastClosed.IsZero() && effectiveAt.Before(lastClosed.AddDate(0, 0, 1)) {
+		return time.Time{}, fmt.Errorf("%w: periods through %s are closed", domain.ErrPeriodClosed, lastClosed.Format(time.DateOnly))
 	}
 
-	if err := s.txRepo.CreateTransaction(ctx, tx, transaction); err != nil {
-		_ = tx.Rollback()
-		return fmt.Errorf("failed to insert transaction record: %w", err)
+	accountIDs := slices.Sorted(maps.Keys(accs))
+	for _, id := range accountIDs {
+		if effectiveAt.Before(accs[id].CreatedAt) {
+			return time.Time{}, fmt.Errorf("%w: account %d was opened after effective_at", domain.ErrInvalidTransaction, id)
+		}
+		amount, ok := debits[id]
+		if !ok {
+			continue
+		}
+		balance, err := repos.Balances.BalanceAt(ctx, id, effectiveAt)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if cents(balance) < cents(amount) {
+			return time.Time{}, fmt.Errorf("%w: account %d held %.2f at effective_at", domain.ErrInsufficientFunds, id, balance)
+		}
 	}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("transaction commit failed: %w", err)
+	if err := repos.Balances.DeleteSnapshotsAfter(ctx, accountIDs, effectiveAt); err != nil {
+		return time.Time{}, err
 	}
+	// an accrual is earned on the balance at the end of its day, so the day of effectiveAt is stale as well
+	if err := repos.Interest.ReopenAccruals(ctx, accountIDs, effectiveAt.UTC().Truncate(24*time.Hour)); err != nil {
+		return time.Time{}, err
+	}
+	return effectiveAt, nil
+}
 
-	return nil
+// transferOutcome maps the result of a transfer to its metrics label
+func transferOutcome(err error) string {
+	switch {
+	case err == nil:
+		return metrics.OutcomeSuccess
+	case errors.Is(err, domain.ErrInsufficientFunds):
+		return metrics.OutcomeInsufficientFunds
+	case errors.Is(err, domain.ErrAccountNotFound):
+		return metrics.OutcomeNotFound
+	case errors.Is(err, domain.ErrConcurrentUpdate):
+		return metrics.OutcomeConflict
+	case errors.Is(err, domain.ErrDuplicateReference):
+		return metrics.OutcomeDuplicate
+	case errors.Is(err, domain.ErrPeriodClosed):
+		return metrics.OutcomePeriodClosed
+	case errors.Is(err, domain.ErrAccountClosed):
+		return metrics.OutcomeAccountClosed
+	default:
+		return metrics.OutcomeError
+	}
 }
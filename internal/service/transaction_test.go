@@ -2,146 +2,535 @@ package service
 
 import (
 	"context"
-	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"internal-transfers/internal/domain"
 	"internal-transfers/internal/model"
+	"internal-transfers/internal/repository"
+	"internal-transfers/internal/repository/memory"
 	"internal-transfers/internal/repository/mocks"
 
-	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
-func newTestSetup(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *mocks.TransactionRepository, *mocks.AccountRepository, TransactionService) {
-	db, mockSql, err := sqlmock.New()
-	if err != nil {
-		t.Fatalf("failed to open sqlmock database: %v", err)
-	}
-
+func newTestSetup(t *testing.T) (*mocks.TransactionRepository, *mocks.AccountRepository, TransactionService) {
 	txRepo := mocks.NewTransactionRepository(t)
 	accRepo := mocks.NewAccountRepository(t)
-	service := NewTransactionService(txRepo, accRepo, db)
-
-	return db, mockSql, txRepo, accRepo, service
+	uow := mocks.NewUnitOfWork(t)
+	uow.EXPECT().
+		WithinTx(mock.Anything, mock.Anything).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context, repository.Repositories) error) error {
+			return fn(ctx, repository.Repositories{Accounts: accRepo, Transactions: txRepo})
+		}).
+		Maybe()
+
+	return txRepo, accRepo, NewTransactionService(txRepo, uow, map[string]int64{"bank": 9001})
 }
 
 func TestTransactionService_ProcessTransaction(t *testing.T) {
 	ctx := context.Background()
 
 	t.Run("success", func(t *testing.T) {
-		db, mockSql, txRepo, accRepo, service := newTestSetup(t)
-		defer db.Close()
+		txRepo, accRepo, service := newTestSetup(t)
 
 		source := &model.Account{AccountID: 1, Balance: 200}
 		dest := &model.Account{AccountID: 2, Balance: 50}
 		amount := 50.0
 
-		mockSql.ExpectBegin()
-		mockSql.ExpectCommit()
-
-		accRepo.EXPECT().GetAccount(ctx, source.AccountID).Return(source, nil)
-		accRepo.EXPECT().GetAccount(ctx, dest.AccountID).Return(dest, nil)
+		accRepo.EXPECT().GetAccountForUpdate(mock.Anything, source.AccountID).Return(source, nil)
+		accRepo.EXPECT().GetAccountForUpdate(mock.Anything, dest.AccountID).Return(dest, nil)
 
 		accRepo.EXPECT().
-			UpdateBalance(mock.Anything, mock.AnythingOfType("*sql.Tx"), source.AccountID, source.Balance-amount).
+			UpdateBalance(mock.Anything, source.AccountID, source.Balance-amount).
 			Return(nil)
 		accRepo.EXPECT().
-			UpdateBalance(mock.Anything, mock.AnythingOfType("*sql.Tx"), dest.AccountID, dest.Balance+amount).
+			UpdateBalance(mock.Anything, dest.AccountID, dest.Balance+amount).
 			Return(nil)
 
 		txRepo.EXPECT().
-			CreateTransaction(mock.Anything, mock.AnythingOfType("*sql.Tx"), mock.MatchedBy(func(tx *model.Transaction) bool {
+			CreateTransaction(mock.Anything, mock.MatchedBy(func(tx *model.Transaction) bool {
 				return tx.SourceAccountID == source.AccountID &&
 					tx.DestinationAccountID == dest.AccountID &&
 					tx.Amount == amount
 			})).
 			Return(nil)
 
-		err := service.ProcessTransaction(ctx, source.AccountID, dest.AccountID, amount)
+		err := service.ProcessTransaction(ctx, source.AccountID, dest.AccountID, amount, TransferDetails{})
 		assert.NoError(t, err)
+	})
 
-		assert.NoError(t, mockSql.ExpectationsWereMet())
+	t.Run("locks accounts in ascending id order", func(t *testing.T) {
+		txRepo, accRepo, service := newTestSetup(t)
+
+		source := &model.Account{AccountID: 2, Balance: 200}
+		dest := &model.Account{AccountID: 1, Balance: 50}
+
+		var locked []int64
+		accRepo.EXPECT().GetAccountForUpdate(mock.Anything, mock.Anything).
+			RunAndReturn(func(ctx context.Context, id int64) (*model.Account, error) {
+				locked = append(locked, id)
+				if id == source.AccountID {
+					return source, nil
+				}
+				return dest, nil
+			})
+		accRepo.EXPECT().UpdateBalance(mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		txRepo.EXPECT().CreateTransaction(mock.Anything, mock.Anything).Return(nil)
+
+		err := service.ProcessTransaction(ctx, source.AccountID, dest.AccountID, 10, TransferDetails{})
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{1, 2}, locked)
 	})
 
-	t.Run("insufficient funds from source account", func(t *testing.T) {
-		db, mockSql, _, accRepo, service := newTestSetup(t)
-		defer db.Close()
+	t.Run("records the transfer details", func(t *testing.T) {
+		txRepo, accRepo, service := newTestSetup(t)
 
-		mockSql.ExpectBegin()
-		mockSql.ExpectRollback()
+		accRepo.EXPECT().GetAccountForUpdate(mock.Anything, int64(1)).Return(&model.Account{AccountID: 1, Balance: 100}, nil)
+		accRepo.EXPECT().GetAccountForUpdate(mock.Anything, int64(2)).Return(&model.Account{AccountID: 2}, nil)
+		accRepo.EXPECT().UpdateBalance(mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		txRepo.EXPECT().
+			CreateTransaction(mock.Anything, mock.MatchedBy(func(tx *model.Transaction) bool {
+				return tx.Type == model.TransactionRefund &&
+					tx.Description == "order 42" &&
+					tx.Metadata["order_id"] == "42" &&
+					tx.ExternalReference == "rf-1"
+			})).
+			Return(nil)
+
+		err := service.ProcessTransaction(ctx, 1, 2, 10, TransferDetails{
+			Type:              model.TransactionRefund,
+			Description:       "order 42",
+			Metadata:          map[string]string{"order_id": "42"},
+			ExternalReference: "rf-1",
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid details", func(t *testing.T) {
+		_, _, service := newTestSetup(t)
+
+		for name, details := range map[string]TransferDetails{
+			"system type":   {Type: model.TransactionDeposit},
+			"interest type": {Type: model.TransactionInterest},
+			"unknown type":  {Type: "gift"},
+			"description":   {Description: strings.Repeat("x", maxDescriptionLength+1)},
+			"reference":     {ExternalReference: strings.Repeat("x", maxExternalReferenceLength+1)},
+			"metadata":      {Metadata: map[string]string{"blob": strings.Repeat("x", maxMetadataBytes)}},
+		} {
+			err := service.ProcessTransaction(ctx, 1, 2, 10, details)
+			assert.ErrorIs(t, err, domain.ErrInvalidTransaction, name)
+		}
+	})
+
+	t.Run("same source and destination", func(t *testing.T) {
+		_, _, service := newTestSetup(t)
+
+		err := service.ProcessTransaction(ctx, 1, 1, 50, TransferDetails{})
+		assert.ErrorIs(t, err, domain.ErrSameAccount)
+	})
+
+	t.Run("insufficient funds from source account", func(t *testing.T) {
+		_, accRepo, service := newTestSetup(t)
 
 		source := &model.Account{AccountID: 1, Balance: 10}
-		accRepo.EXPECT().GetAccount(ctx, source.AccountID).Return(source, nil)
+		accRepo.EXPECT().GetAccountForUpdate(mock.Anything, source.AccountID).Return(source, nil)
+		accRepo.EXPECT().GetAccountForUpdate(mock.Anything, int64(2)).Return(&model.Account{AccountID: 2}, nil)
 
-		err := service.ProcessTransaction(ctx, source.AccountID, 2, 50)
+		err := service.ProcessTransaction(ctx, source.AccountID, 2, 50, TransferDetails{})
 		assert.ErrorIs(t, err, domain.ErrInsufficientFunds)
-
-		assert.NoError(t, mockSql.ExpectationsWereMet())
 	})
 
 	t.Run("source account not found", func(t *testing.T) {
-		db, mockSql, _, accRepo, service := newTestSetup(t)
-		defer db.Close()
+		_, accRepo, service := newTestSetup(t)
 
-		mockSql.ExpectBegin()
-		mockSql.ExpectRollback()
+		accRepo.EXPECT().GetAccountForUpdate(mock.Anything, int64(1)).Return(nil, domain.ErrAccountNotFound)
 
-		accRepo.EXPECT().GetAccount(ctx, int64(1)).Return(nil, nil)
-
-		err := service.ProcessTransaction(ctx, 1, 2, 50)
+		err := service.ProcessTransaction(ctx, 1, 2, 50, TransferDetails{})
 		assert.ErrorIs(t, err, domain.ErrAccountNotFound)
-
-		assert.NoError(t, mockSql.ExpectationsWereMet())
 	})
 
 	t.Run("destination account not found", func(t *testing.T) {
-		db, mockSql, _, accRepo, service := newTestSetup(t)
-		defer db.Close()
-
-		mockSql.ExpectBegin()
-		mockSql.ExpectRollback()
+		_, accRepo, service := newTestSetup(t)
 
 		source := &model.Account{AccountID: 1, Balance: 100}
-		accRepo.EXPECT().GetAccount(ctx, source.AccountID).Return(source, nil)
-		accRepo.EXPECT().GetAccount(ctx, int64(2)).Return(nil, nil)
+		accRepo.EXPECT().GetAccountForUpdate(mock.Anything, source.AccountID).Return(source, nil)
+		accRepo.EXPECT().GetAccountForUpdate(mock.Anything, int64(2)).Return(nil, nil)
 
-		err := service.ProcessTransaction(ctx, source.AccountID, 2, 50)
+		err := service.ProcessTransaction(ctx, source.AccountID, 2, 50, TransferDetails{})
 		assert.ErrorIs(t, err, domain.ErrAccountNotFound)
-
-		assert.NoError(t, mockSql.ExpectationsWereMet())
 	})
 
 	t.Run("transaction create error when inserting", func(t *testing.T) {
-		db, mockSql, txRepo, accRepo, service := newTestSetup(t)
-		defer db.Close()
+		txRepo, accRepo, service := newTestSetup(t)
 
 		source := &model.Account{AccountID: 1, Balance: 200}
 		dest := &model.Account{AccountID: 2, Balance: 50}
 		amount := 50.0
 
-		mockSql.ExpectBegin()
-		mockSql.ExpectRollback()
-
-		accRepo.EXPECT().GetAccount(ctx, source.AccountID).Return(source, nil)
-		accRepo.EXPECT().GetAccount(ctx, dest.AccountID).Return(dest, nil)
+		accRepo.EXPECT().GetAccountForUpdate(mock.Anything, source.AccountID).Return(source, nil)
+		accRepo.EXPECT().GetAccountForUpdate(mock.Anything, dest.AccountID).Return(dest, nil)
 
 		accRepo.EXPECT().
-			UpdateBalance(ctx, mock.AnythingOfType("*sql.Tx"), source.AccountID, source.Balance-amount).
+			UpdateBalance(mock.Anything, source.AccountID, source.Balance-amount).
 			Return(nil)
 		accRepo.EXPECT().
-			UpdateBalance(ctx, mock.AnythingOfType("*sql.Tx"), dest.AccountID, dest.Balance+amount).
+			UpdateBalance(mock.Anything, dest.AccountID, dest.Balance+amount).
 			Return(nil)
 
 		txRepo.EXPECT().
-			CreateTransaction(mock.Anything, mock.AnythingOfType("*sql.Tx"), mock.Anything).
+			CreateTransaction(mock.Anything, mock.Anything).
 			Return(errors.New("insert error"))
 
-		err := service.ProcessTransaction(ctx, source.AccountID, dest.AccountID, amount)
+		err := service.ProcessTransaction(ctx, source.AccountID, dest.AccountID, amount, TransferDetails{})
 		assert.ErrorContains(t, err, "insert error")
+	})
+}
+
+func TestTransactionService_ListTransactions(t *testing.T) {
+	txRepo, _, service := newTestSetup(t)
+	filter := repository.TransactionFilter{AccountID: 1, Limit: 10}
+	want := []*model.Transaction{{TransactionID: 1, SourceAccountID: 1, DestinationAccountID: 2, Amount: 5}}
+	txRepo.EXPECT().ListTransactions(mock.Anything, filter).Return(want, nil)
+
+	got, err := service.ListTransactions(context.Background(), filter)
+	assert.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestTransactionService_ProcessTransaction_InMemory(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	accRepo := memory.NewAccountRepository(store)
+	service := NewTransactionService(memory.NewTransactionRepository(store), memory.NewUnitOfWork(store), nil)
+
+	assert.NoError(t, accRepo.CreateAccount(ctx, &model.Account{AccountID: 1, Balance: 100}))
+	assert.NoError(t, accRepo.CreateAccount(ctx, &model.Account{AccountID: 2, Balance: 0}))
+
+	t.Run("success", func(t *testing.T) {
+		err := service.ProcessTransaction(ctx, 1, 2, 40, TransferDetails{})
+		assert.NoError(t, err)
+
+		source, _ := accRepo.GetAccount(ctx, 1)
+		dest, _ := accRepo.GetAccount(ctx, 2)
+		assert.Equal(t, 60.0, source.Balance)
+		assert.Equal(t, 40.0, dest.Balance)
+	})
+
+	t.Run("unknown destination leaves balances untouched", func(t *testing.T) {
+		err := service.ProcessTransaction(ctx, 1, 3, 10, TransferDetails{})
+		assert.ErrorIs(t, err, domain.ErrAccountNotFound)
+
+		source, _ := accRepo.GetAccount(ctx, 1)
+		assert.Equal(t, 60.0, source.Balance)
+	})
+
+	t.Run("account types decide which of the ledger's own accounts may go negative", func(t *testing.T) {
+		assert.NoError(t, accRepo.CreateAccount(ctx, &model.Account{AccountID: model.InterestExpenseAccountID, Type: model.AccountExpense}))
+
+		err := service.ProcessTransaction(ctx, model.InterestExpenseAccountID, 2, 15, TransferDetails{system: true})
+		assert.NoError(t, err)
+		expense, _ := accRepo.GetAccount(ctx, model.InterestExpenseAccountID)
+		assert.Equal(t, -15.0, expense.Balance)
+
+		err = service.ProcessTransaction(ctx, 2, model.InterestExpenseAccountID, 100, TransferDetails{system: true})
+		assert.ErrorIs(t, err, domain.ErrInsufficientFunds)
+	})
+
+	t.Run("accounts callers open never go negative", func(t *testing.T) {
+		for id, accountType := range map[int64]model.AccountType{4: model.AccountExpense, 6: model.AccountSuspense} {
+			assert.NoError(t, accRepo.CreateAccount(ctx, &model.Account{AccountID: id, Type: accountType}))
+
+			err := service.ProcessTransaction(ctx, id, 2, 15, TransferDetails{system: true})
+			assert.ErrorIs(t, err, domain.ErrInsufficientFunds, accountType)
+			_, err = service.TransferMulti(ctx, []model.TransferLeg{{AccountID: id, Amount: -15}, {AccountID: 2, Amount: 15}},
+				TransferDetails{system: true})
+			assert.ErrorIs(t, err, domain.ErrInsufficientFunds, accountType)
+
+			acc, _ := accRepo.GetAccount(ctx, id)
+			assert.Zero(t, acc.Balance, accountType)
+		}
+	})
+
+	t.Run("callers may not overdraw system accounts", func(t *testing.T) {
+		assert.NoError(t, accRepo.CreateAccount(ctx, &model.Account{AccountID: model.SuspenseAccountID, Type: model.AccountSuspense}))
+
+		err := service.ProcessTransaction(ctx, model.SuspenseAccountID, 2, 1000, TransferDetails{})
+		assert.ErrorIs(t, err, domain.ErrInsufficientFunds)
+
+		_, err = service.TransferMulti(ctx, []model.TransferLeg{
+			{AccountID: model.SuspenseAccountID, Amount: -1000},
+			{AccountID: 2, Amount: 1000},
+		}, TransferDetails{})
+		assert.ErrorIs(t, err, domain.ErrInsufficientFunds)
+
+		suspense, _ := accRepo.GetAccount(ctx, model.SuspenseAccountID)
+		assert.Zero(t, suspense.Balance)
+	})
+
+	t.Run("amounts are rounded to the cent", func(t *testing.T) {
+		before, _ := accRepo.GetAccount(ctx, 1)
+
+		err := service.ProcessTransaction(ctx, 1, 2, 0.333, TransferDetails{})
+		assert.NoError(t, err)
+		err = service.ProcessTransaction(ctx, 1, 2, 0.004, TransferDetails{})
+		assert.Error(t, err)
+
+		after, _ := accRepo.GetAccount(ctx, 1)
+		assert.Equal(t, int64(-33), cents(after.Balance)-cents(before.Balance))
+		assert.Equal(t, after.Balance, float64(cents(after.Balance))/100)
+		transactions, _ := memory.NewTransactionRepository(store).ListTransactions(ctx, repository.TransactionFilter{})
+		assert.Equal(t, 0.33, transactions[len(transactions)-1].Amount)
+		require.NoError(t, service.ProcessTransaction(ctx, 2, 1, 0.33, TransferDetails{}))
+	})
+
+	t.Run("closed accounts take no postings", func(t *testing.T) {
+		assert.NoError(t, accRepo.CreateAccount(ctx, &model.Account{AccountID: 5}))
+		closed, _ := accRepo.GetAccount(ctx, 5)
+		closed.Status = model.AccountClosed
+		assert.NoError(t, accRepo.UpdateDetails(ctx, closed))
+
+		err := service.ProcessTransaction(ctx, 1, 5, 10, TransferDetails{})
+		assert.ErrorIs(t, err, domain.ErrAccountClosed)
+		err = service.ProcessTransaction(ctx, 5, 1, 10, TransferDetails{system: true})
+		assert.ErrorIs(t, err, domain.ErrAccountClosed)
+		_, err = service.TransferMulti(ctx, []model.TransferLeg{
+			{AccountID: 1, Amount: -10},
+			{AccountID: 5, Amount: 10},
+		}, TransferDetails{})
+		assert.ErrorIs(t, err, domain.ErrAccountClosed)
+
+		source, _ := accRepo.GetAccount(ctx, 1)
+		assert.Equal(t, 60.0, source.Balance)
+	})
+}
+
+func TestTransactionService_Backdated_InMemory(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	accRepo := memory.NewAccountRepository(store)
+	service := NewTransactionService(memory.NewTransactionRepository(store), memory.NewUnitOfWork(store), nil)
+	opened := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.NoError(t, accRepo.CreateAccount(ctx, &model.Account{AccountID: 1, Balance: 100, CreatedAt: opened}))
+	assert.NoError(t, accRepo.CreateAccount(ctx, &model.Account{AccountID: 2, Balance: 0, CreatedAt: opened}))
+	require.NoError(t, service.ProcessTransaction(ctx, 1, 2, 50, TransferDetails{}))
+	balances := func() []float64 {
+		source, _ := accRepo.GetAccount(ctx, 1)
+		dest, _ := accRepo.GetAccount(ctx, 2)
+		return []float64{source.Balance, dest.Balance}
+	}
+
+	t.Run("debits need the funds at the effective time", func(t *testing.T) {
+		err := service.ProcessTransaction(ctx, 2, 1, 10, TransferDetails{EffectiveAt: opened.AddDate(0, 0, 9)})
+		assert.ErrorIs(t, err, domain.ErrInsufficientFunds)
+
+		_, err = service.TransferMulti(ctx, []model.TransferLeg{{AccountID: 2, Amount: -10}, {AccountID: 1, Amount: 10}},
+			TransferDetails{EffectiveAt: opened.AddDate(0, 0, 9)})
+		assert.ErrorIs(t, err, domain.ErrInsufficientFunds)
+		assert.Equal(t, []float64{50, 50}, balances())
+	})
+
+	t.Run("rejects entries before the accounts were opened", func(t *testing.T) {
+		err := service.ProcessTransaction(ctx, 1, 2, 10, TransferDetails{EffectiveAt: opened.Add(-time.Hour)})
+		assert.ErrorIs(t, err, domain.ErrInvalidTransaction)
+		assert.Equal(t, []float64{50, 50}, balances())
+	})
+
+	t.Run("accepts debits covered at the effective time", func(t *testing.T) {
+		err := service.ProcessTransaction(ctx, 1, 2, 10, TransferDetails{EffectiveAt: opened.AddDate(0, 0, 9)})
+		assert.NoError(t, err)
+		assert.Equal(t, []float64{40, 60}, balances())
+	})
+}
+
+func TestTransactionService_Settlements_InMemory(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	accRepo := memory.NewAccountRepository(store)
+	service := NewTransactionService(memory.NewTransactionRepository(store), memory.NewUnitOfWork(store), map[string]int64{"bank": 9001})
+
+	assert.NoError(t, accRepo.CreateAccount(ctx, &model.Account{AccountID: 9001, Type: model.AccountSettlement}))
+	assert.NoError(t, accRepo.CreateAccount(ctx, &model.Account{AccountID: 1, Balance: 10}))
+
+	balances := func() (float64, float64) {
+		acc, _ := accRepo.GetAccount(ctx, 1)
+		settlement, _ := accRepo.GetAccount(ctx, 9001)
+		return acc.Balance, settlement.Balance
+	}
+
+	t.Run("deposit draws on the settlement account", func(t *testing.T) {
+		tx, err := service.Deposit(ctx, 1, "bank", "dep-1", 100)
+		assert.NoError(t, err)
+		assert.Equal(t, model.TransactionDeposit, tx.Type)
+		assert.Equal(t, int64(9001), tx.SourceAccountID)
+		assert.Equal(t, "dep-1", tx.ExternalReference)
+
+		balance, settlement := balances()
+		assert.Equal(t, 110.0, balance)
+		assert.Equal(t, -100.0, settlement)
+	})
+
+	t.Run("repeated reference is not posted twice", func(t *testing.T) {
+		_, err := service.Deposit(ctx, 1, "bank", "dep-1", 100)
+		assert.ErrorIs(t, err, domain.ErrDuplicateReference)
+
+		balance, _ := balances()
+		assert.Equal(t, 110.0, balance)
+	})
+
+	t.Run("withdrawal credits the settlement account", func(t *testing.T) {
+		tx, err := service.Withdraw(ctx, 1, "bank", "wd-1", 60)
+		assert.NoError(t, err)
+		assert.Equal(t, model.TransactionWithdrawal, tx.Type)
+
+		balance, settlement := balances()
+		assert.Equal(t, 50.0, balance)
+		assert.Equal(t, -40.0, settlement)
+	})
+
+	t.Run("withdrawal beyond the balance", func(t *testing.T) {
+		_, err := service.Withdraw(ctx, 1, "bank", "wd-2", 60)
+		assert.ErrorIs(t, err, domain.ErrInsufficientFunds)
+	})
+
+	t.Run("withdrawal from an account that may overdraw still needs the funds", func(t *testing.T) {
+		for _, acc := range []*model.Account{
+			{AccountID: 2, Type: model.AccountExpense},
+			{AccountID: 3, Type: model.AccountSuspense},
+			{AccountID: 9002, Type: model.AccountSystem},
+		} {
+			assert.NoError(t, accRepo.CreateAccount(ctx, acc))
+
+			_, err := service.Withdraw(ctx, acc.AccountID, "bank", fmt.Sprintf("wd-%d", acc.AccountID), 10)
+			assert.ErrorIs(t, err, domain.ErrInsufficientFunds, acc.Type)
+
+			stored, _ := accRepo.GetAccount(ctx, acc.AccountID)
+			assert.Zero(t, stored.Balance, acc.Type)
+		}
+	})
+
+	t.Run("settlement amounts are rounded to the cent", func(t *testing.T) {
+		tx, err := service.Deposit(ctx, 1, "bank", "dep-3", 0.005)
+		assert.NoError(t, err)
+		assert.Equal(t, 0.01, tx.Amount)
+		tx, err = service.Withdraw(ctx, 1, "bank", "wd-3", 0.014)
+		assert.NoError(t, err)
+		assert.Equal(t, 0.01, tx.Amount)
+		_, err = service.Withdraw(ctx, 1, "bank", "wd-4", 0.004)
+		assert.Error(t, err)
+
+		balance, settlement := balances()
+		assert.Equal(t, 50.0, balance)
+		assert.Equal(t, -40.0, settlement)
+	})
+
+	t.Run("unknown source", func(t *testing.T) {
+		_, err := service.Deposit(ctx, 1, "card", "dep-1", 10)
+		assert.ErrorIs(t, err, domain.ErrUnknownSource)
+	})
+
+	t.Run("settlement account itself", func(t *testing.T) {
+		_, err := service.Deposit(ctx, 9001, "bank", "dep-2", 10)
+		assert.ErrorIs(t, err, domain.ErrSameAccount)
+	})
+}
+
+func TestTransactionService_TransferMulti_InMemory(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	accRepo := memory.NewAccountRepository(store)
+	txRepo := memory.NewTransactionRepository(store)
+	service := NewTransactionService(txRepo, memory.NewUnitOfWork(store), nil)
+
+	for id, balance := range map[int64]float64{1: 100, 2: 0, 3: 0, 4: 0} {
+		assert.NoError(t, accRepo.CreateAccount(ctx, &model.Account{AccountID: id, Balance: balance}))
+	}
+	balances := func() []float64 {
+		var out []float64
+		for id := int64(1); id <= 4; id++ {
+			acc, _ := accRepo.GetAccount(ctx, id)
+			out = append(out, acc.Balance)
+		}
+		return out
+	}
+
+	t.Run("splits a debit across credits", func(t *testing.T) {
+		group, err := service.TransferMulti(ctx, []model.TransferLeg{
+			{AccountID: 1, Amount: -10.3}, {AccountID: 2, Amount: 9}, {AccountID: 3, Amount: 1.1}, {AccountID: 4, Amount: 0.2},
+		}, TransferDetails{Type: model.TransactionPayment, Description: "order 42"})
+		require.NoError(t, err)
+		assert.Equal(t, []float64{89.7, 9, 1.1, 0.2}, balances())
+
+		require.Len(t, group.Transactions, 3)
+		for i, want := range []struct {
+			dest   int64
+			amount float64
+		}{{2, 9}, {3, 1.1}, {4, 0.2}} {
+			tx := group.Transactions[i]
+			assert.Equal(t, int64(1), tx.SourceAccountID)
+			assert.Equal(t, want.dest, tx.DestinationAccountID)
+			assert.Equal(t, want.amount, tx.Amount)
+			assert.Equal(t, model.TransactionPayment, tx.Type)
+			assert.Equal(t, group.GroupID, tx.GroupID)
+		}
+
+		listed, err := txRepo.ListTransactions(ctx, repository.TransactionFilter{GroupID: group.GroupID})
+		require.NoError(t, err)
+		assert.Len(t, listed, 3)
+	})
+
+	t.Run("pairs several debits with several credits", func(t *testing.T) {
+		group, err := service.TransferMulti(ctx, []model.TransferLeg{
+			{AccountID: 2, Amount: -5}, {AccountID: 3, Amount: 4}, {AccountID: 1, Amount: -1}, {AccountID: 4, Amount: 2},
+		}, TransferDetails{})
+		require.NoError(t, err)
+		assert.Equal(t, []float64{88.7, 4, 5.1, 2.2}, balances())
+
+		var pairs [][3]float64
+		for _, tx := range group.Transactions {
+			pairs = append(pairs, [3]float64{float64(tx.SourceAccountID), float64(tx.DestinationAccountID), tx.Amount})
+		}
+		assert.Equal(t, [][3]float64{{2, 3, 4}, {2, 4, 1}, {1, 4, 1}}, pairs)
+	})
+
+	t.Run("insufficient funds leaves every balance untouched", func(t *testing.T) {
+		_, err := service.TransferMulti(ctx, []model.TransferLeg{
+			{AccountID: 1, Amount: 3}, {AccountID: 2, Amount: -5}, {AccountID: 3, Amount: 2},
+		}, TransferDetails{})
+		assert.ErrorIs(t, err, domain.ErrInsufficientFunds)
+		assert.Equal(t, []float64{88.7, 4, 5.1, 2.2}, balances())
+	})
+
+	t.Run("applies legs rounded to the cent like their transactions", func(t *testing.T) {
+		group, err := service.TransferMulti(ctx, []model.TransferLeg{{AccountID: 1, Amount: -0.704}, {AccountID: 3, Amount: 0.704}}, TransferDetails{})
+		require.NoError(t, err)
+		assert.Equal(t, 0.7, group.Transactions[0].Amount)
+		assert.Equal(t, []float64{88, 4, 5.8, 2.2}, balances())
+
+		report, err := NewReconciliationService(memory.NewReconciliationRepository(store), 0, nil).Run(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, report.Discrepancies)
+	})
+
+	t.Run("unknown account", func(t *testing.T) {
+		_, err := service.TransferMulti(ctx, []model.TransferLeg{{AccountID: 1, Amount: -1}, {AccountID: 9, Amount: 1}}, TransferDetails{})
+		assert.ErrorIs(t, err, domain.ErrAccountNotFound)
+	})
 
-		assert.NoError(t, mockSql.ExpectationsWereMet())
+	t.Run("invalid legs", func(t *testing.T) {
+		for name, legs := range map[string][]model.TransferLeg{
+			"single leg":       {{AccountID: 1, Amount: 0}},
+			"does not net":     {{AccountID: 1, Amount: -1}, {AccountID: 2, Amount: 0.99}},
+			"repeated account": {{AccountID: 1, Amount: -1}, {AccountID: 1, Amount: 1}},
+			"zero amount":      {{AccountID: 1, Amount: -1}, {AccountID: 2, Amount: 1}, {AccountID: 3, Amount: 0}},
+		} {
+			_, err := service.TransferMulti(ctx, legs, TransferDetails{})
+			assert.ErrorIs(t, err, domain.ErrInvalidTransaction, name)
+		}
 	})
 }
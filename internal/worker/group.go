@@ -0,0 +1,68 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Group runs background workers that share a context which is cancelled on shutdown
+type Group struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewGroup(parent context.Context) *Group {
+	ctx, cancel := context.WithCancel(parent)
+	return &Group{ctx: ctx, cancel: cancel}
+}
+
+// Go starts fn in a new goroutine; fn must return promptly once ctx is cancelled
+func (g *Group) Go(name string, fn func(ctx context.Context)) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		log.Debug().Str("worker", name).Msg("worker started")
+		fn(g.ctx)
+		log.Debug().Str("worker", name).Msg("worker stopped")
+	}()
+}
+
+// Stop cancels all workers and waits for them to return, giving up when ctx is done
+func (g *Group) Stop(ctx context.Context) error {
+	g.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Every runs fn immediately and then every interval until the group stops, logging any error it returns
+func (g *Group) Every(name string, interval time.Duration, fn func(ctx context.Context) error) {
+	g.Go(name, func(ctx context.Context) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if err := fn(ctx); err != nil && ctx.Err() == nil {
+				log.Error().Err(err).Str("worker", name).Msg("worker run failed")
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	})
+}
@@ -0,0 +1,72 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroup_Stop(t *testing.T) {
+	t.Run("waits for workers to return", func(t *testing.T) {
+		// given
+		g := NewGroup(context.Background())
+		stopped := make(chan struct{})
+		g.Go("test", func(ctx context.Context) {
+			<-ctx.Done()
+			close(stopped)
+		})
+
+		// when
+		err := g.Stop(context.Background())
+
+		// then
+		assert.NoError(t, err)
+		select {
+		case <-stopped:
+		default:
+			t.Fatal("worker still running after Stop returned")
+		}
+	})
+
+	t.Run("gives up at deadline", func(t *testing.T) {
+		// given
+		g := NewGroup(context.Background())
+		release := make(chan struct{})
+		defer close(release)
+		g.Go("stuck", func(ctx context.Context) {
+			<-release
+		})
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		// when
+		err := g.Stop(ctx)
+
+		// then
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestGroup_Every(t *testing.T) {
+	// given
+	g := NewGroup(context.Background())
+	runs := make(chan struct{}, 10)
+
+	// when
+	g.Every("test", time.Millisecond, func(ctx context.Context) error {
+		runs <- struct{}{}
+		return assert.AnError // errors are logged and do not stop the schedule
+	})
+
+	// then
+	for range 3 {
+		select {
+		case <-runs:
+		case <-time.After(time.Second):
+			t.Fatal("job did not run")
+		}
+	}
+	assert.NoError(t, g.Stop(context.Background()))
+}
@@ -2,51 +2,51 @@ package main
 
 import (
 	"fmt"
-	"net/http"
 	"os"
 
 	"github.com/rs/zerolog/log"
 
-	"internal-transfers/internal/api"
 	"internal-transfers/internal/config"
-	"internal-transfers/internal/repository"
-	"internal-transfers/internal/service"
 
 	"internal-transfers/pkg/logger"
 )
 
 func main() {
-	logger.InitLogger()
-
-	// init configs
-	if err := config.LoadEnv(); err != nil {
-		log.Fatal().Err(err).Msg("failed to load env")
+	cmd, args := "serve", os.Args[1:]
+	if len(args) > 0 {
+		cmd, args = args[0], args[1:]
 	}
 
-	// init db
-	dbCfg := config.GetDBConfig()
-	db, err := repository.InitDB(dbCfg)
+	// init configs
+	cfg, err := config.Load()
 	if err != nil {
-		log.Fatal().Err(err).Msg("failed to initialize db")
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
 	}
 
-	accountRepo := repository.NewAccountRepository(db)
-	transactionRepo := repository.NewTransactionRepository(db)
-
-	// init services
-	accountSvc := service.NewAccountService(accountRepo)
-	transactionSvc := service.NewTransactionService(transactionRepo, accountRepo, db)
-
-	// init router
-	router := api.NewRouter(accountSvc, transactionSvc)
+	// config print writes to stdout, so it must run before the logger starts writing there
+	if cmd == "config" {
+		runConfig(cfg, args)
+		return
+	}
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	// reconcile writes its report to stdout, so its logs go to stderr
+	if cmd == "reconcile" {
+		logger.InitLoggerTo(os.Stderr, cfg.Log.Level, cfg.Log.Format)
+	} else {
+		logger.InitLogger(cfg.Log.Level, cfg.Log.Format)
 	}
 
-	log.Info().Msg(fmt.Sprintf("Server running on :%s", port))
-	if err := http.ListenAndServe(":"+port, router); err != nil {
-		log.Fatal().Err(err).Msg("Server crashed: %v")
+	switch cmd {
+	case "serve":
+		serve(cfg)
+	case "migrate":
+		runMigrate(cfg, args)
+	case "import":
+		runImport(cfg, args)
+	case "reconcile":
+		runReconcile(cfg)
+	default:
+		log.Fatal().Str("command", cmd).Msg("unknown command, expected one of: serve, migrate, import, reconcile, config")
 	}
 }
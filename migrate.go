@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"internal-transfers/internal/config"
+	"internal-transfers/internal/migrate"
+	"internal-transfers/internal/repository"
+	"internal-transfers/migrations"
+)
+
+const migrateUsage = "usage: migrate up|down|status|goto <version>"
+
+// runMigrate applies, reverts or reports the embedded schema migrations
+func runMigrate(cfg config.Config, args []string) {
+	if len(args) == 0 {
+		log.Fatal().Msg(migrateUsage)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	db, err := repository.InitDB(ctx, cfg.DB)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to initialize db")
+	}
+	defer db.Close()
+
+	migrator, err := migrate.New(db, migrations.FS)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to load migrations")
+	}
+
+	switch args[0] {
+	case "up":
+		err = migrator.Up(ctx)
+	case "down":
+		err = migrator.Down(ctx)
+	case "goto":
+		if len(args) != 2 {
+			log.Fatal().Msg(migrateUsage)
+		}
+		version, parseErr := strconv.ParseInt(args[1], 10, 64)
+		if parseErr != nil {
+			log.Fatal().Err(parseErr).Msg("invalid migration version")
+		}
+		err = migrator.Goto(ctx, version)
+	case "status":
+		err = printMigrationStatus(ctx, migrator)
+	default:
+		log.Fatal().Msg(migrateUsage)
+	}
+	if err != nil {
+		log.Fatal().Err(err).Str("command", args[0]).Msg("migration failed")
+	}
+}
+
+func printMigrationStatus(ctx context.Context, migrator *migrate.Migrator) error {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+	for _, st := range statuses {
+		appliedAt := "pending"
+		if st.Applied {
+			appliedAt = st.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", st.Version, st.Name, appliedAt)
+	}
+	return w.Flush()
+}
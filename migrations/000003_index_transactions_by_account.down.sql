@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_transactions_destination_created_at;
+DROP INDEX IF EXISTS idx_transactions_source_created_at;
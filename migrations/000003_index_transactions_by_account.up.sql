@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS idx_transactions_source_created_at ON transactions (source_account_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_transactions_destination_created_at ON transactions (destination_account_id, created_at);
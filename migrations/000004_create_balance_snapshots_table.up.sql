@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS balance_snapshots (
+    account_id BIGINT NOT NULL,
+    as_of TIMESTAMPTZ NOT NULL,
+    balance NUMERIC(20,2) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (account_id, as_of),
+    CONSTRAINT fk_snapshot_account FOREIGN KEY (account_id) REFERENCES accounts(account_id)
+);
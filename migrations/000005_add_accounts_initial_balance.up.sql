@@ -0,0 +1,3 @@
+-- existing accounts predate the column and keep a NULL initial balance: inferring it from their history would
+-- make them reconcile by construction, so reconciliation reports them as unverifiable instead
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS initial_balance NUMERIC(20,2);
@@ -0,0 +1,5 @@
+ALTER TABLE transactions
+    DROP CONSTRAINT IF EXISTS transactions_external_reference_key,
+    DROP COLUMN IF EXISTS external_reference,
+    DROP COLUMN IF EXISTS external_source,
+    DROP COLUMN IF EXISTS type;
@@ -0,0 +1,8 @@
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS type TEXT NOT NULL DEFAULT 'transfer',
+    ADD COLUMN IF NOT EXISTS external_source TEXT,
+    ADD COLUMN IF NOT EXISTS external_reference TEXT;
+
+-- an external system may post each of its references only once
+ALTER TABLE transactions
+    ADD CONSTRAINT transactions_external_reference_key UNIQUE (external_source, external_reference);
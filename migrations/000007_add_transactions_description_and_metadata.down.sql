@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_transactions_metadata;
+DROP INDEX IF EXISTS idx_transactions_external_reference;
+DROP INDEX IF EXISTS idx_transactions_type;
+
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS metadata,
+    DROP COLUMN IF EXISTS description;
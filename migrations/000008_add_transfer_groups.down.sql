@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_transactions_group_id;
+
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS group_id;
+
+DROP TABLE IF EXISTS transfer_groups;
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS transfer_groups (
+    group_id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS group_id BIGINT REFERENCES transfer_groups (group_id);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_group_id ON transactions (group_id);
@@ -0,0 +1,17 @@
+-- remove the seeded accounts unless they have been used
+WITH unused AS (
+    SELECT a.account_id FROM accounts a
+    WHERE a.account_id IN (9000000000000000001, 9000000000000000002, 9000000000000000003)
+        AND NOT EXISTS (
+            SELECT 1 FROM transactions t
+            WHERE t.source_account_id = a.account_id OR t.destination_account_id = a.account_id
+        )
+), snapshots AS (
+    DELETE FROM balance_snapshots WHERE account_id IN (SELECT account_id FROM unused)
+)
+DELETE FROM accounts WHERE account_id IN (SELECT account_id FROM unused);
+
+DROP INDEX IF EXISTS idx_accounts_type;
+
+ALTER TABLE accounts
+    DROP COLUMN IF EXISTS type;
@@ -0,0 +1,24 @@
+ALTER TABLE accounts
+    ADD COLUMN IF NOT EXISTS type TEXT NOT NULL DEFAULT 'customer'
+        CONSTRAINT accounts_type_check
+        CHECK (type IN ('customer', 'revenue', 'expense', 'suspense', 'settlement', 'system'));
+
+CREATE INDEX IF NOT EXISTS idx_accounts_type ON accounts (type);
+
+-- the mint and settlement accounts are configured by id; classify those that have already been used. The
+-- service refuses to start with a configured account of another type, which an operator has to resolve.
+UPDATE accounts SET type = 'system'
+WHERE account_id IN (SELECT source_account_id FROM transactions WHERE type = 'funding');
+
+UPDATE accounts SET type = 'settlement'
+WHERE account_id IN (
+    SELECT source_account_id FROM transactions WHERE type = 'deposit'
+    UNION
+    SELECT destination_account_id FROM transactions WHERE type = 'withdrawal'
+);
+
+INSERT INTO accounts (account_id, balance, initial_balance, type) VALUES
+    (9000000000000000001, 0, 0, 'suspense'),
+    (9000000000000000002, 0, 0, 'revenue'),
+    (9000000000000000003, 0, 0, 'expense')
+ON CONFLICT (account_id) DO NOTHING;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS interest_postings;
+DROP TABLE IF EXISTS interest_accruals;
@@ -0,0 +1,31 @@
+-- one accrual per account and day, so rerunning the accrual job never accrues a day twice
+CREATE TABLE IF NOT EXISTS interest_accruals (
+    account_id BIGINT NOT NULL,
+    accrual_date DATE NOT NULL,
+    product TEXT NOT NULL,
+    balance NUMERIC(24,10) NOT NULL,
+    rate NUMERIC(10,8) NOT NULL,
+    amount NUMERIC(24,10) NOT NULL,
+    period_start DATE NOT NULL,
+    period_end DATE NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (account_id, accrual_date),
+    CONSTRAINT fk_accrual_account FOREIGN KEY (account_id) REFERENCES accounts(account_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_interest_accruals_period ON interest_accruals (period_end, account_id, period_start);
+
+-- one posting per account and period; a pending posting is claimed by the job paying it until claimed_at
+-- goes stale
+CREATE TABLE IF NOT EXISTS interest_postings (
+    account_id BIGINT NOT NULL,
+    period_start DATE NOT NULL,
+    period_end DATE NOT NULL,
+    amount NUMERIC(20,2) NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    claimed_at TIMESTAMPTZ NOT NULL,
+    posted_at TIMESTAMPTZ,
+    PRIMARY KEY (account_id, period_start),
+    CONSTRAINT fk_posting_account FOREIGN KEY (account_id) REFERENCES accounts(account_id),
+    CONSTRAINT interest_postings_status_check CHECK (status IN ('pending', 'posted'))
+);
@@ -0,0 +1,7 @@
+-- a row per closed day; entries may not be backdated to before the end of the latest one
+CREATE TABLE IF NOT EXISTS accounting_periods (
+    period_date DATE PRIMARY KEY,
+    closed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    closed_by TEXT NOT NULL DEFAULT '',
+    trial_balance JSONB NOT NULL
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_accounts_created_at_id;
+DROP INDEX IF EXISTS idx_accounts_balance_id;
+ALTER TABLE accounts DROP COLUMN IF EXISTS created_at;
@@ -0,0 +1,18 @@
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ;
+
+-- existing accounts predate the column; the first transaction touching an account is the earliest it is known
+-- to have existed
+UPDATE accounts a
+SET created_at = COALESCE((
+    SELECT MIN(t.created_at)
+    FROM transactions t
+    WHERE t.source_account_id = a.account_id OR t.destination_account_id = a.account_id
+), NOW())
+WHERE a.created_at IS NULL;
+
+ALTER TABLE accounts ALTER COLUMN created_at SET DEFAULT NOW();
+ALTER TABLE accounts ALTER COLUMN created_at SET NOT NULL;
+
+-- keyset pagination of the account listing in each of its sort orders; listing by id uses the primary key
+CREATE INDEX IF NOT EXISTS idx_accounts_balance_id ON accounts (balance, account_id);
+CREATE INDEX IF NOT EXISTS idx_accounts_created_at_id ON accounts (created_at, account_id);
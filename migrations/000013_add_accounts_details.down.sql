@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_accounts_owner_id;
+ALTER TABLE accounts
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS version,
+    DROP COLUMN IF EXISTS labels,
+    DROP COLUMN IF EXISTS owner,
+    DROP COLUMN IF EXISTS name;
@@ -0,0 +1,13 @@
+ALTER TABLE accounts
+    ADD COLUMN IF NOT EXISTS name TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS owner TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS labels JSONB NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ;
+
+UPDATE accounts SET updated_at = created_at WHERE updated_at IS NULL;
+
+ALTER TABLE accounts ALTER COLUMN updated_at SET DEFAULT NOW();
+ALTER TABLE accounts ALTER COLUMN updated_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_accounts_owner_id ON accounts (owner, account_id);
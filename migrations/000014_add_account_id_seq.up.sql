@@ -0,0 +1,6 @@
+-- Server-allocated account ids are a number from this sequence followed by its check digit. The sequence starts
+-- above the ids reserved for callers and stops short of the seeded accounts.
+CREATE SEQUENCE IF NOT EXISTS account_id_seq
+    MINVALUE 10000000000
+    MAXVALUE 899999999999999999
+    START WITH 10000000000;
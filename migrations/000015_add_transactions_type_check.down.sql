@@ -0,0 +1 @@
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_type_check;
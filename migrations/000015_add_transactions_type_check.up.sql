@@ -0,0 +1,3 @@
+ALTER TABLE transactions
+    ADD CONSTRAINT transactions_type_check
+        CHECK (type IN ('transfer', 'payment', 'payroll', 'refund', 'fee', 'funding', 'deposit', 'withdrawal', 'interest'));
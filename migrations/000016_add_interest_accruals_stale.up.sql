@@ -0,0 +1,3 @@
+-- a backdated entry marks the accruals it made out of date stale; they are accrued again before their period
+-- is paid
+ALTER TABLE interest_accruals ADD COLUMN IF NOT EXISTS stale BOOLEAN NOT NULL DEFAULT false;
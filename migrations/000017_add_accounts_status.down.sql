@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_accounts_status;
+ALTER TABLE accounts DROP CONSTRAINT IF EXISTS accounts_status_check;
+ALTER TABLE accounts DROP COLUMN IF EXISTS status;
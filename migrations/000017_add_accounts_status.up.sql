@@ -0,0 +1,7 @@
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'open';
+
+ALTER TABLE accounts DROP CONSTRAINT IF EXISTS accounts_status_check;
+ALTER TABLE accounts ADD CONSTRAINT accounts_status_check CHECK (status IN ('open', 'closed'));
+
+-- backs the status filter of the account listing, in id order like the default sort
+CREATE INDEX IF NOT EXISTS idx_accounts_status ON accounts (status, account_id);
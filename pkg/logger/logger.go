@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"io"
 	"os"
 	"time"
 
@@ -8,26 +9,42 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
-func InitLogger() {
-	logLevelStr := os.Getenv("LOG_LEVEL")
-	if logLevelStr == "" {
-		logLevelStr = "info"
-	}
+const (
+	FormatJSON    = "json"
+	FormatConsole = "console"
+)
+
+// InitLogger configures the global logger to write to stdout with the given level and output format (json or console)
+func InitLogger(logLevelStr, format string) {
+	InitLoggerTo(os.Stdout, logLevelStr, format)
+}
+
+// InitLoggerTo is InitLogger writing to out, for commands whose stdout carries their result
+func InitLoggerTo(out io.Writer, logLevelStr, format string) {
 	level, err := zerolog.ParseLevel(logLevelStr)
 	if err != nil {
 		level = zerolog.DebugLevel
 	}
 
-	consoleWriter := zerolog.ConsoleWriter{
-		Out:        os.Stdout,
-		TimeFormat: time.RFC3339,
-	}
-
-	log.Logger = zerolog.New(consoleWriter).
+	log.Logger = zerolog.New(newWriter(out, format)).
 		Level(level).
 		With().
 		Timestamp().
 		Logger()
 
-	log.Info().Str("level", level.String()).Msg("Logger initialized")
+	// log.Ctx falls back to the global logger when no request-scoped logger is attached
+	zerolog.DefaultContextLogger = &log.Logger
+
+	log.Info().Str("level", level.String()).Str("format", format).Msg("Logger initialized")
+}
+
+// newWriter returns the output for the given log format; unknown formats fall back to console
+func newWriter(out io.Writer, format string) io.Writer {
+	if format == FormatJSON {
+		return out
+	}
+	return zerolog.ConsoleWriter{
+		Out:        out,
+		TimeFormat: time.RFC3339,
+	}
 }
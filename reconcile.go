@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/rs/zerolog/log"
+
+	"internal-transfers/internal/api/handler"
+	"internal-transfers/internal/config"
+	"internal-transfers/internal/repository"
+	"internal-transfers/internal/service"
+)
+
+// exitDiscrepancies is the exit status of reconcile when at least one balance does not match
+const exitDiscrepancies = 2
+
+// runReconcile checks every balance against the transaction history and prints the report as JSON,
+// exiting with exitDiscrepancies when any account disagrees
+func runReconcile(cfg config.Config) {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	db, err := repository.InitDB(ctx, cfg.DB)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to initialize db")
+	}
+	defer db.Close()
+
+	report, err := service.NewReconciliationService(repository.NewReconciliationRepository(db), cfg.Mint.AccountID,
+		settlementAccountIDs(cfg.Settlement)).Run(ctx)
+	if err != nil {
+		log.Fatal().Err(err).Msg("reconciliation failed")
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(handler.NewReconciliationResponse(report)); err != nil {
+		log.Fatal().Err(err).Msg("failed to print reconciliation report")
+	}
+	if len(report.Discrepancies) > 0 {
+		db.Close()
+		os.Exit(exitDiscrepancies)
+	}
+}
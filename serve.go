@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"internal-transfers/internal/api"
+	"internal-transfers/internal/config"
+	"internal-transfers/internal/health"
+	"internal-transfers/internal/model"
+	"internal-transfers/internal/service"
+	"internal-transfers/internal/worker"
+
+	"internal-transfers/pkg/tracing"
+)
+
+// serve runs the HTTP API until SIGINT/SIGTERM, then drains and shuts down
+func serve(cfg config.Config) {
+	serverCfg := cfg.Server
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// init tracing
+	shutdownTracer, err := tracing.InitTracer(ctx, cfg.Tracing.Exporter)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to initialize tracing")
+	}
+	defer func() {
+		if err := shutdownTracer(context.Background()); err != nil {
+			log.Error().Err(err).Msg("failed to flush traces")
+		}
+	}()
+
+	// init storage
+	checker := health.NewChecker(serverCfg.ReadinessTimeout)
+	store, err := openStorage(ctx, cfg, checker)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to initialize storage")
+	}
+
+	if err := ensureSystemAccounts(ctx, store.accounts, cfg); err != nil {
+		log.Fatal().Err(err).Msg("failed to initialize storage")
+	}
+
+	// init services
+	accountSvc := service.NewAccountService(store.accounts, store.uow, mintPolicy(cfg.Mint), accountIDPolicy(cfg.Accounts))
+	transactionSvc := service.NewTransactionService(store.transactions, store.uow, cfg.Settlement.AccountIDs())
+	statementSvc := service.NewStatementService(store.transactions)
+	balanceSvc := service.NewBalanceService(store.balances, store.uow)
+	reconciliationSvc := service.NewReconciliationService(store.reconcile, cfg.Mint.AccountID, settlementAccountIDs(cfg.Settlement))
+	periodSvc := service.NewPeriodService(store.periods, store.uow, cfg.Jobs.SnapshotDelay)
+	interestSvc := service.NewInterestService(store.interest, store.balances, transactionSvc, model.InterestExpenseAccountID, cfg.Interest.Enrollments())
+
+	// background workers outlive the shutdown signal and are stopped once in-flight requests have drained
+	workers := worker.NewGroup(context.Background())
+	if jobs := cfg.Jobs; jobs.SnapshotInterval > 0 {
+		workers.Every("balance-snapshots", jobs.SnapshotInterval, func(ctx context.Context) error {
+			return balanceSvc.SnapshotBalances(ctx, service.DailySnapshotTime(time.Now(), jobs.SnapshotDelay))
+		})
+	}
+	if jobs := cfg.Jobs; jobs.ReconcileInterval > 0 {
+		workers.Every("reconciliation", jobs.ReconcileInterval, func(ctx context.Context) error {
+			_, err := reconciliationSvc.Run(ctx)
+			return err
+		})
+	}
+	if jobs := cfg.Jobs; jobs.PeriodCloseInterval > 0 {
+		workers.Every("period-close", jobs.PeriodCloseInterval, func(ctx context.Context) error {
+			return periodSvc.CloseDue(ctx, service.DailySnapshotTime(time.Now(), jobs.SnapshotDelay))
+		})
+	}
+	if jobs := cfg.Jobs; jobs.InterestAccrualInterval > 0 && len(cfg.Interest.Accounts) > 0 {
+		workers.Every("interest-accrual", jobs.InterestAccrualInterval, func(ctx context.Context) error {
+			return interestSvc.Accrue(ctx, service.DailySnapshotTime(time.Now(), jobs.SnapshotDelay))
+		})
+	}
+	if jobs := cfg.Jobs; jobs.InterestPostingInterval > 0 && len(cfg.Interest.Accounts) > 0 {
+		workers.Every("interest-posting", jobs.InterestPostingInterval, func(ctx context.Context) error {
+			return interestSvc.Post(ctx, service.DailySnapshotTime(time.Now(), jobs.SnapshotDelay))
+		})
+	}
+
+	// init router
+	router := api.NewRouter(accountSvc, transactionSvc, statementSvc, balanceSvc, reconciliationSvc, periodSvc, checker)
+
+	log.Info().Msg(fmt.Sprintf("Server running on :%d", serverCfg.Port))
+	srv := &http.Server{
+		Addr:         fmt.Sprintf(":%d", serverCfg.Port),
+		Handler:      router,
+		ReadTimeout:  serverCfg.ReadTimeout,
+		WriteTimeout: serverCfg.WriteTimeout,
+		IdleTimeout:  serverCfg.IdleTimeout,
+	}
+	serverErr := make(chan error, 1)
+	go func() {
+		if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+
+	select {
+	case err := <-serverErr:
+		log.Fatal().Err(err).Msg("Server crashed")
+	case <-ctx.Done():
+	}
+	stop()
+
+	log.Info().Msg("Shutdown signal received, draining")
+	checker.SetShuttingDown()
+	time.Sleep(serverCfg.ShutdownDelay)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), serverCfg.ShutdownTimeout)
+	defer cancel()
+
+	// stop accepting connections and wait for in-flight requests to complete
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Error().Err(err).Msg("failed to drain in-flight requests")
+	}
+	if err := workers.Stop(shutdownCtx); err != nil {
+		log.Error().Err(err).Msg("failed to stop background workers")
+	}
+	if err := store.close(); err != nil {
+		log.Error().Err(err).Msg("failed to close storage")
+	}
+	log.Info().Msg("Server stopped")
+}
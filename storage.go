@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/rs/zerolog/log"
+
+	"internal-transfers/internal/config"
+	"internal-transfers/internal/domain"
+	"internal-transfers/internal/health"
+	"internal-transfers/internal/metrics"
+	"internal-transfers/internal/migrate"
+	"internal-transfers/internal/model"
+	"internal-transfers/internal/repository"
+	"internal-transfers/internal/repository/memory"
+	"internal-transfers/internal/service"
+	"internal-transfers/migrations"
+)
+
+// storage bundles the repositories of the configured backend
+type storage struct {
+	accounts     repository.AccountRepository
+	transactions repository.TransactionRepository
+	balances     repository.BalanceRepository
+	reconcile    repository.ReconciliationRepository
+	interest     repository.InterestRepository
+	periods      repository.PeriodRepository
+	uow          repository.UnitOfWork
+	close        func() error
+}
+
+// openStorage connects to the configured backend and registers its readiness checks with checker
+func openStorage(ctx context.Context, cfg config.Config, checker *health.Checker) (*storage, error) {
+	if cfg.Storage == config.StorageMemory {
+		log.Warn().Msg("Using in-memory storage, all data will be lost on exit")
+		store := memory.NewStore()
+		return &storage{
+			accounts:     memory.NewAccountRepository(store),
+			transactions: memory.NewTransactionRepository(store),
+			balances:     memory.NewBalanceRepository(store),
+			reconcile:    memory.NewReconciliationRepository(store),
+			interest:     memory.NewInterestRepository(store),
+			periods:      memory.NewPeriodRepository(store),
+			uow:          memory.NewUnitOfWork(store),
+			close:        func() error { return nil },
+		}, nil
+	}
+
+	db, err := repository.InitDB(ctx, cfg.DB)
+	if err != nil {
+		return nil, fmt.Errorf("initialize db: %w", err)
+	}
+	metrics.RegisterDBStats(db, cfg.DB.Name)
+
+	migrator, err := migrate.New(db, migrations.FS)
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("load migrations: %w", err)
+	}
+	if cfg.Features.AutoMigrate {
+		if err := migrator.Up(ctx); err != nil {
+			_ = db.Close()
+			return nil, fmt.Errorf("apply migrations: %w", err)
+		}
+	}
+
+	checker.Register("database", db.PingContext)
+	checker.Register("migrations", migrator.CheckVersion)
+
+	return &storage{
+		accounts:     repository.NewAccountRepository(db),
+		transactions: repository.NewTransactionRepository(db),
+		balances:     repository.NewBalanceRepository(db),
+		reconcile:    repository.NewReconciliationRepository(db),
+		interest:     repository.NewInterestRepository(db),
+		periods:      repository.NewPeriodRepository(db),
+		uow:          repository.NewUnitOfWork(db, txOptions(cfg.DB)),
+		close:        db.Close,
+	}, nil
+}
+
+func txOptions(cfg config.DBConfig) repository.TxOptions {
+	return repository.TxOptions{
+		Isolation:       repository.IsolationLevel(cfg.Isolation),
+		MaxRetries:      cfg.TxMaxRetries,
+		RetryBackoff:    cfg.TxRetryBackoff,
+		MaxRetryBackoff: cfg.TxMaxRetryBackoff,
+	}
+}
+
+func settlementAccountIDs(cfg config.SettlementConfig) []int64 {
+	return slices.Sorted(maps.Values(cfg.AccountIDs()))
+}
+
+func mintPolicy(cfg config.MintConfig) service.MintPolicy {
+	return service.MintPolicy{AccountID: cfg.AccountID, AllowedCallers: cfg.AllowedCallers}
+}
+
+func accountIDPolicy(cfg config.AccountsConfig) service.AccountIDPolicy {
+	return service.AccountIDPolicy{CallerChosen: cfg.CallerIDs}
+}
+
+// ensureSystemAccounts opens the seeded accounts, which migrations already provide in Postgres, and the
+// configured mint and settlement accounts with a zero balance unless they already exist with their type.
+func ensureSystemAccounts(ctx context.Context, accounts repository.AccountRepository, cfg config.Config) error {
+	for _, acc := range model.SeededAccounts {
+		err := accounts.CreateAccount(ctx, &acc)
+		if err != nil && !errors.Is(err, domain.ErrAccountDuplicate) {
+			return fmt.Errorf("open seeded account %d: %w", acc.AccountID, err)
+		}
+	}
+
+	var configured []model.Account
+	if cfg.Mint.AccountID != 0 {
+		configured = append(configured, model.Account{AccountID: cfg.Mint.AccountID, Type: model.AccountSystem})
+	}
+	for _, id := range settlementAccountIDs(cfg.Settlement) {
+		configured = append(configured, model.Account{AccountID: id, Type: model.AccountSettlement})
+	}
+	for _, acc := range configured {
+		err := accounts.CreateAccount(ctx, &acc)
+		if errors.Is(err, domain.ErrAccountDuplicate) {
+			err = ensureAccountType(ctx, accounts, acc)
+		}
+		if err != nil {
+			return fmt.Errorf("open system account %d: %w", acc.AccountID, err)
+		}
+	}
+	return nil
+}
+
+// ensureAccountType checks that the existing account configured as want has its type. Reclassifying it would
+// let whoever owns it overdraw it, so an account of another type, like an unused configured account from
+// before account types, is left for an operator to resolve.
+func ensureAccountType(ctx context.Context, accounts repository.AccountRepository, want model.Account) error {
+	acc, err := accounts.GetAccount(ctx, want.AccountID)
+	if err != nil || acc.Type == want.Type {
+		return err
+	}
+	return fmt.Errorf("account already exists as a %s account with balance %.2f, not a %s account",
+		acc.Type, acc.Balance, want.Type)
+}